@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// runningInActions reports whether the plugin runs inside a GitHub Actions job.
+func runningInActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// writeActionsOutputs exports the release as step outputs through
+// $GITHUB_OUTPUT and renders the uploaded assets into $GITHUB_STEP_SUMMARY.
+// The success output tells later steps whether every step of the release
+// succeeded. Either file is skipped when its variable is unset.
+func writeActionsOutputs(ctx context.Context, resp *plugin.ExecuteResponse) error {
+	assets := make(map[string]string, len(resp.Artifacts))
+	for _, a := range resp.Artifacts {
+		assets[a.Name] = a.Path
+	}
+	assetsJSON, err := json.Marshal(assets)
+	if err != nil {
+		return fmt.Errorf("failed to encode assets output: %w", err)
+	}
+
+	if path := os.Getenv("GITHUB_OUTPUT"); path != "" {
+		var b strings.Builder
+		if err := writeActionsOutput(&b, "success", fmt.Sprint(resp.Success)); err != nil {
+			return err
+		}
+		for _, key := range []string{"release_id", "release_url", "tag_name", "upload_url", "body_truncated"} {
+			value, ok := resp.Outputs[key]
+			if !ok {
+				continue
+			}
+			if err := writeActionsOutput(&b, key, fmt.Sprint(value)); err != nil {
+				return err
+			}
+		}
+		if releases, ok := resp.Outputs["releases"]; ok {
+			releasesJSON, err := json.Marshal(releases)
+			if err != nil {
+				return fmt.Errorf("failed to encode releases output: %w", err)
+			}
+			if err := writeActionsOutput(&b, "releases", string(releasesJSON)); err != nil {
+				return err
+			}
+		}
+		if err := writeActionsOutput(&b, "assets", string(assetsJSON)); err != nil {
+			return err
+		}
+		if err := appendToFile(path, b.String()); err != nil {
+			return fmt.Errorf("failed to write GITHUB_OUTPUT: %w", err)
+		}
+	}
+
+	if path := os.Getenv("GITHUB_STEP_SUMMARY"); path != "" {
+		if err := appendToFile(path, renderStepSummary(resp)); err != nil {
+			return fmt.Errorf("failed to write GITHUB_STEP_SUMMARY: %w", err)
+		}
+	}
+
+	loggerFromContext(ctx).Debug("wrote GitHub Actions outputs", "assets", len(resp.Artifacts))
+	return nil
+}
+
+// writeActionsOutput writes a single output in the $GITHUB_OUTPUT format,
+// using a random heredoc delimiter for multi-line values.
+func writeActionsOutput(b *strings.Builder, key, value string) error {
+	if !strings.ContainsAny(value, "\r\n") {
+		fmt.Fprintf(b, "%s=%s\n", key, value)
+		return nil
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("failed to generate output delimiter: %w", err)
+	}
+	delimiter := "ghadelimiter_" + hex.EncodeToString(buf)
+	fmt.Fprintf(b, "%s<<%s\n%s\n%s\n", key, delimiter, value, delimiter)
+	return nil
+}
+
+// renderStepSummary renders a Markdown summary of the release and its assets.
+func renderStepSummary(resp *plugin.ExecuteResponse) string {
+	if results, ok := resp.Outputs["releases"].(map[string]componentResult); ok {
+		components := make([]string, 0, len(results))
+		for component := range results {
+			components = append(components, component)
+		}
+		sort.Strings(components)
+
+		var b strings.Builder
+		for _, component := range components {
+			r := results[component]
+			if !r.Success {
+				fmt.Fprintf(&b, "### %s failed\n\n%s\n\n", component, r.Error)
+				continue
+			}
+			fmt.Fprintf(&b, "### %s release %s", component, renderReleaseSummary(r.Tag, r.ReleaseURL, r.Artifacts))
+		}
+		return b.String()
+	}
+
+	tag, _ := resp.Outputs["tag_name"].(string)
+	releaseURL, _ := resp.Outputs["release_url"].(string)
+	summary := "### GitHub release " + renderReleaseSummary(tag, releaseURL, resp.Artifacts)
+	if !resp.Success {
+		summary += fmt.Sprintf("**Failed:** %s\n\n", resp.Error)
+	}
+	return summary
+}
+
+// renderReleaseSummary renders a Markdown link to the release followed by a
+// table of its assets.
+func renderReleaseSummary(tag, releaseURL string, artifacts []plugin.Artifact) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s](%s)\n\n", tag, releaseURL)
+
+	if len(artifacts) == 0 {
+		b.WriteString("No assets uploaded.\n\n")
+		return b.String()
+	}
+
+	b.WriteString("| Asset | Size | SHA-256 |\n")
+	b.WriteString("|-------|-----:|---------|\n")
+	for _, a := range artifacts {
+		checksum := "-"
+		if a.Checksum != "" {
+			checksum = "`" + a.Checksum + "`"
+		}
+		fmt.Fprintf(&b, "| [%s](%s) | %s | %s |\n", escapeTableCell(a.Name), a.Path, formatBytes(a.Size), checksum)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// escapeTableCell escapes characters that would break a Markdown table cell.
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// formatBytes formats a byte count with binary units, e.g. "1.5 MiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// appendToFile appends content to the file at path, creating it if needed.
+func appendToFile(path, content string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+
+	"github.com/relicta-tech/plugin-github/internal/ghfake"
+)
+
+func actionsTestResponse() *plugin.ExecuteResponse {
+	return &plugin.ExecuteResponse{
+		Success: true,
+		Outputs: map[string]any{
+			"release_id":  int64(42),
+			"release_url": "https://github.com/owner/repo/releases/tag/v1.0.0",
+			"tag_name":    "v1.0.0",
+			"upload_url":  "https://uploads.github.com/repos/owner/repo/releases/42/assets{?name,label}",
+		},
+		Artifacts: []plugin.Artifact{
+			{
+				Name:     "app_linux.tar.gz",
+				Path:     "https://github.com/owner/repo/releases/download/v1.0.0/app_linux.tar.gz",
+				Size:     1572864,
+				Checksum: "abc123",
+			},
+		},
+	}
+}
+
+// TestWriteActionsOutputs tests writing GITHUB_OUTPUT and GITHUB_STEP_SUMMARY.
+func TestWriteActionsOutputs(t *testing.T) {
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "output")
+	summaryPath := filepath.Join(dir, "summary")
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+
+	if err := writeActionsOutputs(context.Background(), actionsTestResponse()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	for _, line := range []string{
+		"success=true",
+		"release_id=42",
+		"release_url=https://github.com/owner/repo/releases/tag/v1.0.0",
+		"tag_name=v1.0.0",
+		"upload_url=https://uploads.github.com/repos/owner/repo/releases/42/assets{?name,label}",
+		`assets={"app_linux.tar.gz":"https://github.com/owner/repo/releases/download/v1.0.0/app_linux.tar.gz"}`,
+	} {
+		if !strings.Contains(string(output), line+"\n") {
+			t.Errorf("expected output line %q in:\n%s", line, output)
+		}
+	}
+
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("failed to read summary file: %v", err)
+	}
+	expected := "| [app_linux.tar.gz](https://github.com/owner/repo/releases/download/v1.0.0/app_linux.tar.gz) | 1.5 MiB | `abc123` |"
+	if !strings.Contains(string(summary), expected) {
+		t.Errorf("expected summary row %q in:\n%s", expected, summary)
+	}
+}
+
+// TestWriteActionsOutputMultiline tests the heredoc format for multi-line values.
+func TestWriteActionsOutputMultiline(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	if err := writeActionsOutput(&b, "notes", "line one\nline two"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "notes<<ghadelimiter_") {
+		t.Fatalf("unexpected heredoc output: %q", b.String())
+	}
+	if delimiter := strings.TrimPrefix(lines[0], "notes<<"); lines[3] != delimiter {
+		t.Errorf("expected closing delimiter %q, got %q", delimiter, lines[3])
+	}
+}
+
+// TestExecuteActionsOutputsDisabled tests that outputs are not written when disabled or in dry run.
+func TestExecuteActionsOutputsDisabled(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "output")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+
+	p := &GitHubPlugin{}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"owner":           "test-owner",
+			"repo":            "test-repo",
+			"token":           "ghp_test_token",
+			"actions_outputs": false,
+			"log_level":       "off",
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"},
+		DryRun:  true,
+	}
+
+	if _, err := p.Execute(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Errorf("expected no GITHUB_OUTPUT file, got err=%v", err)
+	}
+}
+
+// TestExecuteActionsOutputsPartialFailure tests that a published release is
+// reported with success=false when a later step fails.
+func TestExecuteActionsOutputsPartialFailure(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.InjectFault(ghfake.Fault{Method: "POST", Path: "/assets", Status: http.StatusUnprocessableEntity})
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "output")
+	summaryPath := filepath.Join(dir, "summary")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+	assets := writeAssets(t, map[string]string{"app.tar.gz": "binary"})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets":               []any{assets + "/app.tar.gz"},
+			"asset_failure_policy": AssetFailureFail,
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success {
+		t.Fatal("expected the asset failure to fail the hook")
+	}
+
+	output, _ := os.ReadFile(outputPath)
+	if !strings.Contains(string(output), "success=false\n") || !strings.Contains(string(output), "release_url="+resp.Outputs["release_url"].(string)+"\n") {
+		t.Errorf("expected success=false and the release URL in:\n%s", output)
+	}
+	summary, _ := os.ReadFile(summaryPath)
+	if !strings.Contains(string(summary), "**Failed:**") {
+		t.Errorf("expected the failure in the summary:\n%s", summary)
+	}
+}
+
+// TestFormatBytes tests human-readable byte sizes.
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int64]string{
+		0:          "0 B",
+		1023:       "1023 B",
+		1024:       "1.0 KiB",
+		1572864:    "1.5 MiB",
+		5368709120: "5.0 GiB",
+	}
+	for n, expected := range tests {
+		if got := formatBytes(n); got != expected {
+			t.Errorf("formatBytes(%d): expected %q, got %q", n, expected, got)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Default announcement templates.
+const (
+	defaultAnnouncementTitle = "{{.Name}}"
+	defaultAnnouncementBody  = "{{.ReleaseNotes}}\n\n{{.ReleaseURL}}"
+)
+
+// AnnouncementConfig configures a release announcement posted to GitHub
+// Discussions through the GraphQL API.
+type AnnouncementConfig struct {
+	// Enabled turns on the announcement in the post-publish hook.
+	Enabled bool `json:"enabled"`
+	// Owner and Repo select the repository to post in; they default to the
+	// release repository.
+	Owner string `json:"owner,omitempty"`
+	Repo  string `json:"repo,omitempty"`
+	// Category is the discussion category name or slug.
+	Category string `json:"category,omitempty"`
+	// Title and Body are templates rendered with the release data.
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+	// Pin pins the discussion to the repository.
+	Pin bool `json:"pin"`
+	// Lock locks the discussion so only maintainers can comment.
+	Lock bool `json:"lock"`
+}
+
+// discussionTarget is a resolved repository and category to post in.
+type discussionTarget struct {
+	client       *graphqlClient
+	owner, repo  string
+	repositoryID string
+	categoryID   string
+}
+
+// discussionCategory is a discussion category returned by GraphQL.
+type discussionCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+const repositoryCategoriesQuery = `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    id
+    discussionCategories(first: 100) { nodes { id name slug } }
+  }
+}`
+
+const createDiscussionMutation = `mutation($input: CreateDiscussionInput!) {
+  createDiscussion(input: $input) { discussion { id number url } }
+}`
+
+const pinDiscussionMutation = `mutation($input: PinDiscussionInput!) {
+  pinDiscussion(input: $input) { discussion { id } }
+}`
+
+const lockDiscussionMutation = `mutation($input: LockLockableInput!) {
+  lockLockable(input: $input) { lockedRecord { locked } }
+}`
+
+// parseAnnouncementConfig parses the announcement section of the configuration.
+func parseAnnouncementConfig(raw map[string]any) AnnouncementConfig {
+	parser := helpers.NewConfigParser(raw)
+	return AnnouncementConfig{
+		Enabled:  parser.GetBool("enabled", false),
+		Owner:    parser.GetString("owner", "", ""),
+		Repo:     parser.GetString("repo", "", ""),
+		Category: parser.GetString("category", "", ""),
+		Title:    parser.GetString("title", "", defaultAnnouncementTitle),
+		Body:     parser.GetString("body", "", defaultAnnouncementBody),
+		Pin:      parser.GetBool("pin", false),
+		Lock:     parser.GetBool("lock", false),
+	}
+}
+
+// validateAnnouncementConfig validates the announcement section of the
+// configuration. When the target repository is known and a token is
+// available, the category is looked up so a typo fails before any release
+// is created.
+func (p *GitHubPlugin) validateAnnouncementConfig(ctx context.Context, vb *helpers.ValidationBuilder, cfg *Config, raw map[string]any) {
+	if raw == nil {
+		return
+	}
+
+	ann := parseAnnouncementConfig(raw)
+	validateTemplate(vb, "announcement.title", ann.Title, templateData{})
+	validateTemplate(vb, "announcement.body", ann.Body, templateData{})
+
+	if !ann.Enabled {
+		return
+	}
+	if ann.Category == "" {
+		vb.AddError("announcement.category", "category is required when announcement is enabled")
+		return
+	}
+
+	owner, repo := announcementRepository(cfg, plugin.ReleaseContext{})
+	if owner == "" || repo == "" || cfg.Token == "" {
+		return
+	}
+	if _, err := p.resolveDiscussionTarget(ctx, cfg, owner, repo); err != nil {
+		vb.AddError("announcement.category", err.Error())
+	}
+}
+
+// announcementRepository returns the repository to post the announcement in.
+func announcementRepository(cfg *Config, releaseCtx plugin.ReleaseContext) (string, string) {
+	owner, repo := resolveRepository(cfg, releaseCtx)
+	if cfg.Announcement.Owner != "" {
+		owner = cfg.Announcement.Owner
+	}
+	if cfg.Announcement.Repo != "" {
+		repo = cfg.Announcement.Repo
+	}
+	return owner, repo
+}
+
+// resolveDiscussionTarget looks up the repository and category IDs to post in.
+func (p *GitHubPlugin) resolveDiscussionTarget(ctx context.Context, cfg *Config, owner, repo string) (*discussionTarget, error) {
+	client := p.clientFactory(ctx, cfg).newGraphQLClient(cfg.Token, cfg.BaseURL)
+
+	var data struct {
+		Repository struct {
+			ID                   string `json:"id"`
+			DiscussionCategories struct {
+				Nodes []discussionCategory `json:"nodes"`
+			} `json:"discussionCategories"`
+		} `json:"repository"`
+	}
+	vars := map[string]any{"owner": owner, "name": repo}
+	if err := client.query(ctx, repositoryCategoriesQuery, vars, &data); err != nil {
+		return nil, fmt.Errorf("failed to look up discussion categories of %s/%s: %w", owner, repo, err)
+	}
+
+	category := cfg.Announcement.Category
+	names := make([]string, 0, len(data.Repository.DiscussionCategories.Nodes))
+	for _, c := range data.Repository.DiscussionCategories.Nodes {
+		if strings.EqualFold(c.Name, category) || strings.EqualFold(c.Slug, category) {
+			return &discussionTarget{
+				client:       client,
+				owner:        owner,
+				repo:         repo,
+				repositoryID: data.Repository.ID,
+				categoryID:   c.ID,
+			}, nil
+		}
+		names = append(names, c.Name)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("discussion category %q not found in %s/%s (discussions may be disabled)", category, owner, repo)
+	}
+	return nil, fmt.Errorf("discussion category %q not found in %s/%s (available: %s)", category, owner, repo, strings.Join(names, ", "))
+}
+
+// announceRelease posts the announcement for a published release and adds
+// the discussion to resp.
+func (p *GitHubPlugin) announceRelease(ctx context.Context, cfg *Config, target *discussionTarget, data templateData, resp *plugin.ExecuteResponse, dryRun bool) error {
+	logger := loggerFromContext(ctx).With("discussion_repo", target.owner+"/"+target.repo)
+
+	title, err := renderTemplate("announcement.title", cfg.Announcement.Title, data)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("announcement title is empty")
+	}
+	body, err := renderTemplate("announcement.body", cfg.Announcement.Body, data)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		logger.Info("dry run, skipping announcement", "title", title)
+		resp.Outputs["discussion_title"] = title
+		resp.Outputs["discussion_body"] = body
+		return nil
+	}
+
+	var created struct {
+		CreateDiscussion struct {
+			Discussion struct {
+				ID     string `json:"id"`
+				Number int    `json:"number"`
+				URL    string `json:"url"`
+			} `json:"discussion"`
+		} `json:"createDiscussion"`
+	}
+	input := map[string]any{
+		"repositoryId": target.repositoryID,
+		"categoryId":   target.categoryID,
+		"title":        title,
+		"body":         body,
+	}
+	if err := target.client.query(ctx, createDiscussionMutation, map[string]any{"input": input}, &created); err != nil {
+		return err
+	}
+
+	discussion := created.CreateDiscussion.Discussion
+	resp.Outputs["discussion_url"] = discussion.URL
+	resp.Outputs["discussion_number"] = discussion.Number
+	logger.Info("created discussion", "number", discussion.Number, "url", discussion.URL)
+
+	if cfg.Announcement.Pin {
+		// Pinning is best effort: it needs admin rights and is not available
+		// on every GitHub Enterprise Server version.
+		input := map[string]any{"discussionId": discussion.ID}
+		pinErr := target.client.query(ctx, pinDiscussionMutation, map[string]any{"input": input}, nil)
+		if pinErr != nil {
+			logger.Warn("failed to pin discussion", "number", discussion.Number, "error", pinErr)
+		}
+		resp.Outputs["discussion_pinned"] = pinErr == nil
+	}
+
+	if cfg.Announcement.Lock {
+		// Locking is best effort like pinning: the discussion is already
+		// posted and a rerun would post it again
+		input := map[string]any{"lockableId": discussion.ID}
+		lockErr := target.client.query(ctx, lockDiscussionMutation, map[string]any{"input": input}, nil)
+		if lockErr != nil {
+			logger.Warn("failed to lock discussion", "number", discussion.Number, "error", lockErr)
+		}
+		resp.Outputs["discussion_locked"] = lockErr == nil
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// TestGraphQLURL tests derivation of the GraphQL endpoint.
+func TestGraphQLURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                githubGraphQLURL,
+		"https://ghe.example.com/api/v3/": "https://ghe.example.com/api/graphql",
+		"https://ghe.example.com/api/v3":  "https://ghe.example.com/api/graphql",
+		"https://ghe.example.com/":        "https://ghe.example.com/api/graphql",
+	}
+	for base, expected := range tests {
+		if got := graphqlURL(base); got != expected {
+			t.Errorf("graphqlURL(%q) = %q, expected %q", base, got, expected)
+		}
+	}
+}
+
+// TestExecuteAnnouncement tests posting an announcement to another repository.
+func TestExecuteAnnouncement(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.AddDiscussionCategory("test-owner", "community", "Announcements")
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"announcement": map[string]any{
+				"enabled":  true,
+				"repo":     "community",
+				"category": "announcements",
+				"title":    "{{.Repo}} {{.Tag}} is out",
+				"body":     "{{.ReleaseNotes}}\n\nDownload: {{.ReleaseURL}}",
+				"pin":      true,
+				"lock":     true,
+			},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: "New things"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	discussions := fake.Discussions("test-owner", "community")
+	if len(discussions) != 1 {
+		t.Fatalf("expected 1 discussion, got %d", len(discussions))
+	}
+	d := discussions[0]
+	if d.Title != "test-repo v1.2.0 is out" {
+		t.Errorf("unexpected title %q", d.Title)
+	}
+	if !strings.Contains(d.Body, "New things") || !strings.Contains(d.Body, resp.Outputs["release_url"].(string)) {
+		t.Errorf("unexpected body %q", d.Body)
+	}
+	if !d.Pinned || !d.Locked {
+		t.Errorf("expected pinned and locked discussion, got %+v", d)
+	}
+	if resp.Outputs["discussion_url"] != d.URL || resp.Outputs["discussion_pinned"] != true || resp.Outputs["discussion_locked"] != true {
+		t.Errorf("unexpected outputs %v", resp.Outputs)
+	}
+}
+
+// TestExecuteAnnouncementMissingCategory tests that an unknown category fails before the release is created.
+func TestExecuteAnnouncementMissingCategory(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.AddDiscussionCategory("test-owner", "test-repo", "General")
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"announcement": map[string]any{"enabled": true, "category": "Announcements"},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "available: General") {
+		t.Errorf("expected missing category error, got %+v", resp)
+	}
+	if n := len(fake.Releases("test-owner", "test-repo")); n != 0 {
+		t.Errorf("expected no release to be created, got %d", n)
+	}
+}
+
+// TestExecuteAnnouncementDryRun tests that a dry run renders the announcement without posting it.
+func TestExecuteAnnouncementDryRun(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.AddDiscussionCategory("test-owner", "test-repo", "Announcements")
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:   plugin.HookPostPublish,
+		DryRun: true,
+		Config: fakeConfig(fake, map[string]any{
+			"announcement": map[string]any{"enabled": true, "category": "Announcements"},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: "Notes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if resp.Outputs["discussion_title"] != "Release 1.2.0" {
+		t.Errorf("unexpected title %v", resp.Outputs["discussion_title"])
+	}
+	if len(fake.Discussions("test-owner", "test-repo")) != 0 {
+		t.Error("expected no discussion in dry run")
+	}
+}
+
+// TestValidateAnnouncement tests validation of the announcement section.
+func TestValidateAnnouncement(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.AddDiscussionCategory("test-owner", "test-repo", "General")
+
+	tests := []struct {
+		name         string
+		announcement map[string]any
+		expectField  string
+	}{
+		{
+			name:         "valid",
+			announcement: map[string]any{"enabled": true, "category": "general"},
+		},
+		{
+			name:         "missing category",
+			announcement: map[string]any{"enabled": true},
+			expectField:  "announcement.category",
+		},
+		{
+			name:         "unknown category",
+			announcement: map[string]any{"enabled": true, "category": "Announcements"},
+			expectField:  "announcement.category",
+		},
+		{
+			name:         "bad template",
+			announcement: map[string]any{"title": "{{.Nope}}"},
+			expectField:  "announcement.title",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &GitHubPlugin{}
+			resp, err := p.Validate(context.Background(), fakeConfig(fake, map[string]any{"announcement": tt.announcement}))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectField == "" {
+				if !resp.Valid {
+					t.Errorf("expected valid config, got %+v", resp.Errors)
+				}
+				return
+			}
+			if resp.Valid || resp.Errors[0].Field != tt.expectField {
+				t.Errorf("expected error on %s, got %+v", tt.expectField, resp.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// expandAssetPatterns expands the configured asset glob patterns. Patterns
+// without matches are kept as literal paths so a missing file is reported
+// by the upload.
+func expandAssetPatterns(ctx context.Context, patterns []string) []string {
+	logger := loggerFromContext(ctx)
+
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			// Invalid pattern, skip
+			logger.Warn("skipping invalid asset pattern", "pattern", pattern, "error", err)
+			continue
+		}
+
+		// If no matches found and pattern has no wildcards, treat as literal path
+		if len(matches) == 0 {
+			logger.Debug("asset pattern matched no files, treating as literal path", "pattern", pattern)
+			matches = []string{pattern}
+		}
+		paths = append(paths, matches...)
+	}
+	return paths
+}
+
+// plannedArtifacts returns the artifacts a release would have after
+// uploading the configured local assets, with their predicted download URLs.
+// It is used to render package manifests in dry-run.
+func plannedArtifacts(ctx context.Context, cfg *Config, owner, repo, tag string) []plugin.Artifact {
+	var artifacts []plugin.Artifact
+	for _, path := range expandAssetPatterns(ctx, cfg.Assets) {
+		file, err := os.Open(path)
+		if err != nil {
+			loggerFromContext(ctx).Debug("skipping unreadable asset", "path", path, "error", err)
+			continue
+		}
+		info, err := file.Stat()
+		if err == nil && info.IsDir() {
+			err = fmt.Errorf("asset path is a directory")
+		}
+		var checksum string
+		if err == nil {
+			checksum, err = hashFile(file)
+		}
+		_ = file.Close()
+		if err != nil {
+			loggerFromContext(ctx).Debug("skipping unreadable asset", "path", path, "error", err)
+			continue
+		}
+
+		artifacts = append(artifacts, plugin.Artifact{
+			Name:     info.Name(),
+			Path:     releaseDownloadURL(cfg, owner, repo, tag, info.Name()),
+			Type:     "url",
+			Size:     info.Size(),
+			Checksum: checksum,
+		})
+	}
+	return artifacts
+}
+
+// webURL returns the web URL of the GitHub instance, derived from base_url
+// for GitHub Enterprise Server.
+func webURL(cfg *Config) string {
+	if cfg.BaseURL == "" {
+		return "https://github.com"
+	}
+	base := strings.TrimSuffix(cfg.BaseURL, "/")
+	return strings.TrimSuffix(base, "/api/v3")
+}
+
+// releaseDownloadURL returns the browser download URL of a release asset.
+func releaseDownloadURL(cfg *Config, owner, repo, tag, name string) string {
+	return fmt.Sprintf("%s/%s/%s/releases/download/%s/%s",
+		webURL(cfg), owner, repo, url.PathEscape(tag), url.PathEscape(name))
+}
+
+// Asset platforms detected from file names.
+const (
+	platformDarwin  = "darwin"
+	platformLinux   = "linux"
+	platformWindows = "windows"
+
+	archAMD64 = "amd64"
+	archARM64 = "arm64"
+	arch386   = "386"
+)
+
+// archiveExtensions are the extensions of installable archives.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".tar.xz", ".tar.bz2", ".zip"}
+
+// classifyAsset detects the OS and architecture of an archive from its file
+// name, e.g. "app_1.0.0_darwin_arm64.tar.gz". It returns empty strings for
+// files that are not archives or name no known OS and architecture.
+func classifyAsset(name string) (string, string) {
+	lower := strings.ToLower(name)
+
+	isArchive := false
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			isArchive = true
+			lower = strings.TrimSuffix(lower, ext)
+			break
+		}
+	}
+	if !isArchive {
+		return "", ""
+	}
+
+	tokens := strings.FieldsFunc(lower, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+	has := func(words ...string) bool {
+		for _, t := range tokens {
+			for _, w := range words {
+				if t == w {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var osName string
+	switch {
+	case has("darwin", "macos", "mac", "osx", "apple"):
+		osName = platformDarwin
+	case has("linux"):
+		osName = platformLinux
+	case has("windows", "win", "win64", "win32"):
+		osName = platformWindows
+	default:
+		return "", ""
+	}
+
+	// "x86_64" splits into "x86" and "64"
+	x8664 := false
+	for i := 0; i+1 < len(tokens); i++ {
+		x8664 = x8664 || tokens[i] == "x86" && tokens[i+1] == "64"
+	}
+
+	var arch string
+	switch {
+	case has("arm64", "aarch64"):
+		arch = archARM64
+	case has("amd64", "x64", "win64") || x8664:
+		arch = archAMD64
+	case has("386", "i386", "x86", "win32"):
+		arch = arch386
+	default:
+		// Other architectures, e.g. armv7 or riscv64, are not packaged
+		return "", ""
+	}
+	return osName, arch
+}
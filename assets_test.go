@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// TestClassifyAsset tests OS and architecture detection from asset names.
+func TestClassifyAsset(t *testing.T) {
+	tests := []struct {
+		name, os, arch string
+	}{
+		{"app_1.0.0_darwin_arm64.tar.gz", platformDarwin, archARM64},
+		{"app-1.0.0-macos-x86_64.tar.gz", platformDarwin, archAMD64},
+		{"app_linux_amd64.tgz", platformLinux, archAMD64},
+		{"app_linux_aarch64.tar.xz", platformLinux, archARM64},
+		{"app_windows_amd64.zip", platformWindows, archAMD64},
+		{"app_windows_386.zip", platformWindows, arch386},
+		{"app_windows_x86.zip", platformWindows, arch386},
+		{"app_windows_x64.zip", platformWindows, archAMD64},
+		{"app_linux_armv7.tar.gz", "", ""},
+		{"app_linux_arm.tar.gz", "", ""},
+		{"app_linux_riscv64.tar.gz", "", ""},
+		{"app_darwin.tar.gz", "", ""},
+		{"app_linux_amd64.deb", "", ""},
+		{"checksums.txt", "", ""},
+		{"app_source.tar.gz", "", ""},
+	}
+
+	for _, tt := range tests {
+		osName, arch := classifyAsset(tt.name)
+		if osName != tt.os || arch != tt.arch {
+			t.Errorf("classifyAsset(%q) = %q, %q; expected %q, %q", tt.name, osName, arch, tt.os, tt.arch)
+		}
+	}
+}
+
+// TestReleaseDownloadURL tests download URLs for github.com and Enterprise Server.
+func TestReleaseDownloadURL(t *testing.T) {
+	if got := releaseDownloadURL(&Config{}, "o", "r", "v1.0.0", "app.zip"); got != "https://github.com/o/r/releases/download/v1.0.0/app.zip" {
+		t.Errorf("unexpected URL %q", got)
+	}
+	cfg := &Config{BaseURL: "https://ghe.example.com/api/v3/"}
+	if got := releaseDownloadURL(cfg, "o", "r", "v1.0.0", "app.zip"); got != "https://ghe.example.com/o/r/releases/download/v1.0.0/app.zip" {
+		t.Errorf("unexpected URL %q", got)
+	}
+}
+
+// TestPlannedArtifacts tests that dry-run artifacts are hashed from local files.
+func TestPlannedArtifacts(t *testing.T) {
+	dir := writeAssets(t, map[string]string{"app_linux_amd64.tar.gz": "binary"})
+	cfg := &Config{Assets: []string{dir + "/*.tar.gz", dir + "/missing.zip"}}
+
+	artifacts := plannedArtifacts(context.Background(), cfg, "o", "r", "v1.0.0")
+	if len(artifacts) != 1 {
+		t.Fatalf("expected 1 artifact, got %d", len(artifacts))
+	}
+	if artifacts[0].Checksum != sha256Hex("binary") || artifacts[0].Size != 6 {
+		t.Errorf("unexpected artifact %+v", artifacts[0])
+	}
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// defaultAuditLogPath is the audit log written when no path is configured.
+const defaultAuditLogPath = "relicta-github-audit.jsonl"
+
+// AuditLogConfig configures the audit log of mutating API calls.
+type AuditLogConfig struct {
+	// Enabled turns on the audit log.
+	Enabled bool `json:"enabled"`
+	// Path is the JSON Lines file entries are appended to.
+	Path string `json:"path,omitempty"`
+	// Upload uploads the audit log as an asset of the release.
+	Upload bool `json:"upload"`
+	// Name is the asset name; it defaults to the file name of Path.
+	Name string `json:"name,omitempty"`
+}
+
+// parseAuditLogConfig parses the audit_log section of the configuration.
+func parseAuditLogConfig(raw map[string]any) AuditLogConfig {
+	parser := helpers.NewConfigParser(raw)
+	path := parser.GetString("path", "", defaultAuditLogPath)
+	return AuditLogConfig{
+		Enabled: parser.GetBool("enabled", false),
+		Path:    path,
+		Upload:  parser.GetBool("upload", false),
+		Name:    parser.GetString("name", "", filepath.Base(path)),
+	}
+}
+
+// validateAuditLogConfig validates the audit_log section of the configuration.
+func validateAuditLogConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	cfg := parseAuditLogConfig(raw)
+	if cfg.Upload && !cfg.Enabled {
+		vb.AddError("audit_log.upload", "upload requires the audit log to be enabled")
+	}
+	if strings.ContainsAny(cfg.Name, `/\`) {
+		vb.AddError("audit_log.name", "name must be a file name")
+	}
+}
+
+// auditEntry is one mutating API call in the audit log. Entries are chained
+// by the SHA-256 of the previous line so edits and removals are detectable.
+type auditEntry struct {
+	Seq        int            `json:"seq"`
+	Time       string         `json:"time"`
+	Hook       string         `json:"hook"`
+	Method     string         `json:"method"`
+	Host       string         `json:"host"`
+	Path       string         `json:"path"`
+	Query      string         `json:"query,omitempty"`
+	Mutation   string         `json:"graphql_mutation,omitempty"`
+	Status     int            `json:"status"`
+	RequestID  string         `json:"request_id,omitempty"`
+	IDs        map[string]any `json:"ids,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	DurationMS int64          `json:"duration_ms"`
+	Prev       string         `json:"prev_sha256"`
+}
+
+// auditLog appends entries to a JSON Lines file.
+type auditLog struct {
+	mu   sync.Mutex
+	file *os.File
+	hook string
+	now  func() time.Time
+	seq  int
+	prev string
+}
+
+// mutatingHook reports whether hook can make mutating API calls.
+func mutatingHook(hook plugin.Hook) bool {
+	switch hook {
+	case plugin.HookPostPublish, plugin.HookOnSuccess, plugin.HookOnError:
+		return true
+	}
+	return false
+}
+
+// openAuditLog opens the audit log at path for appending, continuing the
+// hash chain of earlier runs.
+func openAuditLog(path, hook string, now func() time.Time) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	log := &auditLog{file: file, hook: hook, now: now}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			log.seq++
+			log.prev = digestHex(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return log, nil
+}
+
+// digestHex returns the hex-encoded SHA-256 of b.
+func digestHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// record appends entry to the log, filling in the sequence, hook and chain.
+func (l *auditLog) record(entry auditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	entry.Seq = l.seq
+	entry.Hook = l.hook
+	entry.Prev = l.prev
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	l.prev = digestHex(line)
+	return nil
+}
+
+// contents returns the log file and its number of entries.
+func (l *auditLog) contents() ([]byte, int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	content, err := os.ReadFile(l.file.Name())
+	return content, l.seq, err
+}
+
+// Close closes the log file.
+func (l *auditLog) Close() error {
+	return l.file.Close()
+}
+
+type auditLogKey struct{}
+
+// withAuditLog returns a context carrying log.
+func withAuditLog(ctx context.Context, log *auditLog) context.Context {
+	return context.WithValue(ctx, auditLogKey{}, log)
+}
+
+// auditLogFromContext returns the audit log carried by ctx, or nil.
+func auditLogFromContext(ctx context.Context) *auditLog {
+	log, _ := ctx.Value(auditLogKey{}).(*auditLog)
+	return log
+}
+
+// graphQLMutation matches the first field of a GraphQL mutation.
+var graphQLMutation = regexp.MustCompile(`^mutation\b[^{]*\{\s*(\w+)`)
+
+// auditTransport records every mutating request in the audit log once,
+// after retries. Headers, and with them the token, are never recorded.
+type auditTransport struct {
+	base http.RoundTripper
+	log  *auditLog
+	now  func() time.Time
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mutation, mutating := t.mutating(req)
+	if !mutating {
+		return t.base.RoundTrip(req)
+	}
+
+	start := t.now()
+	resp, err := t.base.RoundTrip(req)
+	entry := auditEntry{
+		Time:       start.UTC().Format(time.RFC3339Nano),
+		Method:     req.Method,
+		Host:       req.URL.Host,
+		Path:       req.URL.Path,
+		Query:      req.URL.RawQuery,
+		Mutation:   mutation,
+		DurationMS: t.now().Sub(start).Milliseconds(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+		entry.RequestID = resp.Header.Get("X-GitHub-Request-Id")
+		entry.IDs = responseIDs(resp)
+	}
+	if recordErr := t.log.record(entry); recordErr != nil {
+		loggerFromContext(req.Context()).Warn("failed to write audit log", "error", recordErr)
+	}
+	return resp, err
+}
+
+// mutating reports whether req changes state and, for GraphQL, the name of
+// the mutation.
+func (t *auditTransport) mutating(req *http.Request) (string, bool) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return "", false
+	}
+	if !strings.HasSuffix(req.URL.Path, "/graphql") {
+		return "", true
+	}
+	if req.GetBody == nil {
+		return "", true
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", true
+	}
+	defer func() { _ = body.Close() }()
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return "", true
+	}
+	m := graphQLMutation.FindStringSubmatch(strings.TrimSpace(payload.Query))
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// responseIDs returns the identifiers in a JSON response: the top-level id,
+// number and node_id, or the ids of the objects a GraphQL mutation returned.
+// The body is buffered so the caller can still read it.
+func responseIDs(resp *http.Response) map[string]any {
+	if resp.Body == nil || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	var object map[string]any
+	if json.Unmarshal(body, &object) != nil {
+		return nil
+	}
+	ids := make(map[string]any)
+	for _, key := range []string{"id", "number", "node_id"} {
+		if v, ok := object[key]; ok {
+			ids[key] = v
+		}
+	}
+	if data, ok := object["data"].(map[string]any); ok {
+		for mutation, result := range data {
+			fields, _ := result.(map[string]any)
+			for field, value := range fields {
+				if obj, ok := value.(map[string]any); ok && obj["id"] != nil {
+					ids[mutation+"."+field] = obj["id"]
+				}
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+// uploadAuditLog uploads the audit log so far as an asset of the release in
+// resp. The upload itself is only recorded in the local file.
+func (p *GitHubPlugin) uploadAuditLog(ctx context.Context, cfg *Config, log *auditLog, owner, repo string, resp *plugin.ExecuteResponse) error {
+	releaseID, _ := resp.Outputs["release_id"].(int64)
+	if releaseID == 0 {
+		return nil
+	}
+	client, err := p.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	content, _, err := log.contents()
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	asset, err := uploadReleaseAssetFromReader(ctx, client, owner, repo, releaseID, cfg.AuditLog.Name, "application/jsonl", bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return fmt.Errorf("failed to upload asset %s: %w", cfg.AuditLog.Name, err)
+	}
+	resp.Artifacts = append(resp.Artifacts, plugin.Artifact{
+		Name:     cfg.AuditLog.Name,
+		Path:     asset.GetBrowserDownloadURL(),
+		Type:     "url",
+		Size:     int64(len(content)),
+		Checksum: digestHex(content),
+	})
+	resp.Outputs["audit_log_url"] = asset.GetBrowserDownloadURL()
+	return nil
+}
+
+// reportAuditLog records the audit log file in outputs.
+func reportAuditLog(resp *plugin.ExecuteResponse, log *auditLog) {
+	content, entries, err := log.contents()
+	if err != nil {
+		return
+	}
+	if resp.Outputs == nil {
+		resp.Outputs = make(map[string]any)
+	}
+	resp.Outputs["audit_log_path"] = log.file.Name()
+	resp.Outputs["audit_log_entries"] = entries
+	resp.Outputs["audit_log_sha256"] = digestHex(content)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// readAuditLog reads the audit log at path and checks its hash chain.
+func readAuditLog(t *testing.T, path string) []auditEntry {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(content, []byte("ghp_test_token")) {
+		t.Error("expected the token to be absent from the audit log")
+	}
+
+	var entries []auditEntry
+	prev := ""
+	for i, line := range bytes.Split(bytes.TrimSuffix(content, []byte("\n")), []byte("\n")) {
+		var entry auditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("invalid entry %d: %v", i, err)
+		}
+		if entry.Seq != i+1 || entry.Prev != prev {
+			t.Errorf("entry %d breaks the chain: %+v", i, entry)
+		}
+		prev = digestHex(line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestExecuteAuditLog tests recording and uploading the mutating calls of a release.
+func TestExecuteAuditLog(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"app.tar.gz": "binary"})
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets":    []any{dir + "/*"},
+			"audit_log": map[string]any{"enabled": true, "path": path, "upload": true},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	entries := readAuditLog(t, path)
+	if len(entries) != 3 || resp.Outputs["audit_log_entries"] != 3 || resp.Outputs["audit_log_path"] != path {
+		t.Fatalf("unexpected entries %+v, outputs %v", entries, resp.Outputs)
+	}
+	release := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0")
+	create := entries[0]
+	if create.Method != "POST" || !strings.HasSuffix(create.Path, "/repos/test-owner/test-repo/releases") ||
+		create.Status != 201 || create.IDs["id"] != float64(release.GetID()) || create.RequestID == "" || create.Hook != string(plugin.HookPostPublish) {
+		t.Errorf("unexpected release entry %+v", create)
+	}
+	if upload := entries[1]; upload.Query != "name=app.tar.gz" || upload.Status != 201 {
+		t.Errorf("unexpected upload entry %+v", upload)
+	}
+
+	assets := fake.Assets(release.GetID())
+	if len(assets) != 2 || assets[1].GetName() != "audit.jsonl" || resp.Outputs["audit_log_url"] != assets[1].GetBrowserDownloadURL() {
+		t.Fatalf("unexpected assets %v", assets)
+	}
+	uploaded, _ := fake.AssetContent(assets[1].GetID())
+	if strings.Count(string(uploaded), "\n") != 2 {
+		t.Errorf("expected the uploaded log to hold the entries before its own upload, got %q", uploaded)
+	}
+}
+
+// TestExecuteAuditLogChain tests that later hooks continue the chain of earlier ones.
+func TestExecuteAuditLogChain(t *testing.T) {
+	fake := newFakeGitHub(t)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := fakeConfig(fake, map[string]any{
+		"commit_status": map[string]any{"enabled": true},
+		"audit_log":     map[string]any{"enabled": true, "path": path},
+	})
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"}
+
+	p := &GitHubPlugin{}
+	for _, hook := range []plugin.Hook{plugin.HookPostPublish, plugin.HookOnSuccess} {
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{Hook: hook, Config: cfg, Context: releaseCtx})
+		if err != nil || !resp.Success {
+			t.Fatalf("%s failed: %v %+v", hook, err, resp)
+		}
+	}
+
+	entries := readAuditLog(t, path)
+	if len(entries) < 2 || entries[0].Hook != string(plugin.HookPostPublish) || entries[len(entries)-1].Hook != string(plugin.HookOnSuccess) {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
+
+// TestExecuteAuditLogSkipped tests that dry runs and read-only hooks do not
+// open the audit log.
+func TestExecuteAuditLogSkipped(t *testing.T) {
+	fake := newFakeGitHub(t)
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := fakeConfig(fake, map[string]any{"audit_log": map[string]any{"enabled": true, "path": path}})
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"}
+
+	p := &GitHubPlugin{}
+	for _, req := range []plugin.ExecuteRequest{
+		{Hook: plugin.HookPostPublish, Config: cfg, Context: releaseCtx, DryRun: true},
+		{Hook: plugin.HookPrePublish, Config: cfg, Context: releaseCtx},
+	} {
+		resp, err := p.Execute(context.Background(), req)
+		if err != nil || !resp.Success {
+			t.Fatalf("%s failed: %v %+v", req.Hook, err, resp)
+		}
+		if resp.Outputs["audit_log_path"] != nil {
+			t.Errorf("%s: unexpected audit log outputs %v", req.Hook, resp.Outputs)
+		}
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no audit log file, got %v", err)
+	}
+}
+
+// TestValidateAuditLog tests validation of the audit_log section.
+func TestValidateAuditLog(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":     "ghp_test",
+		"audit_log": map[string]any{"upload": true, "name": "logs/audit.jsonl"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 2 {
+		t.Errorf("expected upload and name errors, got %+v", resp.Errors)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Sources of the release body, tried in the order of body_precedence.
+const (
+	BodyFromFile          = "body_file"
+	BodyFromChangelogFile = "changelog_file"
+	BodyFromReleaseNotes  = "release_notes"
+	BodyFromChangelog     = "changelog"
+	BodyFromGenerated     = "generated"
+)
+
+// defaultBodyPrecedence prefers curated files over the notes from Relicta.
+var defaultBodyPrecedence = []string{BodyFromFile, BodyFromChangelogFile, BodyFromReleaseNotes, BodyFromChangelog}
+
+// changelogHeading matches a level 2 heading of a Keep-a-Changelog version,
+// e.g. "## [1.2.0] - 2024-05-01". Submatch 1 is the version.
+var changelogHeading = regexp.MustCompile(`^##\s+\[?v?([^\]\s]+)\]?`)
+
+// changelogLinkDefinition matches the link reference definitions that end a
+// Keep-a-Changelog file, e.g. "[1.2.0]: https://...".
+var changelogLinkDefinition = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+
+// validateBodyConfig validates body_file, changelog_file and body_precedence.
+func validateBodyConfig(vb *helpers.ValidationBuilder, parser *helpers.ConfigParser) {
+	validateTemplate(vb, "body_file", parser.GetString("body_file", "", ""), templateData{})
+	validateTemplate(vb, "changelog_file", parser.GetString("changelog_file", "", ""), templateData{})
+
+	seen := make(map[string]bool)
+	for _, source := range parser.GetStringSlice("body_precedence", nil) {
+		switch source {
+		case BodyFromFile, BodyFromChangelogFile, BodyFromReleaseNotes, BodyFromChangelog, BodyFromGenerated:
+		default:
+			vb.AddError("body_precedence", fmt.Sprintf("unknown body source %q", source))
+			continue
+		}
+		if seen[source] {
+			vb.AddError("body_precedence", fmt.Sprintf("duplicate body source %q", source))
+		}
+		seen[source] = true
+	}
+}
+
+// resolveBody returns the release body from the first source in
+// body_precedence that yields text, and whether GitHub should generate the
+// notes instead. Missing files and versions absent from the changelog file
+// fall through to the next source.
+func resolveBody(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext) (string, bool, error) {
+	logger := loggerFromContext(ctx)
+	owner, repo := resolveRepository(cfg, releaseCtx)
+	data := newTemplateData(owner, repo, releaseCtx, "")
+
+	for _, source := range cfg.BodyPrecedence {
+		var body string
+		switch source {
+		case BodyFromFile, BodyFromChangelogFile:
+			pathTemplate := cfg.BodyFile
+			if source == BodyFromChangelogFile {
+				pathTemplate = cfg.ChangelogFile
+			}
+			if pathTemplate == "" {
+				continue
+			}
+			path, err := renderTemplate(source, pathTemplate, data)
+			if err != nil {
+				return "", false, fmt.Errorf("failed to render %s: %w", source, err)
+			}
+			content, err := readBodyFile(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				logger.Debug("body file not found", "source", source, "path", path)
+				continue
+			}
+			if err != nil {
+				return "", false, err
+			}
+			body = content
+			if source == BodyFromChangelogFile {
+				body = changelogSection(content, releaseCtx.Version)
+			}
+		case BodyFromReleaseNotes:
+			body = releaseCtx.ReleaseNotes
+		case BodyFromChangelog:
+			body = releaseCtx.Changelog
+		case BodyFromGenerated:
+			logger.Debug("using generated release notes")
+			return "", true, nil
+		}
+
+		if strings.TrimSpace(body) != "" {
+			logger.Debug("resolved release body", "source", source)
+			return body, false, nil
+		}
+	}
+	return "", false, nil
+}
+
+// readBodyFile reads a notes file from disk. A missing file is reported as
+// fs.ErrNotExist.
+func readBodyFile(path string) (string, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	if err := helpers.ValidateAssetPath(path); err != nil {
+		return "", fmt.Errorf("invalid body file path %s: %w", path, err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read body file %s: %w", path, err)
+	}
+	return string(content), nil
+}
+
+// changelogSection extracts the section of version from a changelog in
+// Keep-a-Changelog format, without its heading. It returns "" when the
+// version has no section.
+func changelogSection(changelog, version string) string {
+	version = strings.TrimPrefix(version, "v")
+	var section []string
+	inSection, fenced := false, false
+	for _, line := range strings.Split(changelog, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+		}
+		if !fenced {
+			if m := changelogHeading.FindStringSubmatch(line); m != nil {
+				if inSection {
+					break
+				}
+				inSection = strings.EqualFold(m[1], version)
+				continue
+			}
+			if inSection && changelogLinkDefinition.MatchString(line) {
+				break
+			}
+		}
+		if inSection {
+			section = append(section, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(section, "\n"))
+}
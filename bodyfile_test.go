@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+const keepAChangelog = `# Changelog
+
+## [Unreleased]
+
+### Added
+- Something new
+
+## [1.2.0] - 2024-05-01
+
+### Fixed
+- A crash
+
+` + "```" + `
+## not a heading
+` + "```" + `
+
+## [1.1.0] - 2024-04-01
+
+### Added
+- The first feature
+
+[Unreleased]: https://github.com/o/r/compare/v1.2.0...HEAD
+[1.1.0]: https://github.com/o/r/releases/tag/v1.1.0
+`
+
+// TestChangelogSection tests extracting a version from a Keep-a-Changelog file.
+func TestChangelogSection(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.2.0", "### Fixed\n- A crash\n\n```\n## not a heading\n```"},
+		{"v1.1.0", "### Added\n- The first feature"},
+		{"1.0.0", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := changelogSection(keepAChangelog, tt.version); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestResolveBody tests the precedence of body sources.
+func TestResolveBody(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "v1.2.0.md"), []byte("curated"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "CHANGELOG.md"), []byte(keepAChangelog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: "notes", Changelog: "changes"}
+	tests := []struct {
+		name      string
+		raw       map[string]any
+		body      string
+		generated bool
+	}{
+		{"default", map[string]any{}, "notes", false},
+		{"body file", map[string]any{"body_file": dir + "/{{.Tag}}.md"}, "curated", false},
+		{"missing body file", map[string]any{"body_file": dir + "/{{.Version}}.md"}, "notes", false},
+		{"changelog file", map[string]any{"changelog_file": dir + "/CHANGELOG.md"}, "### Fixed\n- A crash\n\n```\n## not a heading\n```", false},
+		{"precedence", map[string]any{"body_file": dir + "/{{.Tag}}.md", "body_precedence": []any{"changelog", "body_file"}}, "changes", false},
+		{"generated", map[string]any{"body_file": dir + "/missing.md", "body_precedence": []any{"body_file", "generated", "release_notes"}}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := (&GitHubPlugin{}).parseConfig(tt.raw)
+			body, generated, err := resolveBody(context.Background(), cfg, releaseCtx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if body != tt.body || generated != tt.generated {
+				t.Errorf("got body %q generated %v, want %q %v", body, generated, tt.body, tt.generated)
+			}
+		})
+	}
+}
+
+// TestExecuteBodyFile tests that the release body comes from the notes file.
+func TestExecuteBodyFile(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"v1.2.0.md": "## Highlights\n\nCurated notes"})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  fakeConfig(fake, map[string]any{"body_file": dir + "/{{.Tag}}.md"}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: "notes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if body := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0").GetBody(); body != "## Highlights\n\nCurated notes" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+// TestExecuteBodyPrecedenceWithoutChangelog tests that the changelog is not
+// used as the body when body_precedence leaves it out.
+func TestExecuteBodyPrecedenceWithoutChangelog(t *testing.T) {
+	fake := newFakeGitHub(t)
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  fakeConfig(fake, map[string]any{"body_precedence": []any{"release_notes"}}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", Changelog: "changelog"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("unexpected failure: %v %+v", err, resp)
+	}
+	if body := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0").GetBody(); body != "" {
+		t.Errorf("expected an empty body, got %q", body)
+	}
+}
+
+// TestValidateBodyConfig tests validation of the body sources.
+func TestValidateBodyConfig(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":           "ghp_test",
+		"body_file":       "docs/{{.Nope}}.md",
+		"body_precedence": []any{"body_file", "readme", "body_file"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 3 {
+		t.Errorf("expected template, unknown and duplicate errors, got %+v", resp.Errors)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+const (
+	// maxReleaseBodyLength is the longest release body GitHub accepts, in
+	// characters.
+	maxReleaseBodyLength = 125000
+	// minReleaseBodyLength is the smallest configurable limit, leaving room
+	// for the truncation notice.
+	minReleaseBodyLength = 1000
+	// fullNotesAssetName is the asset holding the untruncated notes.
+	fullNotesAssetName = "RELEASE_NOTES.md"
+)
+
+// limitReleaseBody truncates the body of release to the configured limit,
+// linking to the uploaded full notes or, without them, to the changes since
+// the previous tag. It returns the original body and whether it was
+// truncated.
+func limitReleaseBody(cfg *Config, owner, repo, previous string, release *github.RepositoryRelease) (string, bool) {
+	body := release.GetBody()
+	limit := cfg.Notes.MaxLength
+	if limit <= 0 || limit > maxReleaseBodyLength {
+		limit = maxReleaseBodyLength
+	}
+	if utf8.RuneCountInString(body) <= limit {
+		return body, false
+	}
+
+	tag := release.GetTagName()
+	var link string
+	switch {
+	case uploadsFullNotes(cfg):
+		link = releaseDownloadURL(cfg, owner, repo, tag, fullNotesAssetName)
+	case previous != "":
+		link = fmt.Sprintf("%s/%s/%s/compare/%s...%s", webURL(cfg), owner, repo, url.PathEscape(previous), url.PathEscape(tag))
+	default:
+		// The first release has no previous tag; its history is the changelog
+		link = fmt.Sprintf("%s/%s/%s/commits/%s", webURL(cfg), owner, repo, url.PathEscape(tag))
+	}
+	release.Body = github.String(truncateBody(body, limit, link))
+	return body, true
+}
+
+// uploadsFullNotes reports whether truncated notes are uploaded as an
+// asset. Promoted releases copy their assets and get no new ones.
+func uploadsFullNotes(cfg *Config) bool {
+	return cfg.Notes.UploadFullNotes && cfg.PromoteFrom == ""
+}
+
+// truncateBody shortens the Markdown body to at most limit characters. It
+// cuts at a paragraph or line boundary, closes an open code fence and
+// appends a notice linking to link.
+func truncateBody(body string, limit int, link string) string {
+	const fence = "\n```"
+	notice := fmt.Sprintf("\n\n---\n\n*These release notes were truncated. [See the full changelog](%s).*", link)
+	budget := max(limit-utf8.RuneCountInString(notice)-len(fence), 0)
+
+	cut := string([]rune(body)[:budget])
+	if i := strings.LastIndex(cut, "\n\n"); i > len(cut)/2 {
+		cut = cut[:i]
+	} else if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	cut = strings.TrimRight(cut, "\n")
+
+	fenced := false
+	for _, line := range strings.Split(cut, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+		}
+	}
+	if fenced {
+		cut += fence
+	}
+	return cut + notice
+}
+
+// uploadFullNotes uploads the untruncated notes as a release asset.
+func uploadFullNotes(ctx context.Context, client *github.Client, owner, repo string, releaseID int64, notes string) (*plugin.Artifact, error) {
+	content := []byte(notes)
+	asset, err := uploadReleaseAssetFromReader(ctx, client, owner, repo, releaseID, fullNotesAssetName, "text/markdown; charset=utf-8", bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload asset %s: %w", fullNotesAssetName, err)
+	}
+
+	releaseFilesFromContext(ctx).addContent(fullNotesAssetName, "text/markdown; charset=utf-8", content)
+
+	sum := sha256.Sum256(content)
+	return &plugin.Artifact{
+		Name:     fullNotesAssetName,
+		Path:     asset.GetBrowserDownloadURL(),
+		Type:     "url",
+		Size:     int64(len(content)),
+		Checksum: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// reportTruncation records a truncated body of length characters in outputs.
+func reportTruncation(outputs map[string]any, length int) {
+	outputs["body_truncated"] = true
+	outputs["body_length"] = length
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// TestTruncateBody tests cutting at Markdown boundaries within the limit.
+func TestTruncateBody(t *testing.T) {
+	const link = "https://example.com/full"
+	notice := "\n\n---\n\n*These release notes were truncated. [See the full changelog](" + link + ").*"
+	paragraph := strings.Repeat("ü", 300)
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"paragraph", paragraph + "\n\n" + paragraph + "\n\n" + paragraph, paragraph + "\n\n" + paragraph + notice},
+		{"line", "- " + strings.Repeat("a", 400) + "\n- " + strings.Repeat("b", 600), "- " + strings.Repeat("a", 400) + notice},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateBody(tt.body, 1000, link)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	got := truncateBody("```\n"+strings.Repeat("x\n", 600), 1000, link)
+	if !strings.HasSuffix(got, "x\n```"+notice) || utf8.RuneCountInString(got) > 1000 {
+		t.Errorf("expected the code fence to be closed within the limit, got %q", got)
+	}
+}
+
+// TestExecuteTruncatesBody tests that an oversized body is truncated and uploaded in full.
+func TestExecuteTruncatesBody(t *testing.T) {
+	fake := newFakeGitHub(t)
+	notes := strings.Repeat("- fix: something went wrong\n", 200)
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"notes": map[string]any{"max_length": 1000, "upload_full_notes": true},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: notes},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if resp.Outputs["body_truncated"] != true || resp.Outputs["body_length"] != len(notes) {
+		t.Errorf("unexpected truncation outputs %v", resp.Outputs)
+	}
+
+	release := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0")
+	body := release.GetBody()
+	if len(body) > 1000 || !strings.HasPrefix(body, "- fix: something went wrong\n") || !strings.Contains(body, "/releases/download/v1.2.0/RELEASE_NOTES.md)") {
+		t.Errorf("unexpected body %q", body)
+	}
+
+	assets := fake.Assets(release.GetID())
+	if len(assets) != 1 || assets[0].GetName() != fullNotesAssetName || assets[0].GetContentType() != "text/markdown; charset=utf-8" {
+		t.Fatalf("unexpected assets %v", assets)
+	}
+	if content, _ := fake.AssetContent(assets[0].GetID()); string(content) != notes {
+		t.Error("expected the full notes in the asset")
+	}
+	if len(resp.Artifacts) != 1 || resp.Artifacts[0].Name != fullNotesAssetName {
+		t.Errorf("unexpected artifacts %v", resp.Artifacts)
+	}
+}
+
+// TestExecuteTruncatedBodyLink tests linking truncated notes to the changes
+// since the previous tag when the full notes are not uploaded.
+func TestExecuteTruncatedBodyLink(t *testing.T) {
+	notes := strings.Repeat("- fix: something went wrong\n", 200)
+	tests := []struct {
+		name     string
+		previous string
+		want     string
+	}{
+		{"previous", "1.1.0", "/test-owner/test-repo/compare/v1.1.0...v1.2.0)"},
+		{"first", "", "/test-owner/test-repo/commits/v1.2.0)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeGitHub(t)
+			p := &GitHubPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  fakeConfig(fake, map[string]any{"notes": map[string]any{"max_length": 1000}}),
+				Context: plugin.ReleaseContext{Version: "1.2.0", PreviousVersion: tt.previous, TagName: "v1.2.0", ReleaseNotes: notes},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("unexpected failure: %v %+v", err, resp)
+			}
+			if body := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0").GetBody(); !strings.HasSuffix(body, tt.want+".*") {
+				t.Errorf("expected a link ending in %s, got %q", tt.want, body)
+			}
+		})
+	}
+}
+
+// TestExecuteKeepsShortBody tests that bodies within the limit are unchanged.
+func TestExecuteKeepsShortBody(t *testing.T) {
+	fake := newFakeGitHub(t)
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  fakeConfig(fake, map[string]any{"notes": map[string]any{"upload_full_notes": true}}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: "notes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success || resp.Outputs["body_truncated"] != nil || len(resp.Artifacts) != 0 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if body := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0").GetBody(); body != "notes" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+// TestValidateNotesMaxLength tests the bounds of notes.max_length.
+func TestValidateNotesMaxLength(t *testing.T) {
+	p := &GitHubPlugin{}
+	for _, n := range []int{10, 200000} {
+		resp, err := p.Validate(context.Background(), map[string]any{
+			"token": "ghp_test",
+			"notes": map[string]any{"max_length": n},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Valid || len(resp.Errors) != 1 || resp.Errors[0].Field != "notes.max_length" {
+			t.Errorf("max_length %d: unexpected errors %+v", n, resp.Errors)
+		}
+	}
+}
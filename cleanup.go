@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// CleanupConfig configures removal of old releases after a successful release.
+type CleanupConfig struct {
+	// Enabled turns on cleanup in the on-success hook.
+	Enabled bool `json:"enabled"`
+	// KeepPrereleases is the number of prereleases kept per major.minor line (0 keeps all).
+	KeepPrereleases int `json:"keep_prereleases,omitempty"`
+	// DraftMaxAgeDays deletes drafts older than this many days (0 keeps all).
+	DraftMaxAgeDays int `json:"draft_max_age_days,omitempty"`
+	// DeleteSuperseded deletes prereleases superseded by the current stable release.
+	DeleteSuperseded bool `json:"delete_superseded"`
+	// DeleteTags also deletes the tags of removed releases.
+	DeleteTags bool `json:"delete_tags"`
+	// DryRun only lists what would be removed.
+	DryRun bool `json:"dry_run"`
+}
+
+// cleanupCandidate is a release selected for removal.
+type cleanupCandidate struct {
+	Release *github.RepositoryRelease
+	Reason  string
+}
+
+// parseCleanupConfig parses the cleanup section of the configuration.
+func parseCleanupConfig(raw map[string]any) CleanupConfig {
+	parser := helpers.NewConfigParser(raw)
+	return CleanupConfig{
+		Enabled:          parser.GetBool("enabled", false),
+		KeepPrereleases:  parser.GetInt("keep_prereleases", 0),
+		DraftMaxAgeDays:  parser.GetInt("draft_max_age_days", 0),
+		DeleteSuperseded: parser.GetBool("delete_superseded", false),
+		DeleteTags:       parser.GetBool("delete_tags", false),
+		DryRun:           parser.GetBool("dry_run", false),
+	}
+}
+
+// validateCleanupConfig validates the cleanup section of the configuration.
+func validateCleanupConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	if raw == nil {
+		return
+	}
+
+	cfg := parseCleanupConfig(raw)
+	if cfg.KeepPrereleases < 0 {
+		vb.AddError("cleanup.keep_prereleases", "keep_prereleases must not be negative")
+	}
+	if cfg.DraftMaxAgeDays < 0 {
+		vb.AddError("cleanup.draft_max_age_days", "draft_max_age_days must not be negative")
+	}
+	if cfg.Enabled && cfg.KeepPrereleases == 0 && cfg.DraftMaxAgeDays == 0 && !cfg.DeleteSuperseded {
+		vb.AddError("cleanup",
+			"cleanup is enabled but no rule is configured (keep_prereleases, draft_max_age_days or delete_superseded)")
+	}
+}
+
+// cleanupReleases removes releases matching the cleanup rules.
+func (p *GitHubPlugin) cleanupReleases(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	client, err := p.getClient(ctx, cfg)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create GitHub client: %v", err),
+		}, nil
+	}
+
+	owner, repo := resolveRepository(cfg, releaseCtx)
+	if owner == "" || repo == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "repository owner and name are required",
+		}, nil
+	}
+
+	releases, err := listReleases(ctx, client, owner, repo)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to list releases: %v", err),
+		}, nil
+	}
+
+	candidates := planCleanup(releases, cfg.Cleanup, releaseCtx.TagName, p.now())
+
+	planned := make([]string, len(candidates))
+	for i, c := range candidates {
+		planned[i] = fmt.Sprintf("%s (%s)", releaseLabel(c.Release), c.Reason)
+	}
+
+	if dryRun || cfg.Cleanup.DryRun {
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("Would delete %d release(s) from %s/%s", len(candidates), owner, repo),
+			Outputs: map[string]any{
+				"cleanup_candidates": planned,
+			},
+		}, nil
+	}
+
+	logger := loggerFromContext(ctx)
+
+	var deleted, failures []string
+	for _, c := range candidates {
+		if _, err := client.Repositories.DeleteRelease(ctx, owner, repo, c.Release.GetID()); err != nil {
+			logger.Error("failed to delete release", "release", releaseLabel(c.Release), "error", err)
+			failures = append(failures, fmt.Sprintf("failed to delete release %s: %v", releaseLabel(c.Release), err))
+			continue
+		}
+		logger.Info("deleted release", "release", releaseLabel(c.Release), "reason", c.Reason)
+		deleted = append(deleted, releaseLabel(c.Release))
+
+		// Draft releases have no tag until they are published
+		if cfg.Cleanup.DeleteTags && !c.Release.GetDraft() && c.Release.GetTagName() != "" {
+			if _, err := client.Git.DeleteRef(ctx, owner, repo, "tags/"+c.Release.GetTagName()); err != nil {
+				failures = append(failures, fmt.Sprintf("failed to delete tag %s: %v", c.Release.GetTagName(), err))
+			}
+		}
+	}
+
+	outputs := map[string]any{
+		"deleted_releases": deleted,
+	}
+
+	if len(failures) > 0 {
+		outputs["cleanup_errors"] = failures
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("cleanup failed: %s", strings.Join(failures, "; ")),
+			Outputs: outputs,
+		}, nil
+	}
+
+	return &plugin.ExecuteResponse{
+		Success: true,
+		Message: fmt.Sprintf("Deleted %d release(s) from %s/%s", len(deleted), owner, repo),
+		Outputs: outputs,
+	}, nil
+}
+
+// planCleanup selects the releases to remove. The release for currentTag and
+// published stable releases are never selected.
+func planCleanup(releases []*github.RepositoryRelease, cfg CleanupConfig, currentTag string, now time.Time) []cleanupCandidate {
+	var candidates []cleanupCandidate
+	selected := make(map[int64]bool)
+
+	add := func(r *github.RepositoryRelease, reason string) {
+		if selected[r.GetID()] {
+			return
+		}
+		selected[r.GetID()] = true
+		candidates = append(candidates, cleanupCandidate{Release: r, Reason: reason})
+	}
+
+	current, currentOK := parseVersion(currentTag)
+
+	// Drafts older than the maximum age
+	if cfg.DraftMaxAgeDays > 0 {
+		cutoff := now.Add(-time.Duration(cfg.DraftMaxAgeDays) * 24 * time.Hour)
+		for _, r := range releases {
+			if r.GetDraft() && r.GetTagName() != currentTag && r.GetCreatedAt().Before(cutoff) {
+				add(r, fmt.Sprintf("draft older than %d days", cfg.DraftMaxAgeDays))
+			}
+		}
+	}
+
+	// Collect published prereleases with parseable versions
+	type versioned struct {
+		release *github.RepositoryRelease
+		version semver
+	}
+	var prereleases []versioned
+	for _, r := range releases {
+		if r.GetDraft() || !r.GetPrerelease() || r.GetTagName() == currentTag {
+			continue
+		}
+		if v, ok := parseVersion(r.GetTagName()); ok {
+			prereleases = append(prereleases, versioned{release: r, version: v})
+		}
+	}
+
+	// Prereleases superseded by this stable release
+	if cfg.DeleteSuperseded && currentOK && current.Prerelease == "" {
+		for _, pr := range prereleases {
+			if pr.version.Prefix == current.Prefix && compareVersions(pr.version, current) < 0 {
+				add(pr.release, fmt.Sprintf("superseded by %s", currentTag))
+			}
+		}
+	}
+
+	// Keep only the newest prereleases per line
+	if cfg.KeepPrereleases > 0 {
+		lines := make(map[string][]versioned)
+		var order []string
+		for _, pr := range prereleases {
+			line := pr.version.Line()
+			if _, ok := lines[line]; !ok {
+				order = append(order, line)
+			}
+			lines[line] = append(lines[line], pr)
+		}
+		for _, line := range order {
+			group := lines[line]
+			sort.SliceStable(group, func(i, j int) bool {
+				return compareVersions(group[i].version, group[j].version) > 0
+			})
+			for _, pr := range group[min(cfg.KeepPrereleases, len(group)):] {
+				add(pr.release, fmt.Sprintf("more than %d prereleases in %s", cfg.KeepPrereleases, line))
+			}
+		}
+	}
+
+	return candidates
+}
+
+// listReleases returns all releases of a repository, following pagination.
+func listReleases(ctx context.Context, client *github.Client, owner, repo string) ([]*github.RepositoryRelease, error) {
+	var all []*github.RepositoryRelease
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		releases, resp, err := client.Repositories.ListReleases(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, releases...)
+		if resp == nil || resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// releaseLabel returns a human-readable identifier for a release.
+func releaseLabel(r *github.RepositoryRelease) string {
+	if r.GetTagName() != "" {
+		return r.GetTagName()
+	}
+	return fmt.Sprintf("release %d", r.GetID())
+}
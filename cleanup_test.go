@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func testRelease(id int64, tag string, draft, prerelease bool, created time.Time) *github.RepositoryRelease {
+	return &github.RepositoryRelease{
+		ID:         github.Int64(id),
+		TagName:    github.String(tag),
+		Draft:      github.Bool(draft),
+		Prerelease: github.Bool(prerelease),
+		CreatedAt:  &github.Timestamp{Time: created},
+	}
+}
+
+// TestPlanCleanup tests selection of releases for removal.
+func TestPlanCleanup(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-60 * 24 * time.Hour)
+	recent := now.Add(-2 * 24 * time.Hour)
+
+	releases := []*github.RepositoryRelease{
+		testRelease(1, "v1.3.0", false, false, old),
+		testRelease(2, "v1.4.0-rc.1", false, true, old),
+		testRelease(3, "v1.4.0-rc.2", false, true, old),
+		testRelease(4, "v1.4.0-rc.3", false, true, recent),
+		testRelease(5, "v1.5.0-nightly.1", false, true, old),
+		testRelease(6, "v1.5.0-nightly.2", false, true, recent),
+		testRelease(7, "v1.5.0-nightly.3", false, true, recent),
+		testRelease(8, "v9.9.9", true, false, old),
+		testRelease(9, "v9.9.10", true, false, recent),
+		testRelease(10, "v1.4.0", false, false, recent),
+	}
+
+	tests := []struct {
+		name     string
+		cfg      CleanupConfig
+		current  string
+		expected []int64
+	}{
+		{
+			name:     "keep last prereleases per line",
+			cfg:      CleanupConfig{KeepPrereleases: 2},
+			current:  "v1.4.0",
+			expected: []int64{2, 5},
+		},
+		{
+			name:     "old drafts",
+			cfg:      CleanupConfig{DraftMaxAgeDays: 30},
+			current:  "v1.4.0",
+			expected: []int64{8},
+		},
+		{
+			name:     "superseded by stable release",
+			cfg:      CleanupConfig{DeleteSuperseded: true},
+			current:  "v1.4.0",
+			expected: []int64{2, 3, 4},
+		},
+		{
+			name:     "nothing superseded by a prerelease",
+			cfg:      CleanupConfig{DeleteSuperseded: true},
+			current:  "v1.5.0-nightly.3",
+			expected: nil,
+		},
+		{
+			name:     "current prerelease is protected",
+			cfg:      CleanupConfig{KeepPrereleases: 1},
+			current:  "v1.5.0-nightly.3",
+			expected: []int64{2, 3, 5},
+		},
+		{
+			name:     "rules combine without duplicates",
+			cfg:      CleanupConfig{KeepPrereleases: 1, DeleteSuperseded: true, DraftMaxAgeDays: 30},
+			current:  "v1.4.0",
+			expected: []int64{2, 3, 4, 5, 6, 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			candidates := planCleanup(releases, tt.cfg, tt.current, now)
+
+			var ids []int64
+			for _, c := range candidates {
+				ids = append(ids, c.Release.GetID())
+				if c.Reason == "" {
+					t.Errorf("release %d selected without a reason", c.Release.GetID())
+				}
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+			if len(ids) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.expected[i] {
+					t.Fatalf("expected %v, got %v", tt.expected, ids)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateCleanupConfig tests validation of the cleanup section.
+func TestValidateCleanupConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		cleanup     map[string]any
+		expectValid bool
+	}{
+		{
+			name:        "valid rules",
+			cleanup:     map[string]any{"enabled": true, "keep_prereleases": 5, "delete_tags": true},
+			expectValid: true,
+		},
+		{
+			name:        "enabled without rules",
+			cleanup:     map[string]any{"enabled": true},
+			expectValid: false,
+		},
+		{
+			name:        "negative keep",
+			cleanup:     map[string]any{"enabled": true, "keep_prereleases": -1},
+			expectValid: false,
+		},
+		{
+			name:        "disabled without rules",
+			cleanup:     map[string]any{"enabled": false},
+			expectValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &GitHubPlugin{}
+			resp, err := p.Validate(context.Background(), map[string]any{
+				"token":   "ghp_test",
+				"cleanup": tt.cleanup,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Valid != tt.expectValid {
+				t.Errorf("expected Valid=%v, got %v (errors: %v)", tt.expectValid, resp.Valid, resp.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// userAgent identifies the plugin to the GitHub API.
+	userAgent = "relicta-plugin-github/" + pluginVersion
+	// defaultAPIVersion is the GitHub REST API version the plugin targets.
+	defaultAPIVersion = "2022-11-28"
+
+	defaultRequestTimeout = 60 * time.Second
+	defaultUploadTimeout  = time.Hour
+	defaultMaxRetries     = 3
+
+	// retryBaseDelay and retryMaxDelay bound the exponential retry backoff.
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// clock abstracts time so retry backoff and age-based rules can be tested.
+type clock interface {
+	Now() time.Time
+	// Sleep waits for d or until ctx is done.
+	Sleep(ctx context.Context, d time.Duration) error
+	// NewTicker delivers the time every d until stop is called.
+	NewTicker(d time.Duration) (ticks <-chan time.Time, stop func())
+}
+
+// systemClock is the real clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (systemClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTicker(d)
+	return t.C, t.Stop
+}
+
+// timeSource returns the plugin clock, defaulting to the system clock.
+func (p *GitHubPlugin) timeSource() clock {
+	if p.clock != nil {
+		return p.clock
+	}
+	return systemClock{}
+}
+
+// now returns the current time from the plugin clock.
+func (p *GitHubPlugin) now() time.Time {
+	if p.clock != nil {
+		return p.clock.Now()
+	}
+	return time.Now()
+}
+
+// clientFactory builds GitHub clients that share a transport, timeouts,
+// retry policy and logger. Every network call of the plugin goes through a
+// client built here.
+type clientFactory struct {
+	transport      http.RoundTripper
+	clock          clock
+	logger         hclog.Logger
+	audit          *auditLog
+	apiVersion     string
+	requestTimeout time.Duration
+	uploadTimeout  time.Duration
+	maxRetries     int
+}
+
+// clientFactory returns the factory for a plugin run.
+func (p *GitHubPlugin) clientFactory(ctx context.Context, cfg *Config) *clientFactory {
+	f := &clientFactory{
+		transport:      p.transport,
+		clock:          p.clock,
+		logger:         loggerFromContext(ctx),
+		audit:          auditLogFromContext(ctx),
+		apiVersion:     cfg.APIVersion,
+		requestTimeout: cfg.RequestTimeout,
+		uploadTimeout:  cfg.UploadTimeout,
+		maxRetries:     cfg.MaxRetries,
+	}
+	if f.transport == nil {
+		f.transport = http.DefaultTransport
+	}
+	if f.clock == nil {
+		f.clock = systemClock{}
+	}
+	if f.apiVersion == "" {
+		f.apiVersion = defaultAPIVersion
+	}
+	return f
+}
+
+// httpClient returns an HTTP client authenticating with token.
+func (f *clientFactory) httpClient(token string) *http.Client {
+	var rt http.RoundTripper = &loggingTransport{base: f.transport, logger: f.logger, now: f.clock.Now}
+	rt = &timeoutTransport{base: rt, request: f.requestTimeout, upload: f.uploadTimeout}
+	rt = &retryTransport{base: rt, clock: f.clock, logger: f.logger, maxRetries: f.maxRetries}
+	if f.audit != nil {
+		rt = &auditTransport{base: rt, log: f.audit, now: f.clock.Now}
+	}
+	rt = &headerTransport{base: rt, apiVersion: f.apiVersion}
+
+	return &http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+			Base:   rt,
+		},
+	}
+}
+
+// newClient returns a GitHub client for token, using the Enterprise Server
+// URLs when baseURL is set. uploadURL defaults to baseURL.
+func (f *clientFactory) newClient(token, baseURL, uploadURL string) (*github.Client, error) {
+	client := github.NewClient(f.httpClient(token))
+	client.UserAgent = userAgent
+
+	if baseURL != "" {
+		if uploadURL == "" {
+			uploadURL = baseURL
+		}
+		return client.WithEnterpriseURLs(baseURL, uploadURL)
+	}
+
+	return client, nil
+}
+
+// headerTransport sets the API version header on every request.
+type headerTransport struct {
+	base       http.RoundTripper
+	apiVersion string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-GitHub-Api-Version", t.apiVersion)
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// timeoutTransport bounds each request attempt, using the upload timeout for
+// asset uploads. A zero timeout disables the bound.
+type timeoutTransport struct {
+	base    http.RoundTripper
+	request time.Duration
+	upload  time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := t.request
+	if isUpload(req) {
+		timeout = t.upload
+	}
+	if timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// Keep the deadline until the body has been consumed
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// isUpload reports whether req uploads a release asset.
+func isUpload(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/assets")
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// retryTransport retries transient failures with exponential backoff.
+// Server and network errors are retried for idempotent methods, 429 responses
+// and secondary rate limits (403 with Retry-After) for all methods; requests
+// whose body cannot be replayed, such as file uploads, are never retried.
+type retryTransport struct {
+	base       http.RoundTripper
+	clock      clock
+	logger     hclog.Logger
+	maxRetries int
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxRetries || !retryable(req, resp, err) || !replayable(req) {
+			return resp, err
+		}
+
+		delay := retryDelay(attempt, resp)
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		t.logger.Warn("retrying github request",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"status", status,
+			"error", err,
+			"attempt", attempt+1,
+			"delay_ms", delay.Milliseconds())
+
+		if err := t.clock.Sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryable reports whether a response or error is worth retrying. Server
+// and network errors are only retried for idempotent methods: a POST or PATCH
+// that failed with a 502 may still have been applied, and replaying it would
+// create duplicates. Rate limits reject the request before it runs, so they
+// are retried for every method.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return idempotent(req) && req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return idempotent(req)
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("Retry-After") != ""
+	default:
+		return false
+	}
+}
+
+// idempotent reports whether sending req twice has the same effect as once.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// replayable reports whether the request body can be sent again.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryDelay returns the wait before the next attempt, honouring Retry-After.
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	delay := retryBaseDelay << attempt
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// parseDuration parses a duration option such as "30s", returning def when
+// the value is empty and an error when it is malformed or negative.
+func parseDuration(value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration must not be negative")
+	}
+	return d, nil
+}
+
+// durationOrDefault parses a duration option, falling back to def when it
+// is empty or invalid. Validate reports invalid values.
+func durationOrDefault(value string, def time.Duration) time.Duration {
+	d, err := parseDuration(value, def)
+	if err != nil {
+		return def
+	}
+	return d
+}
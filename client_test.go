@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+
+	"github.com/relicta-tech/plugin-github/internal/ghfake"
+)
+
+// fakeClock is a clock whose sleeps return immediately and are recorded.
+// Its tickers fire only when a test sends on ticks.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+	ticks  chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(_ context.Context, d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func (c *fakeClock) NewTicker(time.Duration) (<-chan time.Time, func()) {
+	return c.ticks, func() {}
+}
+
+// recordingTransport records requests before passing them to http.DefaultTransport.
+type recordingTransport struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests = append(t.requests, req)
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestClientFactoryTransport tests that the injected transport receives requests with plugin headers.
+func TestClientFactoryTransport(t *testing.T) {
+	fake := newFakeGitHub(t)
+	rt := &recordingTransport{}
+	p := &GitHubPlugin{transport: rt, clock: &fakeClock{}}
+
+	cfg := p.parseConfig(fakeConfig(fake, nil))
+	client, err := p.getClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, _, err := client.Repositories.ListReleases(context.Background(), "o", "r", nil); err != nil {
+		t.Fatalf("ListReleases: %v", err)
+	}
+
+	if len(rt.requests) != 1 {
+		t.Fatalf("expected 1 request through injected transport, got %d", len(rt.requests))
+	}
+	req := rt.requests[0]
+	if got := req.Header.Get("User-Agent"); got != "relicta-plugin-github/"+pluginVersion {
+		t.Errorf("unexpected User-Agent %q", got)
+	}
+	if got := req.Header.Get("X-GitHub-Api-Version"); got != defaultAPIVersion {
+		t.Errorf("unexpected API version %q", got)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer ghp_test_token" {
+		t.Errorf("unexpected Authorization %q", got)
+	}
+}
+
+// TestRedirectClient tests that asset downloads follow redirects through the
+// injected transport without the token.
+func TestRedirectClient(t *testing.T) {
+	fake := newFakeGitHub(t)
+	rt := &recordingTransport{}
+	p := &GitHubPlugin{transport: rt, clock: &fakeClock{}}
+
+	client, err := p.getClient(context.Background(), p.parseConfig(fakeConfig(fake, nil)))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	resp, err := redirectClient(client).Get(fake.URL() + "download/1/app.tar.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if len(rt.requests) != 1 || rt.requests[0].Header.Get("Authorization") != "" {
+		t.Errorf("expected one unauthenticated request through the injected transport, got %v", rt.requests)
+	}
+}
+
+// TestClientRetries tests retry and backoff behaviour against the fake API.
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		fault       ghfake.Fault
+		maxRetries  int
+		expectErr   bool
+		expectCalls int
+		expectSleep []time.Duration
+	}{
+		{
+			name:        "recovers from server errors",
+			fault:       ghfake.Fault{Status: http.StatusServiceUnavailable, Times: 2},
+			maxRetries:  3,
+			expectCalls: 3,
+			expectSleep: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:        "gives up after max retries",
+			fault:       ghfake.Fault{Status: http.StatusBadGateway},
+			maxRetries:  1,
+			expectErr:   true,
+			expectCalls: 2,
+			expectSleep: []time.Duration{time.Second},
+		},
+		{
+			name:        "does not retry client errors",
+			fault:       ghfake.Fault{Status: http.StatusNotFound},
+			maxRetries:  3,
+			expectErr:   true,
+			expectCalls: 1,
+		},
+		{
+			name:        "does not retry primary rate limit",
+			fault:       ghfake.Fault{RateLimited: true},
+			maxRetries:  3,
+			expectErr:   true,
+			expectCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeGitHub(t)
+			fake.InjectFault(tt.fault)
+			clk := &fakeClock{}
+			p := &GitHubPlugin{clock: clk}
+
+			cfg := p.parseConfig(fakeConfig(fake, map[string]any{"max_retries": tt.maxRetries}))
+			client, err := p.getClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			_, _, err = client.Repositories.ListReleases(context.Background(), "o", "r", nil)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			if n := fake.CountRequests("GET", "/releases"); n != tt.expectCalls {
+				t.Errorf("expected %d requests, got %d", tt.expectCalls, n)
+			}
+			if len(clk.sleeps) != len(tt.expectSleep) {
+				t.Fatalf("expected sleeps %v, got %v", tt.expectSleep, clk.sleeps)
+			}
+			for i, d := range tt.expectSleep {
+				if clk.sleeps[i] != d {
+					t.Errorf("sleep %d: expected %s, got %s", i, d, clk.sleeps[i])
+				}
+			}
+		})
+	}
+}
+
+// TestRetryDelay tests backoff growth, capping and Retry-After.
+func TestRetryDelay(t *testing.T) {
+	if d := retryDelay(2, nil); d != 4*time.Second {
+		t.Errorf("expected 4s, got %s", d)
+	}
+	if d := retryDelay(10, nil); d != retryMaxDelay {
+		t.Errorf("expected cap %s, got %s", retryMaxDelay, d)
+	}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	if d := retryDelay(0, resp); d != 7*time.Second {
+		t.Errorf("expected Retry-After 7s, got %s", d)
+	}
+}
+
+// TestClientRetriesNonIdempotent tests that writes are only retried when
+// GitHub rejected them before running them.
+func TestClientRetriesNonIdempotent(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectErr   bool
+		expectCalls int
+	}{
+		{name: "does not retry server errors", status: http.StatusBadGateway, expectErr: true, expectCalls: 1},
+		{name: "retries rate limits", status: http.StatusTooManyRequests, expectCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeGitHub(t)
+			fake.InjectFault(ghfake.Fault{Method: "POST", Status: tt.status, Times: 1})
+			p := &GitHubPlugin{clock: &fakeClock{}}
+
+			client, err := p.getClient(context.Background(), p.parseConfig(fakeConfig(fake, nil)))
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			_, _, err = client.Repositories.CreateRelease(context.Background(), "o", "r", &github.RepositoryRelease{TagName: github.String("v1.0.0")})
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error=%v, got %v", tt.expectErr, err)
+			}
+			if n := fake.CountRequests("POST", "/releases"); n != tt.expectCalls {
+				t.Errorf("expected %d requests, got %d", tt.expectCalls, n)
+			}
+		})
+	}
+}
+
+// TestClientUploadNotRetried tests that file uploads, which cannot be replayed, are not retried.
+func TestClientUploadNotRetried(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.InjectFault(ghfake.Fault{Method: "POST", Path: "/assets", Status: http.StatusServiceUnavailable, Times: 1})
+	dir := writeAssets(t, map[string]string{"app.tar.gz": "binary"})
+
+	p := &GitHubPlugin{clock: &fakeClock{}}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets":               []any{dir + "/app.tar.gz"},
+			"asset_failure_policy": AssetFailureFail,
+		}),
+		Context: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success {
+		t.Error("expected upload failure")
+	}
+	if n := fake.CountRequests("POST", "/assets"); n != 1 {
+		t.Errorf("expected a single upload attempt, got %d", n)
+	}
+}
+
+// TestClientRequestTimeout tests that request_timeout bounds slow API calls.
+func TestClientRequestTimeout(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.InjectFault(ghfake.Fault{Method: "GET", Delay: 5 * time.Second})
+
+	p := &GitHubPlugin{clock: &fakeClock{}}
+	cfg := p.parseConfig(fakeConfig(fake, map[string]any{"request_timeout": "100ms", "max_retries": 0}))
+	client, err := p.getClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	start := time.Now()
+	_, _, err = client.Repositories.ListReleases(context.Background(), "o", "r", nil)
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected request to time out promptly, took %s", elapsed)
+	}
+}
+
+// TestValidateClientOptions tests validation of timeout and retry options.
+func TestValidateClientOptions(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":           "ghp_test",
+		"request_timeout": "soon",
+		"upload_timeout":  "-1m",
+		"max_retries":     -1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 3 {
+		t.Fatalf("expected 3 validation errors, got %+v", resp.Errors)
+	}
+	for _, e := range resp.Errors {
+		if !strings.Contains("request_timeout upload_timeout max_retries", e.Field) {
+			t.Errorf("unexpected error field %q", e.Field)
+		}
+	}
+}
+
+// TestCleanupUsesClock tests that draft age is measured with the injected clock.
+func TestCleanupUsesClock(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.CreateRelease("test-owner", "test-repo", &github.RepositoryRelease{
+		TagName: github.String("v1.0.0-draft"),
+		Draft:   github.Bool(true),
+	})
+
+	p := &GitHubPlugin{clock: &fakeClock{now: time.Now().Add(60 * 24 * time.Hour)}}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookOnSuccess,
+		Config: fakeConfig(fake, map[string]any{
+			"cleanup": map[string]any{"enabled": true, "draft_max_age_days": 30},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if n := len(fake.Releases("test-owner", "test-repo")); n != 0 {
+		t.Errorf("expected aged draft to be deleted, %d release(s) remain", n)
+	}
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Commit status modes.
+const (
+	// CommitStatusAuto uses a check run with a GitHub App token and a commit
+	// status otherwise.
+	CommitStatusAuto     = "auto"
+	CommitStatusCheckRun = "check_run"
+	CommitStatusStatus   = "status"
+)
+
+// defaultCommitStatusName is the check run name and status context.
+const defaultCommitStatusName = "relicta/release"
+
+// appTokenPrefix is the prefix of GitHub App installation tokens.
+const appTokenPrefix = "ghs_"
+
+// Commit status states. Check runs report pending as in progress.
+const (
+	commitStatePending = "pending"
+	commitStateSuccess = "success"
+	commitStateFailure = "failure"
+)
+
+// commitStatusTitles are the check run titles and status descriptions by state.
+var commitStatusTitles = map[string]string{
+	commitStatePending: "Release %s published",
+	commitStateSuccess: "Release %s succeeded",
+	commitStateFailure: "Release %s failed",
+}
+
+// CommitStatusConfig configures reporting the release on the tagged commit.
+type CommitStatusConfig struct {
+	// Enabled reports the release on the tagged commit.
+	Enabled bool `json:"enabled"`
+	// Mode is "auto" (default), "check_run" or "status".
+	Mode string `json:"mode,omitempty"`
+	// Name is the check run name or status context.
+	Name string `json:"name,omitempty"`
+}
+
+// parseCommitStatusConfig parses the commit_status section of the configuration.
+func parseCommitStatusConfig(raw map[string]any) CommitStatusConfig {
+	parser := helpers.NewConfigParser(raw)
+	return CommitStatusConfig{
+		Enabled: parser.GetBool("enabled", false),
+		Mode:    parser.GetString("mode", "", CommitStatusAuto),
+		Name:    parser.GetString("name", "", defaultCommitStatusName),
+	}
+}
+
+// validateCommitStatusConfig validates the commit_status section of the configuration.
+func validateCommitStatusConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	if raw == nil {
+		return
+	}
+	vb.ValidateOneOf(raw, "mode", []string{CommitStatusAuto, CommitStatusCheckRun, CommitStatusStatus})
+}
+
+// useCheckRun reports whether the release is reported as a check run.
+// Check runs can only be created by GitHub Apps.
+func useCheckRun(cfg *Config) bool {
+	switch cfg.CommitStatus.Mode {
+	case CommitStatusCheckRun:
+		return true
+	case CommitStatusStatus:
+		return false
+	default:
+		return strings.HasPrefix(cfg.Token, appTokenPrefix)
+	}
+}
+
+// publishCommitStatus reports the published release as pending on the
+// tagged commit.
+func (p *GitHubPlugin) publishCommitStatus(ctx context.Context, cfg *Config, client *github.Client, data templateData, artifacts []plugin.Artifact, resp *plugin.ExecuteResponse, dryRun bool) error {
+	releaseCtx := plugin.ReleaseContext{TagName: data.Tag, CommitSHA: data.CommitSHA}
+	return p.setCommitStatus(ctx, cfg, client, data.Owner, data.Repo, releaseCtx, commitStatePending, data.ReleaseURL, artifacts, resp, dryRun)
+}
+
+// finishCommitStatus reports the outcome of the release on the tagged commit.
+func (p *GitHubPlugin) finishCommitStatus(ctx context.Context, cfg *Config, client *github.Client, releaseCtx plugin.ReleaseContext, resp *plugin.ExecuteResponse, succeeded, dryRun bool) error {
+	state := commitStateFailure
+	if succeeded {
+		state = commitStateSuccess
+	}
+	owner, repo := resolveRepository(cfg, releaseCtx)
+
+	var releaseURL string
+	var artifacts []plugin.Artifact
+	if !dryRun {
+		// The release may be missing when publishing failed
+		release, _, err := client.Repositories.GetReleaseByTag(ctx, owner, repo, releaseCtx.TagName)
+		switch {
+		case err == nil:
+			releaseURL = release.GetHTMLURL()
+			assets, err := listReleaseAssets(ctx, client, owner, repo, release.GetID())
+			if err != nil {
+				return fmt.Errorf("failed to list release assets: %w", err)
+			}
+			for _, a := range assets {
+				artifacts = append(artifacts, plugin.Artifact{
+					Name: a.GetName(),
+					Path: a.GetBrowserDownloadURL(),
+					Type: "url",
+					Size: int64(a.GetSize()),
+				})
+			}
+		case !isNotFound(err):
+			return fmt.Errorf("failed to get release: %w", err)
+		}
+	}
+
+	return p.setCommitStatus(ctx, cfg, client, owner, repo, releaseCtx, state, releaseURL, artifacts, resp, dryRun)
+}
+
+// setCommitStatus reports state on the tagged commit as a check run or a
+// commit status, linking the release when its URL is known.
+func (p *GitHubPlugin) setCommitStatus(ctx context.Context, cfg *Config, client *github.Client, owner, repo string, releaseCtx plugin.ReleaseContext, state, releaseURL string, artifacts []plugin.Artifact, resp *plugin.ExecuteResponse, dryRun bool) error {
+	logger := loggerFromContext(ctx)
+	name := cfg.CommitStatus.Name
+	title := fmt.Sprintf(commitStatusTitles[state], releaseCtx.TagName)
+
+	kind := CommitStatusStatus
+	if useCheckRun(cfg) {
+		kind = CommitStatusCheckRun
+	}
+	resp.Outputs["commit_status_type"] = kind
+	resp.Outputs["commit_status_state"] = state
+	if dryRun {
+		logger.Info("dry run, skipping commit status", "type", kind, "name", name, "state", state)
+		return nil
+	}
+
+	sha, err := resolveTagCommit(ctx, client, owner, repo, releaseCtx)
+	if errors.Is(err, errTagNotFound) && state == commitStateFailure {
+		// The release failed before its tag was created
+		logger.Warn("no commit to report the failure on", "tag", releaseCtx.TagName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Outputs["commit_status_sha"] = sha
+
+	if kind == CommitStatusStatus {
+		status := &github.RepoStatus{
+			State:       github.String(state),
+			Context:     github.String(name),
+			Description: github.String(title),
+		}
+		if releaseURL != "" {
+			status.TargetURL = github.String(releaseURL)
+		}
+		if _, _, err := client.Repositories.CreateStatus(ctx, owner, repo, sha, status); err != nil {
+			return fmt.Errorf("failed to create commit status: %w", err)
+		}
+		logger.Info("set commit status", "sha", sha, "context", name, "state", state)
+		return nil
+	}
+
+	output := &github.CheckRunOutput{
+		Title:   github.String(title),
+		Summary: github.String(renderReleaseSummary(releaseCtx.TagName, releaseURL, artifacts)),
+	}
+	if releaseURL == "" {
+		output.Summary = github.String("No release was published.")
+	}
+	run, err := setCheckRun(ctx, client, owner, repo, sha, name, state, releaseURL, output)
+	if err != nil {
+		return err
+	}
+	resp.Outputs["check_run_url"] = run.GetHTMLURL()
+	logger.Info("set check run", "sha", sha, "name", name, "state", state, "id", run.GetID())
+	return nil
+}
+
+// setCheckRun updates the latest check run named name on sha, creating it
+// when there is none.
+func setCheckRun(ctx context.Context, client *github.Client, owner, repo, sha, name, state, releaseURL string, output *github.CheckRunOutput) (*github.CheckRun, error) {
+	status := github.String("in_progress")
+	var conclusion *string
+	if state != commitStatePending {
+		status = github.String("completed")
+		conclusion = github.String(state)
+	}
+	var detailsURL *string
+	if releaseURL != "" {
+		detailsURL = github.String(releaseURL)
+	}
+
+	runs, _, err := client.Checks.ListCheckRunsForRef(ctx, owner, repo, sha, &github.ListCheckRunsOptions{
+		CheckName: github.String(name),
+		Filter:    github.String("latest"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check runs: %w", err)
+	}
+
+	if len(runs.CheckRuns) > 0 {
+		run, _, err := client.Checks.UpdateCheckRun(ctx, owner, repo, runs.CheckRuns[0].GetID(), github.UpdateCheckRunOptions{
+			Name:       name,
+			DetailsURL: detailsURL,
+			Status:     status,
+			Conclusion: conclusion,
+			Output:     output,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update check run: %w", err)
+		}
+		return run, nil
+	}
+
+	run, _, err := client.Checks.CreateCheckRun(ctx, owner, repo, github.CreateCheckRunOptions{
+		Name:       name,
+		HeadSHA:    sha,
+		DetailsURL: detailsURL,
+		Status:     status,
+		Conclusion: conclusion,
+		Output:     output,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create check run: %w", err)
+	}
+	return run, nil
+}
+
+// errTagNotFound reports a release tag that does not exist.
+var errTagNotFound = errors.New("not found")
+
+// resolveTagCommit returns the commit the release tag points to, peeling
+// annotated tags. The commit from the release context is used when set.
+func resolveTagCommit(ctx context.Context, client *github.Client, owner, repo string, releaseCtx plugin.ReleaseContext) (string, error) {
+	if releaseCtx.CommitSHA != "" {
+		return releaseCtx.CommitSHA, nil
+	}
+
+	ref, _, err := client.Git.GetRef(ctx, owner, repo, "tags/"+releaseCtx.TagName)
+	if err != nil {
+		if isNotFound(err) {
+			return "", fmt.Errorf("tag %s %w", releaseCtx.TagName, errTagNotFound)
+		}
+		return "", fmt.Errorf("failed to get tag %s: %w", releaseCtx.TagName, err)
+	}
+
+	obj := ref.GetObject()
+	for obj.GetType() == "tag" {
+		tag, _, err := client.Git.GetTag(ctx, owner, repo, obj.GetSHA())
+		if err != nil {
+			return "", fmt.Errorf("failed to get tag %s: %w", releaseCtx.TagName, err)
+		}
+		obj = tag.GetObject()
+	}
+	return obj.GetSHA(), nil
+}
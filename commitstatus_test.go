@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// TestUseCheckRun tests choosing between check runs and commit statuses.
+func TestUseCheckRun(t *testing.T) {
+	tests := []struct {
+		mode, token string
+		want        bool
+	}{
+		{CommitStatusAuto, "ghs_installation", true},
+		{CommitStatusAuto, "ghp_personal", false},
+		{CommitStatusCheckRun, "ghp_personal", true},
+		{CommitStatusStatus, "ghs_installation", false},
+	}
+	for _, tt := range tests {
+		cfg := &Config{Token: tt.token, CommitStatus: CommitStatusConfig{Mode: tt.mode}}
+		if got := useCheckRun(cfg); got != tt.want {
+			t.Errorf("useCheckRun(%s, %s) = %v, want %v", tt.mode, tt.token, got, tt.want)
+		}
+	}
+}
+
+// TestExecuteCommitStatus tests commit statuses across publish and on-success.
+func TestExecuteCommitStatus(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"}
+	cfg := fakeConfig(fake, map[string]any{"commit_status": map[string]any{"enabled": true}})
+
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{Hook: plugin.HookPostPublish, Config: cfg, Context: releaseCtx})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	releaseURL := resp.Outputs["release_url"]
+
+	if _, err := p.Execute(context.Background(), plugin.ExecuteRequest{Hook: plugin.HookOnSuccess, Config: cfg, Context: releaseCtx}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sha := fake.Refs("test-owner", "test-repo")["refs/tags/v1.2.0"]
+	statuses := fake.CommitStatuses("test-owner", "test-repo", sha)
+	if len(statuses) != 2 || statuses[0].GetState() != commitStatePending || statuses[1].GetState() != commitStateSuccess {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+	if statuses[1].GetContext() != defaultCommitStatusName || statuses[1].GetTargetURL() != releaseURL {
+		t.Errorf("unexpected status %v", statuses[1])
+	}
+	if n := len(fake.CheckRuns("test-owner", "test-repo")); n != 0 {
+		t.Errorf("expected no check runs without an App token, got %d", n)
+	}
+}
+
+// TestExecuteCommitStatusMissingTag tests that on-error skips the status
+// when the release failed before its tag was created.
+func TestExecuteCommitStatusMissingTag(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookOnError,
+		Config:  fakeConfig(fake, map[string]any{"commit_status": map[string]any{"enabled": true}}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success || resp.Outputs["commit_status_sha"] != nil {
+		t.Errorf("expected success without a status, got %+v", resp)
+	}
+}
+
+// TestExecuteCheckRun tests that an App token creates one check run that on-error completes.
+func TestExecuteCheckRun(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"app.tar.gz": "binary"})
+	p := &GitHubPlugin{}
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"}
+	cfg := fakeConfig(fake, map[string]any{
+		"token":         "ghs_installation_token",
+		"assets":        []any{dir + "/app.tar.gz"},
+		"commit_status": map[string]any{"enabled": true, "name": "release"},
+	})
+
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{Hook: plugin.HookPostPublish, Config: cfg, Context: releaseCtx})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success || resp.Outputs["commit_status_type"] != CommitStatusCheckRun {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	runs := fake.CheckRuns("test-owner", "test-repo")
+	if len(runs) != 1 || runs[0].GetStatus() != "in_progress" || !strings.Contains(runs[0].GetOutput().GetSummary(), "app.tar.gz") {
+		t.Fatalf("unexpected check runs: %v", runs)
+	}
+
+	resp, err = p.Execute(context.Background(), plugin.ExecuteRequest{Hook: plugin.HookOnError, Config: cfg, Context: releaseCtx})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	runs = fake.CheckRuns("test-owner", "test-repo")
+	if len(runs) != 1 || runs[0].GetConclusion() != commitStateFailure || runs[0].GetOutput().GetTitle() != "Release v1.2.0 failed" {
+		t.Errorf("unexpected check runs: %v", runs)
+	}
+	if !strings.Contains(runs[0].GetOutput().GetSummary(), "app.tar.gz") {
+		t.Errorf("expected assets in summary, got %q", runs[0].GetOutput().GetSummary())
+	}
+}
+
+// TestExecuteCheckRunWithoutApp tests that forcing check runs without an App token fails the hook.
+func TestExecuteCheckRunWithoutApp(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  fakeConfig(fake, map[string]any{"commit_status": map[string]any{"enabled": true, "mode": "check_run"}}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "failed to create check run") {
+		t.Errorf("expected check run failure, got %+v", resp)
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Body sources of a component release.
+const (
+	BodySourceReleaseNotes = "release_notes"
+	BodySourceChangelog    = "changelog"
+	BodySourceGenerated    = "generated"
+	BodySourceNone         = "none"
+)
+
+// defaultComponentName is the release name template of a component.
+const defaultComponentName = "{{.Component}} {{.Version}}"
+
+// ComponentRelease is one release of a monorepo component.
+type ComponentRelease struct {
+	// Component names the component; outputs are keyed by it.
+	Component string `json:"component"`
+	// Version is the component version; it defaults to the release version.
+	Version string `json:"version,omitempty"`
+	// TagPrefix is prepended to the version to form the tag; it defaults
+	// to "<component>/v".
+	TagPrefix string `json:"tag_prefix,omitempty"`
+	// Tag is a tag template that overrides TagPrefix.
+	Tag string `json:"tag,omitempty"`
+	// Name is the release name template.
+	Name string `json:"name,omitempty"`
+	// BodySource selects the release body: "release_notes" (default),
+	// "changelog", "generated" or "none".
+	BodySource string `json:"body_source,omitempty"`
+	// Body is a body template that overrides BodySource.
+	Body string `json:"body,omitempty"`
+	// Assets are the asset paths or glob patterns of the component.
+	Assets []string `json:"assets,omitempty"`
+	// Draft, Prerelease and GenerateReleaseNotes default to the top-level
+	// settings.
+	Draft                bool `json:"draft"`
+	Prerelease           bool `json:"prerelease"`
+	GenerateReleaseNotes bool `json:"generate_release_notes"`
+}
+
+// componentData is the data available to component templates.
+type componentData struct {
+	templateData
+	// Component is the component name.
+	Component string
+}
+
+// componentResult is the outcome of one component release, reported in the
+// releases output.
+type componentResult struct {
+	Tag         string            `json:"tag_name"`
+	ReleaseID   int64             `json:"release_id,omitempty"`
+	ReleaseURL  string            `json:"release_url,omitempty"`
+	Success     bool              `json:"success"`
+	Error       string            `json:"error,omitempty"`
+	AssetErrors []string          `json:"asset_errors,omitempty"`
+	Artifacts   []plugin.Artifact `json:"assets,omitempty"`
+}
+
+// parseComponentReleases parses the releases list, defaulting the flags of
+// each entry to the top-level settings in cfg.
+func parseComponentReleases(raw any, cfg *Config) []ComponentRelease {
+	var releases []ComponentRelease
+	for _, m := range mapSlice(raw) {
+		parser := helpers.NewConfigParser(m)
+		component := parser.GetString("component", "", "")
+		releases = append(releases, ComponentRelease{
+			Component:            component,
+			Version:              parser.GetString("version", "", ""),
+			TagPrefix:            parser.GetString("tag_prefix", "", component+"/v"),
+			Tag:                  parser.GetString("tag", "", ""),
+			Name:                 parser.GetString("name", "", defaultComponentName),
+			BodySource:           parser.GetString("body_source", "", BodySourceReleaseNotes),
+			Body:                 parser.GetString("body", "", ""),
+			Assets:               parser.GetStringSlice("assets", nil),
+			Draft:                parser.GetBool("draft", cfg.Draft),
+			Prerelease:           parser.GetBool("prerelease", cfg.Prerelease),
+			GenerateReleaseNotes: parser.GetBool("generate_release_notes", cfg.GenerateReleaseNotes),
+		})
+	}
+	return releases
+}
+
+// validateComponentReleases validates the releases list and rejects the
+// options that act on a single release, which have no meaning when every
+// component gets its own release.
+func validateComponentReleases(vb *helpers.ValidationBuilder, raw any, cfg *Config) {
+	if len(cfg.Releases) > 0 {
+		for _, option := range []struct {
+			key string
+			set bool
+		}{
+			{"announcement", cfg.Announcement.Enabled},
+			{"mirrors", len(cfg.Mirrors) > 0},
+			{"homebrew", cfg.Homebrew.Enabled},
+			{"scoop", cfg.Scoop.Enabled},
+			{"winget", cfg.Winget.Enabled},
+			{"dispatch", len(cfg.Dispatch.RepositoryDispatch)+len(cfg.Dispatch.WorkflowDispatch) > 0},
+			{"deployment", cfg.Deployment.Enabled},
+			{"commit_status", cfg.CommitStatus.Enabled},
+			{"cleanup", cfg.Cleanup.Enabled},
+			{"audit_log.upload", cfg.AuditLog.Enabled && cfg.AuditLog.Upload},
+			{"promote_from", cfg.PromoteFrom != ""},
+			// These render or compare against the top-level tag rather
+			// than each component's
+			{"body_file", cfg.BodyFile != ""},
+			{"changelog_file", cfg.ChangelogFile != ""},
+			{"notes.full_changelog", cfg.Notes.FullChangelog},
+			{"notes.contributors", cfg.Notes.Contributors},
+		} {
+			if option.set {
+				vb.AddError(option.key, option.key+" is not supported with releases")
+			}
+		}
+		for _, source := range cfg.BodyPrecedence {
+			if source == BodyFromGenerated {
+				vb.AddError("body_precedence", "generated is not supported with releases; use body_source: generated per release")
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, r := range parseComponentReleases(raw, &Config{}) {
+		field := fmt.Sprintf("releases[%d]", i)
+		switch {
+		case r.Component == "":
+			vb.AddError(field+".component", "component is required")
+		case seen[r.Component]:
+			vb.AddError(field+".component", fmt.Sprintf("duplicate component %q", r.Component))
+		}
+		seen[r.Component] = true
+
+		switch r.BodySource {
+		case BodySourceReleaseNotes, BodySourceChangelog, BodySourceGenerated, BodySourceNone:
+		default:
+			vb.AddError(field+".body_source", fmt.Sprintf("body_source must be one of %s, %s, %s or %s",
+				BodySourceReleaseNotes, BodySourceChangelog, BodySourceGenerated, BodySourceNone))
+		}
+
+		validateTemplate(vb, field+".tag", r.Tag, componentData{})
+		validateTemplate(vb, field+".name", r.Name, componentData{})
+		validateTemplate(vb, field+".body", r.Body, componentData{})
+	}
+}
+
+// newComponentRelease renders the GitHub release of a component.
+func newComponentRelease(cfg *Config, c ComponentRelease, owner, repo string, releaseCtx plugin.ReleaseContext) (*github.RepositoryRelease, error) {
+	if c.Version != "" {
+		releaseCtx.Version = c.Version
+	}
+	releaseCtx.TagName = c.TagPrefix + releaseCtx.Version
+	data := componentData{templateData: newTemplateData(owner, repo, releaseCtx, ""), Component: c.Component}
+
+	if c.Tag != "" {
+		tag, err := renderTemplate("tag", c.Tag, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render tag: %w", err)
+		}
+		data.Tag = tag
+	}
+
+	name, err := renderTemplate("name", c.Name, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render name: %w", err)
+	}
+
+	generate := c.GenerateReleaseNotes
+	var body string
+	switch {
+	case c.Body != "":
+		if body, err = renderTemplate("body", c.Body, data); err != nil {
+			return nil, fmt.Errorf("failed to render body: %w", err)
+		}
+	case c.BodySource == BodySourceReleaseNotes:
+		body = data.ReleaseNotes
+	case c.BodySource == BodySourceChangelog:
+		body = data.Changelog
+	case c.BodySource == BodySourceGenerated:
+		generate = true
+	}
+
+	release := &github.RepositoryRelease{
+		TagName:              github.String(data.Tag),
+		Name:                 github.String(name),
+		Body:                 github.String(body),
+		Draft:                github.Bool(c.Draft),
+		Prerelease:           github.Bool(c.Prerelease),
+		GenerateReleaseNotes: github.Bool(generate),
+	}
+	if cfg.DiscussionCategory != "" {
+		release.DiscussionCategoryName = github.String(cfg.DiscussionCategory)
+	}
+	return release, nil
+}
+
+// publishComponents creates the release of every configured component. A
+// failed component does not stop the others; each outcome is reported in
+// the releases output keyed by component.
+func (p *GitHubPlugin) publishComponents(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) *plugin.ExecuteResponse {
+	client, err := p.getClient(ctx, cfg)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create GitHub client: %v", err),
+		}
+	}
+
+	owner, repo := resolveRepository(cfg, releaseCtx)
+	if owner == "" || repo == "" {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   "repository owner and name are required",
+		}
+	}
+
+	results := make(map[string]componentResult, len(cfg.Releases))
+	var artifacts []plugin.Artifact
+	var failures []string
+	for _, c := range cfg.Releases {
+		result := p.publishComponent(ctx, cfg, client, c, owner, repo, releaseCtx, dryRun)
+		results[c.Component] = result
+		artifacts = append(artifacts, result.Artifacts...)
+		if !result.Success {
+			failures = append(failures, fmt.Sprintf("component %s: %s", c.Component, result.Error))
+		}
+	}
+
+	resp := &plugin.ExecuteResponse{
+		Success:   len(failures) == 0,
+		Message:   fmt.Sprintf("Created %d of %d GitHub releases", len(cfg.Releases)-len(failures), len(cfg.Releases)),
+		Outputs:   map[string]any{"releases": results},
+		Artifacts: artifacts,
+	}
+	if dryRun {
+		resp.Message = fmt.Sprintf("Would create %d GitHub releases for %s/%s", len(cfg.Releases), owner, repo)
+	}
+	if len(failures) > 0 {
+		resp.Error = strings.Join(failures, "; ")
+	}
+	return resp
+}
+
+// publishComponent creates the release of one component with its own assets.
+func (p *GitHubPlugin) publishComponent(ctx context.Context, cfg *Config, client *github.Client, c ComponentRelease, owner, repo string, releaseCtx plugin.ReleaseContext, dryRun bool) componentResult {
+	ctx = withLogger(ctx, loggerFromContext(ctx).With("component", c.Component))
+
+	release, err := newComponentRelease(cfg, c, owner, repo, releaseCtx)
+	if err != nil {
+		loggerFromContext(ctx).Error("component release failed", "error", err)
+		return componentResult{Error: err.Error()}
+	}
+	result := componentResult{Tag: release.GetTagName()}
+
+	componentCfg := *cfg
+	componentCfg.Assets = c.Assets
+	componentCfg.PromoteFrom = ""
+	componentCfg.Draft = c.Draft
+	componentCfg.Prerelease = c.Prerelease
+	componentCfg.GenerateReleaseNotes = release.GetGenerateReleaseNotes()
+
+	resp, err := p.publishRelease(ctx, &componentCfg, client, owner, repo, releaseCtx, release, dryRun)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Success = resp.Success
+	result.Error = resp.Error
+	result.Artifacts = resp.Artifacts
+	result.ReleaseID, _ = resp.Outputs["release_id"].(int64)
+	result.ReleaseURL, _ = resp.Outputs["release_url"].(string)
+	result.AssetErrors, _ = resp.Outputs["asset_errors"].([]string)
+	return result
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// TestNewComponentRelease tests tags, names and bodies of component releases.
+func TestNewComponentRelease(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: "notes", Changelog: "changes"}
+	tests := []struct {
+		name     string
+		raw      map[string]any
+		tag      string
+		title    string
+		body     string
+		generate bool
+	}{
+		{"defaults", map[string]any{"component": "api"}, "api/v1.2.0", "api 1.2.0", "notes", false},
+		{"prefix and version", map[string]any{"component": "web", "tag_prefix": "web-", "version": "0.3.0"}, "web-0.3.0", "web 0.3.0", "notes", false},
+		{"tag template", map[string]any{"component": "cli", "tag": "{{.Component}}@{{.Version}}", "name": "CLI {{.Tag}}"}, "cli@1.2.0", "CLI cli@1.2.0", "notes", false},
+		{"changelog", map[string]any{"component": "api", "body_source": "changelog"}, "api/v1.2.0", "api 1.2.0", "changes", false},
+		{"generated", map[string]any{"component": "api", "body_source": "generated"}, "api/v1.2.0", "api 1.2.0", "", true},
+		{"body template", map[string]any{"component": "api", "body": "{{.Component}}: {{.ReleaseNotes}}"}, "api/v1.2.0", "api 1.2.0", "api: notes", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			c := parseComponentReleases([]any{tt.raw}, cfg)[0]
+			release, err := newComponentRelease(cfg, c, "o", "r", releaseCtx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if release.GetTagName() != tt.tag || release.GetName() != tt.title || release.GetBody() != tt.body || release.GetGenerateReleaseNotes() != tt.generate {
+				t.Errorf("unexpected release tag=%q name=%q body=%q generate=%v", release.GetTagName(), release.GetName(), release.GetBody(), release.GetGenerateReleaseNotes())
+			}
+		})
+	}
+}
+
+// TestExecuteComponents tests that each component gets its own release and fails independently.
+func TestExecuteComponents(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.CreateRelease("test-owner", "test-repo", &github.RepositoryRelease{TagName: github.String("web/v1.2.0")})
+	dir := writeAssets(t, map[string]string{"api.tar.gz": "api", "web.zip": "web"})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"prerelease": true,
+			"releases": []any{
+				map[string]any{"component": "api", "assets": []any{dir + "/api.tar.gz"}},
+				map[string]any{"component": "web", "assets": []any{dir + "/web.zip"}, "prerelease": false},
+			},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: "notes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "component web:") || strings.Contains(resp.Error, "component api") {
+		t.Fatalf("expected only web to fail, got %+v", resp)
+	}
+
+	results, _ := resp.Outputs["releases"].(map[string]componentResult)
+	api := results["api"]
+	if !api.Success || api.Tag != "api/v1.2.0" || api.ReleaseURL == "" || len(api.Artifacts) != 1 || api.Artifacts[0].Name != "api.tar.gz" {
+		t.Errorf("unexpected api result %+v", api)
+	}
+	if web := results["web"]; web.Success || web.Tag != "web/v1.2.0" {
+		t.Errorf("unexpected web result %+v", web)
+	}
+
+	var created *github.RepositoryRelease
+	for _, r := range fake.Releases("test-owner", "test-repo") {
+		if r.GetTagName() == "api/v1.2.0" {
+			created = r
+		}
+	}
+	if created == nil || !created.GetPrerelease() || created.GetName() != "api 1.2.0" || created.GetBody() != "notes" {
+		t.Errorf("unexpected api release %v", created)
+	}
+}
+
+// TestValidateComponents tests validation of the releases list.
+func TestValidateComponents(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token": "ghp_test",
+		"releases": []any{
+			map[string]any{"component": "api"},
+			map[string]any{"component": "api", "body_source": "readme"},
+			map[string]any{"tag": "{{.Nope}}"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 4 {
+		t.Errorf("expected duplicate, body_source, component and tag errors, got %+v", resp.Errors)
+	}
+}
+
+// TestExecuteComponentsLinkify tests that component bodies get the
+// processed notes.
+func TestExecuteComponentsLinkify(t *testing.T) {
+	fake := newFakeGitHub(t)
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"notes":    map[string]any{"linkify": true},
+			"releases": []any{map[string]any{"component": "api"}},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: "Fixes #12"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("unexpected failure: %v %+v", err, resp)
+	}
+	body := releaseByTag(t, fake, "test-owner", "test-repo", "api/v1.2.0").GetBody()
+	if !strings.HasPrefix(body, "Fixes [#12](") {
+		t.Errorf("expected the linkified notes, got %q", body)
+	}
+}
+
+// TestValidateComponentsUnsupported tests that options acting on a single
+// release are rejected with releases.
+func TestValidateComponentsUnsupported(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":           "ghp_test",
+		"releases":        []any{map[string]any{"component": "api"}},
+		"mirrors":         []any{map[string]any{"repository": "public/app"}},
+		"deployment":      map[string]any{"enabled": true},
+		"audit_log":       map[string]any{"enabled": true, "upload": true},
+		"body_precedence": []any{"release_notes", "generated"},
+		"body_file":       "NOTES.md",
+		"notes":           map[string]any{"full_changelog": true, "contributors": true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := make(map[string]bool)
+	for _, e := range resp.Errors {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{"mirrors", "deployment", "audit_log.upload", "body_precedence", "body_file", "notes.full_changelog", "notes.contributors"} {
+		if !fields[field] {
+			t.Errorf("expected an error for %s, got %+v", field, resp.Errors)
+		}
+	}
+}
+
+// TestRenderStepSummaryComponents tests the step summary of component releases.
+func TestRenderStepSummaryComponents(t *testing.T) {
+	summary := renderStepSummary(&plugin.ExecuteResponse{Outputs: map[string]any{
+		"releases": map[string]componentResult{
+			"web": {Tag: "web/v1.0.0", Error: "tag exists"},
+			"api": {Tag: "api/v1.0.0", ReleaseURL: "https://example.com/api", Success: true},
+		},
+	}})
+	if !strings.HasPrefix(summary, "### api release [api/v1.0.0](https://example.com/api)") || !strings.Contains(summary, "### web failed\n\ntag exists") {
+		t.Errorf("unexpected summary:\n%s", summary)
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+)
+
+// defaultContentType is used when nothing better is known about an asset.
+const defaultContentType = "application/octet-stream"
+
+// Sources of a resolved content type.
+const (
+	contentTypeFromOverride  = "override"
+	contentTypeFromExtension = "extension"
+	contentTypeFromContent   = "content"
+	contentTypeFromDefault   = "default"
+)
+
+// contentTypesByExtension maps file extensions, matched case-insensitively
+// and longest first, to the content types GitHub should serve them with. It
+// covers release formats the system MIME table gets wrong or lacks.
+var contentTypesByExtension = map[string]string{
+	".7z":           "application/x-7z-compressed",
+	".apk":          "application/vnd.android.package-archive",
+	".appimage":     "application/vnd.appimage",
+	".bz2":          "application/x-bzip2",
+	".cdx.json":     "application/vnd.cyclonedx+json",
+	".cdx.xml":      "application/vnd.cyclonedx+xml",
+	".crt":          "application/x-x509-ca-cert",
+	".deb":          "application/vnd.debian.binary-package",
+	".dmg":          "application/x-apple-diskimage",
+	".exe":          "application/vnd.microsoft.portable-executable",
+	".gz":           "application/gzip",
+	".intoto.jsonl": "application/vnd.in-toto+json",
+	".jar":          "application/java-archive",
+	".json":         "application/json",
+	".jsonl":        "application/jsonl",
+	".md":           "text/markdown",
+	".msi":          "application/x-msi",
+	".pem":          "application/x-pem-file",
+	".pub":          "text/plain",
+	".rpm":          "application/x-rpm",
+	".sbom":         "application/vnd.cyclonedx+json",
+	".sha256":       "text/plain",
+	".sha512":       "text/plain",
+	".spdx":         "text/spdx",
+	".spdx.json":    "application/spdx+json",
+	".tar":          "application/x-tar",
+	".tar.bz2":      "application/x-bzip2",
+	".tar.gz":       "application/gzip",
+	".tar.xz":       "application/x-xz",
+	".tar.zst":      "application/zstd",
+	".tgz":          "application/gzip",
+	".txt":          "text/plain",
+	".whl":          "application/zip",
+	".xz":           "application/x-xz",
+	".yaml":         "application/yaml",
+	".yml":          "application/yaml",
+	".zip":          "application/zip",
+	".zst":          "application/zstd",
+}
+
+// signatureExtensions are used for PGP, cosign and minisign signatures
+// alike, so their content type is decided by the file's magic bytes alone.
+var signatureExtensions = []string{".asc", ".sig"}
+
+// pgpSignatureContentType is the content type of ASCII-armored PGP signatures.
+const pgpSignatureContentType = "application/pgp-signature"
+
+// contentSignature identifies a file format by the bytes at offset.
+type contentSignature struct {
+	offset      int
+	magic       []byte
+	contentType string
+}
+
+// contentSignatures are checked in order, so more specific formats come
+// before the formats they build on (an AppImage is also an ELF binary).
+var contentSignatures = []contentSignature{
+	{8, []byte("AI\x02"), "application/vnd.appimage"},
+	{0, []byte("\x7fELF"), "application/x-executable"},
+	{0, []byte("MZ"), "application/vnd.microsoft.portable-executable"},
+	{0, []byte{0xcf, 0xfa, 0xed, 0xfe}, "application/x-mach-binary"},
+	{0, []byte{0xce, 0xfa, 0xed, 0xfe}, "application/x-mach-binary"},
+	{0, []byte{0xca, 0xfe, 0xba, 0xbe}, "application/x-mach-binary"},
+	{0, []byte("!<arch>\ndebian-binary"), "application/vnd.debian.binary-package"},
+	{0, []byte{0xed, 0xab, 0xee, 0xdb}, "application/x-rpm"},
+	{0, []byte{0x1f, 0x8b}, "application/gzip"},
+	{0, []byte("PK\x03\x04"), "application/zip"},
+	{0, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, "application/x-xz"},
+	{0, []byte{0x28, 0xb5, 0x2f, 0xfd}, "application/zstd"},
+	{0, []byte("BZh"), "application/x-bzip2"},
+	{0, []byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}, "application/x-7z-compressed"},
+	{257, []byte("ustar"), "application/x-tar"},
+	{0, []byte("-----BEGIN PGP SIGNATURE-----"), pgpSignatureContentType},
+}
+
+// sniffLength is how much of a file is read to detect its content type.
+const sniffLength = 512
+
+// parseContentTypes parses the content_types option, mapping asset names or
+// glob patterns to content types.
+func parseContentTypes(raw map[string]any) map[string]string {
+	if len(raw) == 0 {
+		return nil
+	}
+	types := make(map[string]string, len(raw))
+	for pattern, v := range raw {
+		if s, ok := v.(string); ok {
+			types[pattern] = s
+		}
+	}
+	return types
+}
+
+// validateContentTypes validates the content_types option.
+func validateContentTypes(vb *helpers.ValidationBuilder, raw map[string]any) {
+	for pattern, v := range raw {
+		field := "content_types." + pattern
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			vb.AddError(field, fmt.Sprintf("invalid pattern: %v", err))
+		}
+		s, ok := v.(string)
+		if !ok {
+			vb.AddError(field, "content type must be a string")
+			continue
+		}
+		if _, _, err := mime.ParseMediaType(s); err != nil {
+			vb.AddError(field, fmt.Sprintf("invalid content type %q: %v", s, err))
+		}
+	}
+}
+
+// resolveContentType returns the content type to upload the asset at path
+// with, and where it came from. An override for the exact file name wins,
+// then the longest matching override pattern, the extension table, the
+// file's magic bytes and finally the system MIME table. Signatures are only
+// labelled PGP when they are armored PGP signatures.
+func resolveContentType(path string, overrides map[string]string) (string, string) {
+	name := filepath.Base(path)
+	if t, ok := overrideContentType(name, overrides); ok {
+		return t, contentTypeFromOverride
+	}
+	if isSignature(name) {
+		if t, ok := sniffContentType(path); ok && t == pgpSignatureContentType {
+			return t, contentTypeFromContent
+		}
+		return defaultContentType, contentTypeFromDefault
+	}
+	if t, ok := extensionContentType(name); ok {
+		return t, contentTypeFromExtension
+	}
+	if t, ok := sniffContentType(path); ok {
+		return t, contentTypeFromContent
+	}
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t, contentTypeFromExtension
+	}
+	return defaultContentType, contentTypeFromDefault
+}
+
+// isSignature reports whether name has a signature extension.
+func isSignature(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, s := range signatureExtensions {
+		if ext == s {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideContentType returns the configured content type for name.
+func overrideContentType(name string, overrides map[string]string) (string, bool) {
+	if t, ok := overrides[name]; ok {
+		return t, true
+	}
+	patterns := make([]string, 0, len(overrides))
+	for pattern := range overrides {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return overrides[pattern], true
+		}
+	}
+	return "", false
+}
+
+// extensionContentType looks name up in the extension table, preferring
+// compound extensions such as .tar.gz.
+func extensionContentType(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	best := ""
+	for ext := range contentTypesByExtension {
+		if len(ext) > len(best) && strings.HasSuffix(lower, ext) {
+			best = ext
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return contentTypesByExtension[best], true
+}
+
+// sniffContentType detects the content type of the file at path from its
+// first bytes. Generic results such as application/octet-stream are not
+// reported so the system MIME table gets a chance.
+func sniffContentType(path string) (string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer func() { _ = file.Close() }()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", false
+	}
+	head = head[:n]
+	if n == 0 {
+		return "", false
+	}
+
+	for _, sig := range contentSignatures {
+		if len(head) >= sig.offset+len(sig.magic) && bytes.Equal(head[sig.offset:sig.offset+len(sig.magic)], sig.magic) {
+			return sig.contentType, true
+		}
+	}
+	if t := sniffJSON(head); t != "" {
+		return t, true
+	}
+	if t := http.DetectContentType(head); t != defaultContentType {
+		return t, true
+	}
+	return "", false
+}
+
+// sniffJSON recognises JSON documents, including CycloneDX and SPDX SBOMs.
+func sniffJSON(head []byte) string {
+	trimmed := bytes.TrimSpace(head)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return ""
+	}
+	switch {
+	case bytes.Contains(trimmed, []byte(`"bomFormat"`)):
+		return "application/vnd.cyclonedx+json"
+	case bytes.Contains(trimmed, []byte(`"spdxVersion"`)):
+		return "application/spdx+json"
+	case bytes.Contains(trimmed, []byte(`"payloadType"`)):
+		return "application/vnd.in-toto+json"
+	default:
+		return "application/json"
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// TestResolveContentType tests overrides, the extension table and sniffing.
+func TestResolveContentType(t *testing.T) {
+	elf := "\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00"
+	appImage := "\x7fELF\x02\x01\x01\x00AI\x02\x00\x00\x00\x00\x00"
+	dir := writeAssets(t, map[string]string{
+		"app.sig":        "-----BEGIN PGP SIGNATURE-----\n\niQEz\n-----END PGP SIGNATURE-----\n",
+		"app.asc":        "-----BEGIN PGP SIGNATURE-----\n",
+		"cosign.sig":     "MEUCIQDx0vHDf6I1Q2c9",
+		"minisign.sig":   "untrusted comment: signature from minisign secret key\n",
+		"app.deb":        "deb",
+		"App.AppImage":   "img",
+		"app.tar.gz":     "tgz",
+		"app.bin":        "bin",
+		"app-linux":      elf,
+		"app-x86_64":     appImage,
+		"app.sbom":       `{"bomFormat": "CycloneDX", "specVersion": "1.5"}`,
+		"NOTICE":         "plain text",
+		"data.unknownxy": "\x00\x01\x02\x03",
+		"special.sig":    "sig",
+	})
+	overrides := map[string]string{
+		"*.bin":       "application/x-custom",
+		"special.sig": "application/x-special",
+	}
+
+	tests := []struct {
+		name, want, source string
+	}{
+		{"app.sig", "application/pgp-signature", contentTypeFromContent},
+		{"app.asc", "application/pgp-signature", contentTypeFromContent},
+		{"cosign.sig", defaultContentType, contentTypeFromDefault},
+		{"minisign.sig", defaultContentType, contentTypeFromDefault},
+		{"app.deb", "application/vnd.debian.binary-package", contentTypeFromExtension},
+		{"App.AppImage", "application/vnd.appimage", contentTypeFromExtension},
+		{"app.tar.gz", "application/gzip", contentTypeFromExtension},
+		{"app.bin", "application/x-custom", contentTypeFromOverride},
+		{"special.sig", "application/x-special", contentTypeFromOverride},
+		{"app-linux", "application/x-executable", contentTypeFromContent},
+		{"app-x86_64", "application/vnd.appimage", contentTypeFromContent},
+		{"app.sbom", "application/vnd.cyclonedx+json", contentTypeFromExtension},
+		{"NOTICE", "text/plain; charset=utf-8", contentTypeFromContent},
+		{"data.unknownxy", defaultContentType, contentTypeFromDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, source := resolveContentType(dir+"/"+tt.name, overrides)
+			if got != tt.want || source != tt.source {
+				t.Errorf("got %q from %s, want %q from %s", got, source, tt.want, tt.source)
+			}
+		})
+	}
+}
+
+// TestExecuteContentTypes tests the content types assets are uploaded with
+// and reported in dry-run.
+func TestExecuteContentTypes(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"app.deb": "deb", "app.bin": "bin"})
+	cfg := fakeConfig(fake, map[string]any{
+		"assets":        []any{dir + "/*"},
+		"content_types": map[string]any{"*.bin": "application/x-custom"},
+	})
+	want := map[string]string{"app.deb": "application/vnd.debian.binary-package", "app.bin": "application/x-custom"}
+
+	p := &GitHubPlugin{}
+	req := plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  cfg,
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+		DryRun:  true,
+	}
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil || !resp.Success {
+		t.Fatalf("dry run failed: %v %+v", err, resp)
+	}
+	planned, _ := resp.Outputs["asset_content_types"].(map[string]string)
+	for name, contentType := range want {
+		if planned[name] != contentType {
+			t.Errorf("dry run: got %q for %s, want %q", planned[name], name, contentType)
+		}
+	}
+
+	req.DryRun = false
+	resp, err = p.Execute(context.Background(), req)
+	if err != nil || !resp.Success {
+		t.Fatalf("release failed: %v %+v", err, resp)
+	}
+	release := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0")
+	for _, asset := range fake.Assets(release.GetID()) {
+		if asset.GetContentType() != want[asset.GetName()] {
+			t.Errorf("got %q for %s, want %q", asset.GetContentType(), asset.GetName(), want[asset.GetName()])
+		}
+	}
+}
+
+// TestValidateContentTypes tests validation of the content_types option.
+func TestValidateContentTypes(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":         "ghp_test",
+		"content_types": map[string]any{"[": "text/plain", "*.sig": "not a type", "*.bin": 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 3 {
+		t.Errorf("expected pattern, type and value errors, got %+v", resp.Errors)
+	}
+}
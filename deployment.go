@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Deployment states set by the plugin.
+const (
+	deploymentStateInProgress = "in_progress"
+	deploymentStateSuccess    = "success"
+	deploymentStateFailure    = "failure"
+)
+
+// DeploymentConfig configures a GitHub deployment of the release tag.
+type DeploymentConfig struct {
+	// Enabled creates a deployment when the release is published.
+	Enabled bool `json:"enabled"`
+	// Environment is the deployment environment, e.g. "production".
+	Environment string `json:"environment"`
+	// Description is a templated deployment description.
+	Description string `json:"description,omitempty"`
+	// TransientEnvironment marks the environment as going away in the future.
+	TransientEnvironment bool `json:"transient_environment"`
+	// ProductionEnvironment marks the environment as used by end users.
+	ProductionEnvironment bool `json:"production_environment"`
+}
+
+// parseDeploymentConfig parses the deployment section of the configuration.
+func parseDeploymentConfig(raw map[string]any) DeploymentConfig {
+	parser := helpers.NewConfigParser(raw)
+	return DeploymentConfig{
+		Enabled:               parser.GetBool("enabled", false),
+		Environment:           parser.GetString("environment", "", ""),
+		Description:           parser.GetString("description", "", "Release {{.Tag}}"),
+		TransientEnvironment:  parser.GetBool("transient_environment", false),
+		ProductionEnvironment: parser.GetBool("production_environment", false),
+	}
+}
+
+// validateDeploymentConfig validates the deployment section of the configuration.
+func validateDeploymentConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	if raw == nil {
+		return
+	}
+
+	cfg := parseDeploymentConfig(raw)
+	if cfg.Enabled && cfg.Environment == "" {
+		vb.AddError("deployment.environment", "environment is required when deployment is enabled")
+	}
+	validateTemplate(vb, "deployment.description", cfg.Description, templateData{})
+}
+
+// createDeployment creates a deployment of the release tag and marks it in
+// progress. The outcome is recorded by finishDeployment in the on-success and
+// on-error hooks.
+func (p *GitHubPlugin) createDeployment(ctx context.Context, cfg *Config, client *github.Client, data templateData, _ []plugin.Artifact, resp *plugin.ExecuteResponse, dryRun bool) error {
+	logger := loggerFromContext(ctx)
+	d := cfg.Deployment
+
+	description, err := renderTemplate("deployment.description", d.Description, data)
+	if err != nil {
+		return fmt.Errorf("failed to render description: %w", err)
+	}
+
+	resp.Outputs["deployment_environment"] = d.Environment
+	if dryRun {
+		logger.Info("dry run, skipping deployment", "environment", d.Environment, "ref", data.Tag)
+		return nil
+	}
+
+	deployment, _, err := client.Repositories.CreateDeployment(ctx, data.Owner, data.Repo, &github.DeploymentRequest{
+		Ref:                   github.String(data.Tag),
+		Task:                  github.String("deploy"),
+		AutoMerge:             github.Bool(false),
+		RequiredContexts:      &[]string{},
+		Payload:               map[string]string{"version": data.Version},
+		Environment:           github.String(d.Environment),
+		Description:           github.String(description),
+		TransientEnvironment:  github.Bool(d.TransientEnvironment),
+		ProductionEnvironment: github.Bool(d.ProductionEnvironment),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create deployment: %w", err)
+	}
+	resp.Outputs["deployment_id"] = deployment.GetID()
+
+	if err := setDeploymentStatus(ctx, client, data.Owner, data.Repo, deployment.GetID(), deploymentStateInProgress, data.ReleaseURL); err != nil {
+		return err
+	}
+	resp.Outputs["deployment_state"] = deploymentStateInProgress
+
+	logger.Info("created deployment", "id", deployment.GetID(), "environment", d.Environment)
+	return nil
+}
+
+// finishDeployment sets the final status of the deployment created for the
+// release tag. A release that failed before its deployment was created has
+// nothing to update.
+func (p *GitHubPlugin) finishDeployment(ctx context.Context, cfg *Config, client *github.Client, releaseCtx plugin.ReleaseContext, resp *plugin.ExecuteResponse, succeeded, dryRun bool) error {
+	logger := loggerFromContext(ctx)
+	env := cfg.Deployment.Environment
+
+	state := deploymentStateFailure
+	if succeeded {
+		state = deploymentStateSuccess
+	}
+	if dryRun {
+		logger.Info("dry run, skipping deployment status", "environment", env, "state", state)
+		resp.Outputs["deployment_environment"] = env
+		resp.Outputs["deployment_state"] = state
+		return nil
+	}
+
+	owner, repo := resolveRepository(cfg, releaseCtx)
+	deployments, _, err := client.Repositories.ListDeployments(ctx, owner, repo, &github.DeploymentsListOptions{
+		Ref:         releaseCtx.TagName,
+		Environment: env,
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+	if len(deployments) == 0 {
+		logger.Warn("no deployment to update", "tag", releaseCtx.TagName, "environment", env)
+		return nil
+	}
+	id := deployments[0].GetID()
+	resp.Outputs["deployment_environment"] = env
+	resp.Outputs["deployment_state"] = state
+	resp.Outputs["deployment_id"] = id
+
+	// The release may be missing when publishing failed
+	var releaseURL string
+	release, _, err := client.Repositories.GetReleaseByTag(ctx, owner, repo, releaseCtx.TagName)
+	switch {
+	case err == nil:
+		releaseURL = release.GetHTMLURL()
+	case !isNotFound(err):
+		return fmt.Errorf("failed to get release: %w", err)
+	}
+
+	if err := setDeploymentStatus(ctx, client, owner, repo, id, state, releaseURL); err != nil {
+		return err
+	}
+	logger.Info("updated deployment status", "id", id, "environment", env, "state", state)
+	return nil
+}
+
+// setDeploymentStatus adds a status to a deployment, linking the release
+// as its environment URL when known.
+func setDeploymentStatus(ctx context.Context, client *github.Client, owner, repo string, id int64, state, releaseURL string) error {
+	status := &github.DeploymentStatusRequest{State: github.String(state)}
+	if releaseURL != "" {
+		status.EnvironmentURL = github.String(releaseURL)
+	}
+	if _, _, err := client.Repositories.CreateDeploymentStatus(ctx, owner, repo, id, status); err != nil {
+		return fmt.Errorf("failed to set deployment status %s: %w", state, err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// deploymentConfig returns a fake configuration with deployments enabled.
+func deploymentConfig(cfg map[string]any) map[string]any {
+	cfg["deployment"] = map[string]any{"enabled": true, "environment": "production"}
+	return cfg
+}
+
+// TestExecuteDeployment tests the deployment lifecycle across publish and on-success.
+func TestExecuteDeployment(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"}
+	cfg := deploymentConfig(fakeConfig(fake, nil))
+
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{Hook: plugin.HookPostPublish, Config: cfg, Context: releaseCtx})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	deployments := fake.Deployments("test-owner", "test-repo")
+	if len(deployments) != 1 || deployments[0].GetRef() != "v1.2.0" || deployments[0].GetEnvironment() != "production" {
+		t.Fatalf("unexpected deployments: %v", deployments)
+	}
+	id := deployments[0].GetID()
+	if resp.Outputs["deployment_id"] != id || resp.Outputs["deployment_state"] != deploymentStateInProgress {
+		t.Errorf("unexpected outputs %v", resp.Outputs)
+	}
+
+	resp, err = p.Execute(context.Background(), plugin.ExecuteRequest{Hook: plugin.HookOnSuccess, Config: cfg, Context: releaseCtx})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	statuses := fake.DeploymentStatuses(id)
+	if len(statuses) != 2 || statuses[0].GetState() != deploymentStateInProgress || statuses[1].GetState() != deploymentStateSuccess {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+	if url := statuses[1].GetEnvironmentURL(); url == "" || !strings.Contains(url, "v1.2.0") {
+		t.Errorf("expected release URL as environment_url, got %q", url)
+	}
+}
+
+// TestExecuteDeploymentOnError tests that on-error marks the deployment failed.
+func TestExecuteDeploymentOnError(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"}
+	cfg := deploymentConfig(fakeConfig(fake, nil))
+
+	if _, err := p.Execute(context.Background(), plugin.ExecuteRequest{Hook: plugin.HookPostPublish, Config: cfg, Context: releaseCtx}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{Hook: plugin.HookOnError, Config: cfg, Context: releaseCtx})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success || resp.Outputs["deployment_state"] != deploymentStateFailure {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	id := fake.Deployments("test-owner", "test-repo")[0].GetID()
+	statuses := fake.DeploymentStatuses(id)
+	if last := statuses[len(statuses)-1]; last.GetState() != deploymentStateFailure {
+		t.Errorf("expected failure status, got %s", last.GetState())
+	}
+}
+
+// TestExecuteDeploymentMissing tests that a release without a deployment
+// does not fail the final hooks.
+func TestExecuteDeploymentMissing(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	for _, hook := range []plugin.Hook{plugin.HookOnSuccess, plugin.HookOnError} {
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook:    hook,
+			Config:  deploymentConfig(fakeConfig(fake, nil)),
+			Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !resp.Success || resp.Outputs["deployment_id"] != nil {
+			t.Errorf("%s: expected success without a deployment, got %+v", hook, resp)
+		}
+	}
+}
+
+// TestValidateDeployment tests that an enabled deployment requires an environment.
+func TestValidateDeployment(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":      "ghp_test",
+		"deployment": map[string]any{"enabled": true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 1 || resp.Errors[0].Field != "deployment.environment" {
+		t.Errorf("expected environment error, got %+v", resp.Errors)
+	}
+}
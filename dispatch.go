@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// defaultDispatchEventType is the repository_dispatch event type sent when
+// none is configured.
+const defaultDispatchEventType = "release"
+
+// Dispatch statuses reported in the dispatches output.
+const (
+	dispatchStatusSent    = "sent"
+	dispatchStatusFailed  = "failed"
+	dispatchStatusPlanned = "dry-run"
+)
+
+// DispatchConfig configures downstream workflows triggered after a release.
+type DispatchConfig struct {
+	// RepositoryDispatch sends repository_dispatch events.
+	RepositoryDispatch []RepositoryDispatch `json:"repository_dispatch,omitempty"`
+	// WorkflowDispatch runs workflows through workflow_dispatch.
+	WorkflowDispatch []WorkflowDispatch `json:"workflow_dispatch,omitempty"`
+}
+
+// RepositoryDispatch is a repository_dispatch event to send.
+type RepositoryDispatch struct {
+	// Repository is the receiving repository as "owner/name".
+	Repository string `json:"repository"`
+	// EventType is the event type; it defaults to "release".
+	EventType string `json:"event_type,omitempty"`
+	// ClientPayload adds templated fields to the default payload of
+	// version, tag, release_url and assets.
+	ClientPayload map[string]any `json:"client_payload,omitempty"`
+	// Token overrides the plugin token for this repository.
+	Token string `json:"token,omitempty"`
+}
+
+// WorkflowDispatch is a workflow run to trigger.
+type WorkflowDispatch struct {
+	// Repository is the repository as "owner/name"; it defaults to the
+	// release repository.
+	Repository string `json:"repository,omitempty"`
+	// Workflow is the workflow file name, e.g. "deploy.yml", or its ID.
+	Workflow string `json:"workflow"`
+	// Ref is the branch or tag to run on; it defaults to the default branch.
+	Ref string `json:"ref,omitempty"`
+	// Inputs are templated workflow inputs.
+	Inputs map[string]any `json:"inputs,omitempty"`
+	// Token overrides the plugin token for this repository.
+	Token string `json:"token,omitempty"`
+}
+
+// dispatchResult is the status of one dispatch, reported in the outputs.
+type dispatchResult struct {
+	Type       string `json:"type"`
+	Repository string `json:"repository"`
+	Target     string `json:"target"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// parseDispatchConfig parses the dispatch section of the configuration.
+func parseDispatchConfig(raw map[string]any) DispatchConfig {
+	var cfg DispatchConfig
+	for _, m := range mapSlice(raw["repository_dispatch"]) {
+		parser := helpers.NewConfigParser(m)
+		cfg.RepositoryDispatch = append(cfg.RepositoryDispatch, RepositoryDispatch{
+			Repository:    parser.GetString("repository", "", ""),
+			EventType:     parser.GetString("event_type", "", defaultDispatchEventType),
+			ClientPayload: parser.GetMap("client_payload"),
+			Token:         parser.GetString("token", "", ""),
+		})
+	}
+	for _, m := range mapSlice(raw["workflow_dispatch"]) {
+		parser := helpers.NewConfigParser(m)
+		cfg.WorkflowDispatch = append(cfg.WorkflowDispatch, WorkflowDispatch{
+			Repository: parser.GetString("repository", "", ""),
+			Workflow:   parser.GetString("workflow", "", ""),
+			Ref:        parser.GetString("ref", "", ""),
+			Inputs:     parser.GetMap("inputs"),
+			Token:      parser.GetString("token", "", ""),
+		})
+	}
+	return cfg
+}
+
+// validateDispatchConfig validates the dispatch section of the configuration.
+func validateDispatchConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	if raw == nil {
+		return
+	}
+
+	cfg := parseDispatchConfig(raw)
+	for i, d := range cfg.RepositoryDispatch {
+		field := fmt.Sprintf("dispatch.repository_dispatch[%d]", i)
+		if _, _, ok := splitRepository(d.Repository); !ok {
+			vb.AddError(field+".repository", "repository must be owner/name")
+		}
+		if _, err := renderValue(d.ClientPayload, templateData{}); err != nil {
+			vb.AddError(field+".client_payload", fmt.Sprintf("invalid template: %v", err))
+		}
+	}
+	for i, d := range cfg.WorkflowDispatch {
+		field := fmt.Sprintf("dispatch.workflow_dispatch[%d]", i)
+		if d.Repository != "" {
+			if _, _, ok := splitRepository(d.Repository); !ok {
+				vb.AddError(field+".repository", "repository must be owner/name")
+			}
+		}
+		if d.Workflow == "" {
+			vb.AddError(field+".workflow", "workflow is required")
+		}
+		if _, err := renderValue(d.Inputs, templateData{}); err != nil {
+			vb.AddError(field+".inputs", fmt.Sprintf("invalid template: %v", err))
+		}
+	}
+}
+
+// renderValue renders every string in a configured value as a template,
+// descending into maps and lists.
+func renderValue(v any, data templateData) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return renderTemplate("value", v, data)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			rendered, err := renderValue(item, data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			out[k] = rendered
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			rendered, err := renderValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// dispatchPayload returns the client_payload of a repository_dispatch event.
+func dispatchPayload(d RepositoryDispatch, data templateData, artifacts []plugin.Artifact) (map[string]any, error) {
+	assets := make(map[string]string, len(artifacts))
+	for _, a := range artifacts {
+		assets[a.Name] = a.Path
+	}
+	payload := map[string]any{
+		"version":     data.Version,
+		"tag":         data.Tag,
+		"release_url": data.ReleaseURL,
+		"assets":      assets,
+	}
+
+	custom, err := renderValue(d.ClientPayload, data)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := custom.(map[string]any); ok {
+		for k, v := range m {
+			payload[k] = v
+		}
+	}
+	return payload, nil
+}
+
+// dispatchDownstream sends the configured repository_dispatch and
+// workflow_dispatch events. Every dispatch is attempted; the status of each
+// is reported in the dispatches output.
+func (p *GitHubPlugin) dispatchDownstream(ctx context.Context, cfg *Config, client *github.Client, data templateData, artifacts []plugin.Artifact, resp *plugin.ExecuteResponse, dryRun bool) error {
+	logger := loggerFromContext(ctx)
+
+	var results []dispatchResult
+	var errs []error
+	record := func(r dispatchResult, err error) {
+		switch {
+		case err != nil:
+			r.Status = dispatchStatusFailed
+			r.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s %s (%s): %w", r.Type, r.Repository, r.Target, err))
+			logger.Error("dispatch failed", "type", r.Type, "repository", r.Repository, "target", r.Target, "error", err)
+		case dryRun:
+			r.Status = dispatchStatusPlanned
+			logger.Info("dry run, skipping dispatch", "type", r.Type, "repository", r.Repository, "target", r.Target)
+		default:
+			r.Status = dispatchStatusSent
+			logger.Info("dispatched", "type", r.Type, "repository", r.Repository, "target", r.Target)
+		}
+		results = append(results, r)
+	}
+
+	for _, d := range cfg.Dispatch.RepositoryDispatch {
+		r := dispatchResult{Type: "repository_dispatch", Repository: d.Repository, Target: d.EventType}
+		record(r, p.sendRepositoryDispatch(ctx, cfg, client, d, data, artifacts, dryRun))
+	}
+
+	for _, d := range cfg.Dispatch.WorkflowDispatch {
+		if d.Repository == "" {
+			d.Repository = data.Owner + "/" + data.Repo
+		}
+		r := dispatchResult{Type: "workflow_dispatch", Repository: d.Repository, Target: d.Workflow}
+		record(r, p.sendWorkflowDispatch(ctx, cfg, client, d, data, dryRun))
+	}
+
+	resp.Outputs["dispatches"] = results
+	return errors.Join(errs...)
+}
+
+// sendRepositoryDispatch sends one repository_dispatch event.
+func (p *GitHubPlugin) sendRepositoryDispatch(ctx context.Context, cfg *Config, client *github.Client, d RepositoryDispatch, data templateData, artifacts []plugin.Artifact, dryRun bool) error {
+	owner, repo, ok := splitRepository(d.Repository)
+	if !ok {
+		return fmt.Errorf("invalid repository %q", d.Repository)
+	}
+
+	payload, err := dispatchPayload(d, data, artifacts)
+	if err != nil {
+		return fmt.Errorf("failed to render client_payload: %w", err)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode client_payload: %w", err)
+	}
+	if dryRun {
+		return nil
+	}
+
+	client, err = p.targetClient(ctx, cfg, client, RepoTarget{Repository: d.Repository, Token: d.Token})
+	if err != nil {
+		return err
+	}
+	msg := json.RawMessage(raw)
+	_, _, err = client.Repositories.Dispatch(ctx, owner, repo, github.DispatchRequestOptions{
+		EventType:     d.EventType,
+		ClientPayload: &msg,
+	})
+	return err
+}
+
+// sendWorkflowDispatch triggers one workflow run.
+func (p *GitHubPlugin) sendWorkflowDispatch(ctx context.Context, cfg *Config, client *github.Client, d WorkflowDispatch, data templateData, dryRun bool) error {
+	owner, repo, ok := splitRepository(d.Repository)
+	if !ok {
+		return fmt.Errorf("invalid repository %q", d.Repository)
+	}
+
+	rendered, err := renderValue(d.Inputs, data)
+	if err != nil {
+		return fmt.Errorf("failed to render inputs: %w", err)
+	}
+	inputs, _ := rendered.(map[string]any)
+	if dryRun {
+		return nil
+	}
+
+	client, err = p.targetClient(ctx, cfg, client, RepoTarget{Repository: d.Repository, Token: d.Token})
+	if err != nil {
+		return err
+	}
+
+	ref := d.Ref
+	if ref == "" {
+		r, _, err := client.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			return fmt.Errorf("failed to get default branch: %w", err)
+		}
+		ref = r.GetDefaultBranch()
+	}
+
+	event := github.CreateWorkflowDispatchEventRequest{Ref: ref, Inputs: inputs}
+	if id, err := strconv.ParseInt(d.Workflow, 10, 64); err == nil {
+		_, err = client.Actions.CreateWorkflowDispatchEventByID(ctx, owner, repo, id, event)
+		return err
+	}
+	_, err = client.Actions.CreateWorkflowDispatchEventByFileName(ctx, owner, repo, d.Workflow, event)
+	return err
+}
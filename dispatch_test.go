@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// TestRenderValue tests template rendering of nested configuration values.
+func TestRenderValue(t *testing.T) {
+	v := map[string]any{
+		"version": "{{.Version}}",
+		"nested":  map[string]any{"tag": "{{.Tag}}"},
+		"list":    []any{"{{.Repo}}", 3},
+		"flag":    true,
+	}
+	out, err := renderValue(v, templateData{Version: "1.0.0", Tag: "v1.0.0", Repo: "r"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m := out.(map[string]any)
+	if m["version"] != "1.0.0" || m["nested"].(map[string]any)["tag"] != "v1.0.0" || m["list"].([]any)[0] != "r" || m["flag"] != true {
+		t.Errorf("unexpected rendering %v", m)
+	}
+}
+
+// TestExecuteDispatch tests repository_dispatch and workflow_dispatch against the fake API.
+func TestExecuteDispatch(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.AddWorkflow("test-owner", "docs", "rebuild.yml")
+	dir := writeAssets(t, map[string]string{"app.tar.gz": "binary"})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets": []any{dir + "/app.tar.gz"},
+			"dispatch": map[string]any{
+				"repository_dispatch": []any{
+					map[string]any{
+						"repository":     "test-owner/downstream",
+						"event_type":     "upstream-release",
+						"client_payload": map[string]any{"component": "{{.Repo}}"},
+					},
+				},
+				"workflow_dispatch": []any{
+					map[string]any{
+						"repository": "test-owner/docs",
+						"workflow":   "rebuild.yml",
+						"inputs":     map[string]any{"version": "{{.Version}}"},
+					},
+				},
+			},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	dispatches := fake.Dispatches("test-owner", "downstream")
+	if len(dispatches) != 1 || dispatches[0].EventType != "upstream-release" {
+		t.Fatalf("unexpected dispatches: %v", dispatches)
+	}
+	payload := dispatches[0].ClientPayload
+	if payload["version"] != "1.2.0" || payload["tag"] != "v1.2.0" || payload["component"] != "test-repo" || payload["release_url"] != resp.Outputs["release_url"] {
+		t.Errorf("unexpected payload %v", payload)
+	}
+	if assets, _ := payload["assets"].(map[string]any); assets["app.tar.gz"] == nil {
+		t.Errorf("expected asset URLs in payload, got %v", payload["assets"])
+	}
+
+	runs := fake.WorkflowDispatches("test-owner", "docs")
+	if len(runs) != 1 || runs[0].Ref != "main" || runs[0].Inputs["version"] != "1.2.0" {
+		t.Errorf("unexpected workflow dispatches: %v", runs)
+	}
+
+	results, _ := resp.Outputs["dispatches"].([]dispatchResult)
+	if len(results) != 2 || results[0].Status != dispatchStatusSent || results[1].Status != dispatchStatusSent {
+		t.Errorf("unexpected dispatch results %+v", results)
+	}
+}
+
+// TestExecuteDispatchFailure tests that one failed dispatch does not stop the others.
+func TestExecuteDispatchFailure(t *testing.T) {
+	fake := newFakeGitHub(t)
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"dispatch": map[string]any{
+				"workflow_dispatch": []any{
+					map[string]any{"workflow": "missing.yml", "ref": "main"},
+				},
+				"repository_dispatch": []any{
+					map[string]any{"repository": "test-owner/downstream"},
+				},
+			},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "missing.yml") {
+		t.Errorf("expected dispatch failure, got %+v", resp)
+	}
+	if n := len(fake.Dispatches("test-owner", "downstream")); n != 1 {
+		t.Errorf("expected repository_dispatch to be sent, got %d", n)
+	}
+
+	results, _ := resp.Outputs["dispatches"].([]dispatchResult)
+	if len(results) != 2 || results[1].Status != dispatchStatusFailed || results[1].Repository != "test-owner/test-repo" {
+		t.Errorf("unexpected dispatch results %+v", results)
+	}
+}
+
+// TestExecuteDispatchDryRun tests that dry-run renders dispatches without sending them.
+func TestExecuteDispatchDryRun(t *testing.T) {
+	fake := newFakeGitHub(t)
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:   plugin.HookPostPublish,
+		DryRun: true,
+		Config: fakeConfig(fake, map[string]any{
+			"dispatch": map[string]any{
+				"repository_dispatch": []any{map[string]any{"repository": "test-owner/downstream"}},
+			},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, _ := resp.Outputs["dispatches"].([]dispatchResult)
+	if !resp.Success || len(results) != 1 || results[0].Status != dispatchStatusPlanned {
+		t.Errorf("unexpected dry run response %+v", resp)
+	}
+	if n := len(fake.Dispatches("test-owner", "downstream")); n != 0 {
+		t.Errorf("expected no dispatch in dry run, got %d", n)
+	}
+}
+
+// TestValidateDispatch tests validation of the dispatch section.
+func TestValidateDispatch(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token": "ghp_test",
+		"dispatch": map[string]any{
+			"repository_dispatch": []any{map[string]any{"repository": "no-owner"}},
+			"workflow_dispatch":   []any{map[string]any{"inputs": map[string]any{"v": "{{.Nope}}"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 3 {
+		t.Errorf("expected repository, workflow and inputs errors, got %+v", resp.Errors)
+	}
+}
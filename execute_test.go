@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+
+	"github.com/relicta-tech/plugin-github/internal/ghfake"
+)
+
+// newFakeGitHub starts a fake GitHub API for end-to-end tests.
+func newFakeGitHub(t *testing.T) *ghfake.Server {
+	t.Helper()
+	fake := ghfake.New()
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+// fakeConfig returns plugin configuration pointing at fake.
+func fakeConfig(fake *ghfake.Server, extra map[string]any) map[string]any {
+	cfg := map[string]any{
+		"owner":     "test-owner",
+		"repo":      "test-repo",
+		"token":     "ghp_test_token",
+		"base_url":  fake.URL(),
+		"log_level": "off",
+	}
+	for k, v := range extra {
+		cfg[k] = v
+	}
+	return cfg
+}
+
+// writeAssets creates asset files in a temporary directory and returns it.
+func writeAssets(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write asset %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// TestExecutePostPublishEndToEnd tests release creation, upload and verification against the fake API.
+func TestExecutePostPublishEndToEnd(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{
+		"app_linux.tar.gz":  "linux binary",
+		"app_darwin.tar.gz": "darwin binary",
+		"checksums.txt":     "sums",
+	})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets":           []any{dir + "/*.tar.gz", dir + "/checksums.txt"},
+			"verify_assets":    true,
+			"verify_checksums": true,
+		}),
+		Context: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0", ReleaseNotes: "Notes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	releases := fake.Releases("test-owner", "test-repo")
+	if len(releases) != 1 || releases[0].GetTagName() != "v1.0.0" || releases[0].GetBody() != "Notes" {
+		t.Fatalf("unexpected releases: %v", releases)
+	}
+	if resp.Outputs["release_id"] != releases[0].GetID() {
+		t.Errorf("expected release_id %d, got %v", releases[0].GetID(), resp.Outputs["release_id"])
+	}
+
+	assets := fake.Assets(releases[0].GetID())
+	if len(assets) != 3 {
+		t.Fatalf("expected 3 assets, got %d", len(assets))
+	}
+	content, _ := fake.AssetContent(assets[0].GetID())
+	if len(content) == 0 {
+		t.Error("expected uploaded content")
+	}
+	if len(resp.Artifacts) != 3 {
+		t.Errorf("expected 3 artifacts, got %d", len(resp.Artifacts))
+	}
+}
+
+// TestExecuteAssetFailurePolicy tests upload failures with both failure policies.
+func TestExecuteAssetFailurePolicy(t *testing.T) {
+	tests := []struct {
+		policy        string
+		expectSuccess bool
+	}{
+		{policy: AssetFailureContinue, expectSuccess: true},
+		{policy: AssetFailureFail, expectSuccess: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			fake := newFakeGitHub(t)
+			fake.InjectFault(ghfake.Fault{Method: "POST", Path: "/assets", Status: http.StatusBadGateway})
+			dir := writeAssets(t, map[string]string{"app.tar.gz": "binary"})
+
+			p := &GitHubPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: fakeConfig(fake, map[string]any{
+					"assets":               []any{dir + "/app.tar.gz"},
+					"asset_failure_policy": tt.policy,
+				}),
+				Context: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Success != tt.expectSuccess {
+				t.Errorf("expected Success=%v, got %v (%s)", tt.expectSuccess, resp.Success, resp.Error)
+			}
+			if errs, _ := resp.Outputs["asset_errors"].([]string); len(errs) != 1 {
+				t.Errorf("expected 1 asset error, got %v", resp.Outputs["asset_errors"])
+			}
+		})
+	}
+}
+
+// TestExecuteRateLimited tests that a rate-limited release creation fails cleanly.
+func TestExecuteRateLimited(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.InjectFault(ghfake.Fault{RateLimited: true})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  fakeConfig(fake, nil),
+		Context: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "rate limit") {
+		t.Errorf("expected rate limit failure, got %+v", resp)
+	}
+}
+
+// TestExecuteSlowUploadCancelled tests that a cancelled context stops a slow upload.
+func TestExecuteSlowUploadCancelled(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.InjectFault(ghfake.Fault{Method: "POST", Path: "/assets", Delay: 5 * time.Second})
+	dir := writeAssets(t, map[string]string{"app.tar.gz": "binary"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	p := &GitHubPlugin{}
+	start := time.Now()
+	resp, err := p.Execute(ctx, plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets":               []any{dir + "/app.tar.gz"},
+			"asset_failure_policy": AssetFailureFail,
+		}),
+		Context: plugin.ReleaseContext{Version: "1.0.0", TagName: "v1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success {
+		t.Error("expected failure for cancelled upload")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected upload to be cancelled promptly, took %s", elapsed)
+	}
+}
+
+// TestExecutePromoteEndToEnd tests promoting a prerelease against the fake API.
+func TestExecutePromoteEndToEnd(t *testing.T) {
+	fake := newFakeGitHub(t)
+	rc := fake.CreateRelease("test-owner", "test-repo", &github.RepositoryRelease{
+		TagName:    github.String("v1.4.0-rc.3"),
+		Prerelease: github.Bool(true),
+	})
+	fake.AddAsset("test-owner", "test-repo", rc.GetID(), "app.tar.gz", []byte("rc binary"))
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  fakeConfig(fake, map[string]any{"promote_from": "v1.4.0-rc.3"}),
+		Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	releases := fake.Releases("test-owner", "test-repo")
+	if len(releases) != 2 || releases[0].GetTagName() != "v1.4.0" || releases[0].GetPrerelease() {
+		t.Fatalf("unexpected releases: %v", releases)
+	}
+	assets := fake.Assets(releases[0].GetID())
+	if len(assets) != 1 {
+		t.Fatalf("expected 1 promoted asset, got %d", len(assets))
+	}
+	if content, _ := fake.AssetContent(assets[0].GetID()); string(content) != "rc binary" {
+		t.Errorf("expected promoted content, got %q", content)
+	}
+}
+
+// TestExecuteCleanupEndToEnd tests cleanup in the on-success hook against the fake API.
+func TestExecuteCleanupEndToEnd(t *testing.T) {
+	fake := newFakeGitHub(t)
+	for _, tag := range []string{"v1.4.0-rc.1", "v1.4.0-rc.2"} {
+		fake.CreateRelease("test-owner", "test-repo", &github.RepositoryRelease{
+			TagName:    github.String(tag),
+			Prerelease: github.Bool(true),
+		})
+	}
+	fake.CreateRelease("test-owner", "test-repo", &github.RepositoryRelease{TagName: github.String("v1.4.0")})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookOnSuccess,
+		Config: fakeConfig(fake, map[string]any{
+			"cleanup": map[string]any{"enabled": true, "delete_superseded": true, "delete_tags": true},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.4.0", TagName: "v1.4.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	releases := fake.Releases("test-owner", "test-repo")
+	if len(releases) != 1 || releases[0].GetTagName() != "v1.4.0" {
+		t.Errorf("expected only v1.4.0 to remain, got %v", releases)
+	}
+	refs := fake.Refs("test-owner", "test-repo")
+	if _, ok := refs["refs/tags/v1.4.0-rc.1"]; ok {
+		t.Error("expected tag v1.4.0-rc.1 to be deleted")
+	}
+	if _, ok := refs["refs/tags/v1.4.0"]; !ok {
+		t.Error("expected tag v1.4.0 to remain")
+	}
+}
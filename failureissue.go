@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Environment variables describing the failure to the on-error hook. The SDK
+// release context has no failure fields, so these are a contract of this
+// plugin: the host or the workflow sets them, and they are read from the
+// release context environment or, when the host filters them out, from the
+// plugin's own environment.
+const (
+	envFailedHook   = "RELICTA_FAILED_HOOK"
+	envReleaseError = "RELICTA_ERROR"
+)
+
+// defaultFailureIssueLabel labels tracking issues when no labels are configured.
+const defaultFailureIssueLabel = "release-failure"
+
+// defaultFailureIssueBody is the tracking issue body, also posted as a
+// comment when the issue already exists.
+const defaultFailureIssueBody = `The release of {{.Tag}} failed.
+
+| | |
+|---|---|
+| Version | {{.Version}} |
+| Tag | {{.Tag}} |
+| Hook | {{.Hook}} |
+
+` + "```" + `
+{{.Error}}
+` + "```"
+
+// Failure issue actions reported in the failure_issue_action output.
+const (
+	failureIssueOpened    = "opened"
+	failureIssueReopened  = "reopened"
+	failureIssueCommented = "commented"
+	failureIssueClosed    = "closed"
+	failureIssueNone      = "none"
+)
+
+// FailureIssueConfig configures the issue that tracks failed releases.
+type FailureIssueConfig struct {
+	// Enabled opens an issue when a release fails and closes it on the next
+	// successful release of the same line.
+	Enabled bool `json:"enabled"`
+	// Title is the issue title template.
+	Title string `json:"title,omitempty"`
+	// Body is the issue body and comment template.
+	Body string `json:"body,omitempty"`
+	// Labels are added to the issue and used to find it again.
+	Labels []string `json:"labels,omitempty"`
+	// Assignees are assigned to new issues.
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// failureIssueData is the data available to the failure issue templates.
+type failureIssueData struct {
+	templateData
+	// Line is the release line, e.g. "v1.4".
+	Line string
+	// Hook is the hook that failed.
+	Hook string
+	// Error is the failure message.
+	Error string
+}
+
+// parseFailureIssueConfig parses the failure_issue section of the configuration.
+func parseFailureIssueConfig(raw map[string]any) FailureIssueConfig {
+	parser := helpers.NewConfigParser(raw)
+	return FailureIssueConfig{
+		Enabled:   parser.GetBool("enabled", false),
+		Title:     parser.GetString("title", "", "Release {{.Tag}} failed"),
+		Body:      parser.GetString("body", "", defaultFailureIssueBody),
+		Labels:    parser.GetStringSlice("labels", []string{defaultFailureIssueLabel}),
+		Assignees: parser.GetStringSlice("assignees", nil),
+	}
+}
+
+// validateFailureIssueConfig validates the failure_issue section of the configuration.
+func validateFailureIssueConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	if raw == nil {
+		return
+	}
+
+	cfg := parseFailureIssueConfig(raw)
+	validateTemplate(vb, "failure_issue.title", cfg.Title, failureIssueData{})
+	validateTemplate(vb, "failure_issue.body", cfg.Body, failureIssueData{})
+}
+
+// releaseLine returns the release line of a tag, or the tag itself when it
+// is not a semantic version.
+func releaseLine(tag string) string {
+	if v, ok := parseVersion(tag); ok {
+		return v.Line()
+	}
+	return tag
+}
+
+// failureIssueMarker is a hidden marker identifying the tracking issue of a
+// release line, so it is found again even when the title changes.
+func failureIssueMarker(line string) string {
+	return fmt.Sprintf("<!-- relicta:release-failure %s -->", line)
+}
+
+// failureDetail returns the value of the failure environment variable key in
+// the release context, falling back to the process environment.
+func failureDetail(releaseCtx plugin.ReleaseContext, key string) string {
+	if v := releaseCtx.Environment[key]; v != "" {
+		return v
+	}
+	return os.Getenv(key)
+}
+
+// newFailureIssueData returns the template data of a failed release, taking
+// the failing hook and error from the failure environment variables.
+func newFailureIssueData(owner, repo string, releaseCtx plugin.ReleaseContext) failureIssueData {
+	data := failureIssueData{
+		templateData: newTemplateData(owner, repo, releaseCtx, ""),
+		Line:         releaseLine(releaseCtx.TagName),
+		Hook:         failureDetail(releaseCtx, envFailedHook),
+		Error:        failureDetail(releaseCtx, envReleaseError),
+	}
+	if data.Hook == "" {
+		data.Hook = "unknown"
+	}
+	if data.Error == "" {
+		data.Error = "no error message was reported"
+	}
+	return data
+}
+
+// trackFailureIssue opens, reopens or comments on the tracking issue of a
+// failed release, or closes it when a release of the same line succeeds.
+func (p *GitHubPlugin) trackFailureIssue(ctx context.Context, cfg *Config, client *github.Client, releaseCtx plugin.ReleaseContext, resp *plugin.ExecuteResponse, succeeded, dryRun bool) error {
+	logger := loggerFromContext(ctx)
+	owner, repo := resolveRepository(cfg, releaseCtx)
+	data := newFailureIssueData(owner, repo, releaseCtx)
+
+	var title, body string
+	if !succeeded {
+		var err error
+		if title, err = renderTemplate("failure_issue.title", cfg.FailureIssue.Title, data); err != nil {
+			return fmt.Errorf("failed to render title: %w", err)
+		}
+		if body, err = renderTemplate("failure_issue.body", cfg.FailureIssue.Body, data); err != nil {
+			return fmt.Errorf("failed to render body: %w", err)
+		}
+	}
+
+	if dryRun {
+		if !succeeded {
+			resp.Outputs["failure_issue_title"] = title
+			resp.Outputs["failure_issue_body"] = body
+		}
+		logger.Info("dry run, skipping failure issue", "line", data.Line, "succeeded", succeeded)
+		return nil
+	}
+
+	issue, err := findFailureIssue(ctx, client, owner, repo, cfg.FailureIssue.Labels, data.Line)
+	if err != nil {
+		return err
+	}
+
+	action := failureIssueNone
+	switch {
+	case succeeded && (issue == nil || issue.GetState() != "open"):
+		// Nothing to close
+	case succeeded:
+		releaseURL := ""
+		if release, _, err := client.Repositories.GetReleaseByTag(ctx, owner, repo, releaseCtx.TagName); err == nil {
+			releaseURL = release.GetHTMLURL()
+		}
+		comment := fmt.Sprintf("Release %s succeeded. %s", releaseCtx.TagName, releaseURL)
+		if _, _, err := client.Issues.CreateComment(ctx, owner, repo, issue.GetNumber(), &github.IssueComment{Body: github.String(strings.TrimSpace(comment))}); err != nil {
+			return fmt.Errorf("failed to comment on issue #%d: %w", issue.GetNumber(), err)
+		}
+		if issue, _, err = client.Issues.Edit(ctx, owner, repo, issue.GetNumber(), &github.IssueRequest{
+			State:       github.String("closed"),
+			StateReason: github.String("completed"),
+		}); err != nil {
+			return fmt.Errorf("failed to close issue: %w", err)
+		}
+		action = failureIssueClosed
+	case issue == nil:
+		req := &github.IssueRequest{
+			Title:  github.String(title),
+			Body:   github.String(body + "\n\n" + failureIssueMarker(data.Line)),
+			Labels: &cfg.FailureIssue.Labels,
+		}
+		if len(cfg.FailureIssue.Assignees) > 0 {
+			req.Assignees = &cfg.FailureIssue.Assignees
+		}
+		if issue, _, err = client.Issues.Create(ctx, owner, repo, req); err != nil {
+			return fmt.Errorf("failed to create issue: %w", err)
+		}
+		action = failureIssueOpened
+	default:
+		action = failureIssueCommented
+		if issue.GetState() != "open" {
+			if issue, _, err = client.Issues.Edit(ctx, owner, repo, issue.GetNumber(), &github.IssueRequest{State: github.String("open")}); err != nil {
+				return fmt.Errorf("failed to reopen issue: %w", err)
+			}
+			action = failureIssueReopened
+		}
+		if _, _, err := client.Issues.CreateComment(ctx, owner, repo, issue.GetNumber(), &github.IssueComment{Body: github.String(body)}); err != nil {
+			return fmt.Errorf("failed to comment on issue #%d: %w", issue.GetNumber(), err)
+		}
+	}
+
+	resp.Outputs["failure_issue_action"] = action
+	if issue != nil {
+		resp.Outputs["failure_issue_number"] = issue.GetNumber()
+		resp.Outputs["failure_issue_url"] = issue.GetHTMLURL()
+	}
+	logger.Info("tracked failure issue", "line", data.Line, "action", action, "issue", issue.GetNumber())
+	return nil
+}
+
+// findFailureIssue returns the most recent tracking issue of a release
+// line, open or closed, or nil when there is none.
+func findFailureIssue(ctx context.Context, client *github.Client, owner, repo string, labels []string, line string) (*github.Issue, error) {
+	marker := failureIssueMarker(line)
+	opts := &github.IssueListByRepoOptions{
+		State:       "all",
+		Labels:      labels,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		issues, resp, err := client.Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+		for _, issue := range issues {
+			if !issue.IsPullRequest() && strings.Contains(issue.GetBody(), marker) {
+				return issue, nil
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
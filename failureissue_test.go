@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// failureIssueRequest returns a hook request for a tag with failure issues enabled.
+func failureIssueRequest(cfg map[string]any, hook plugin.Hook, tag string) plugin.ExecuteRequest {
+	cfg["failure_issue"] = map[string]any{
+		"enabled":   true,
+		"labels":    []any{"release-failure", "ci"},
+		"assignees": []any{"octocat"},
+	}
+	return plugin.ExecuteRequest{
+		Hook:   hook,
+		Config: cfg,
+		Context: plugin.ReleaseContext{
+			Version: strings.TrimPrefix(tag, "v"),
+			TagName: tag,
+			Environment: map[string]string{
+				envFailedHook:   "post-publish",
+				envReleaseError: "upload failed: 502",
+			},
+		},
+	}
+}
+
+// TestReleaseLine tests deriving the release line from a tag.
+func TestReleaseLine(t *testing.T) {
+	for tag, want := range map[string]string{"v1.4.2": "v1.4", "v2.0.0-rc.1": "v2.0", "nightly": "nightly"} {
+		if got := releaseLine(tag); got != want {
+			t.Errorf("releaseLine(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+// TestNewFailureIssueData tests reading the failure from the release context
+// environment, then the process environment.
+func TestNewFailureIssueData(t *testing.T) {
+	t.Setenv(envFailedHook, "publish")
+	t.Setenv(envReleaseError, "process error")
+
+	data := newFailureIssueData("o", "r", plugin.ReleaseContext{
+		TagName:     "v1.2.0",
+		Environment: map[string]string{envReleaseError: "context error"},
+	})
+	if data.Hook != "publish" || data.Error != "context error" {
+		t.Errorf("unexpected failure data %+v", data)
+	}
+
+	t.Setenv(envFailedHook, "")
+	t.Setenv(envReleaseError, "")
+	if data := newFailureIssueData("o", "r", plugin.ReleaseContext{TagName: "v1.2.0"}); data.Hook != "unknown" || data.Error != "no error message was reported" {
+		t.Errorf("unexpected defaults %+v", data)
+	}
+}
+
+// TestExecuteFailureIssue tests the tracking issue lifecycle of a release line.
+func TestExecuteFailureIssue(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	run := func(hook plugin.Hook, tag string) *plugin.ExecuteResponse {
+		t.Helper()
+		resp, err := p.Execute(context.Background(), failureIssueRequest(fakeConfig(fake, nil), hook, tag))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !resp.Success {
+			t.Fatalf("expected success, got error: %s", resp.Error)
+		}
+		return resp
+	}
+
+	resp := run(plugin.HookOnError, "v1.2.0")
+	if resp.Outputs["failure_issue_action"] != failureIssueOpened {
+		t.Fatalf("unexpected outputs %v", resp.Outputs)
+	}
+	issues := fake.Issues("test-owner", "test-repo")
+	if len(issues) != 1 || issues[0].GetTitle() != "Release v1.2.0 failed" || len(issues[0].Labels) != 2 || issues[0].Assignees[0].GetLogin() != "octocat" {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+	for _, want := range []string{"| Hook | post-publish |", "upload failed: 502", failureIssueMarker("v1.2")} {
+		if !strings.Contains(issues[0].GetBody(), want) {
+			t.Errorf("expected body to contain %q, got:\n%s", want, issues[0].GetBody())
+		}
+	}
+	number := issues[0].GetNumber()
+
+	if resp := run(plugin.HookOnError, "v1.2.1"); resp.Outputs["failure_issue_action"] != failureIssueCommented {
+		t.Errorf("expected a comment, got %v", resp.Outputs)
+	}
+	if comments := fake.Comments("test-owner", "test-repo", number); len(comments) != 1 || !strings.Contains(comments[0].GetBody(), "v1.2.1") {
+		t.Errorf("unexpected comments: %v", comments)
+	}
+
+	// A release of another line leaves the issue open
+	if resp := run(plugin.HookOnSuccess, "v1.3.0"); resp.Outputs["failure_issue_action"] != failureIssueNone {
+		t.Errorf("expected no action, got %v", resp.Outputs)
+	}
+	if resp := run(plugin.HookOnSuccess, "v1.2.2"); resp.Outputs["failure_issue_action"] != failureIssueClosed {
+		t.Errorf("expected the issue to be closed, got %v", resp.Outputs)
+	}
+	if state := fake.Issues("test-owner", "test-repo")[0].GetState(); state != "closed" {
+		t.Errorf("expected closed issue, got %s", state)
+	}
+
+	if resp := run(plugin.HookOnError, "v1.2.3"); resp.Outputs["failure_issue_action"] != failureIssueReopened || resp.Outputs["failure_issue_number"] != number {
+		t.Errorf("expected the issue to be reopened, got %v", resp.Outputs)
+	}
+	if n := len(fake.Issues("test-owner", "test-repo")); n != 1 {
+		t.Errorf("expected a single tracking issue, got %d", n)
+	}
+}
+
+// TestExecuteFailureIssueDryRun tests that dry-run renders the issue without creating it.
+func TestExecuteFailureIssueDryRun(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	req := failureIssueRequest(fakeConfig(fake, nil), plugin.HookOnError, "v1.2.0")
+	req.DryRun = true
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success || resp.Outputs["failure_issue_title"] != "Release v1.2.0 failed" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if n := len(fake.Issues("test-owner", "test-repo")); n != 0 {
+		t.Errorf("expected no issues in dry run, got %d", n)
+	}
+}
+
+// TestValidateFailureIssue tests validation of the failure issue templates.
+func TestValidateFailureIssue(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":         "ghp_test",
+		"failure_issue": map[string]any{"enabled": true, "title": "{{.Hook}} failed for {{.Nope}}"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 1 || resp.Errors[0].Field != "failure_issue.title" {
+		t.Errorf("expected title error, got %+v", resp.Errors)
+	}
+}
@@ -4,6 +4,7 @@ go 1.24.0
 
 require (
 	github.com/google/go-github/v60 v60.0.0
+	github.com/hashicorp/go-hclog v0.14.1
 	github.com/relicta-tech/relicta-plugin-sdk v1.0.0
 	golang.org/x/oauth2 v0.34.0
 )
@@ -12,7 +13,6 @@ require (
 	github.com/fatih/color v1.7.0 // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
-	github.com/hashicorp/go-hclog v0.14.1 // indirect
 	github.com/hashicorp/go-plugin v1.6.2 // indirect
 	github.com/hashicorp/yamux v0.1.1 // indirect
 	github.com/mattn/go-colorable v0.1.4 // indirect
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// githubGraphQLURL is the GraphQL endpoint of github.com.
+const githubGraphQLURL = "https://api.github.com/graphql"
+
+// graphqlClient calls the GitHub GraphQL API.
+type graphqlClient struct {
+	httpClient *http.Client
+	url        string
+}
+
+// newGraphQLClient returns a GraphQL client for token. For GitHub Enterprise
+// Server the endpoint is derived from baseURL.
+func (f *clientFactory) newGraphQLClient(token, baseURL string) *graphqlClient {
+	return &graphqlClient{httpClient: f.httpClient(token), url: graphqlURL(baseURL)}
+}
+
+// graphqlURL returns the GraphQL endpoint for a REST base URL, mapping
+// https://ghe.example.com/api/v3/ to https://ghe.example.com/api/graphql.
+func graphqlURL(baseURL string) string {
+	if baseURL == "" {
+		return githubGraphQLURL
+	}
+	base := strings.TrimSuffix(baseURL, "/")
+	base = strings.TrimSuffix(base, "/api/v3")
+	return base + "/api/graphql"
+}
+
+// graphqlError is an error returned in a GraphQL response.
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+// query runs a query or mutation and decodes its data into out.
+func (c *graphqlClient) query(ctx context.Context, query string, vars map[string]any, out any) error {
+	payload, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("graphql request failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphqlError  `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to decode graphql response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("graphql: %s", strings.Join(messages, "; "))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Data, out)
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// defaultFormulaTemplate renders a formula with per-platform archives.
+const defaultFormulaTemplate = `# typed: false
+# frozen_string_literal: true
+
+# This file was generated by relicta-plugin-github. DO NOT EDIT.
+class {{.Class}} < Formula
+  desc {{rubyString .Description}}
+  homepage {{rubyString .Homepage}}
+  version {{rubyString .Version}}
+{{- if .License}}
+  license {{rubyString .License}}
+{{- end}}
+{{- if or .Darwin.AMD64 .Darwin.ARM64}}
+
+  on_macos do
+{{- with .Darwin.ARM64}}
+    on_arm do
+      url "{{.URL}}"
+      sha256 "{{.SHA256}}"
+    end
+{{- end}}
+{{- with .Darwin.AMD64}}
+    on_intel do
+      url "{{.URL}}"
+      sha256 "{{.SHA256}}"
+    end
+{{- end}}
+  end
+{{- end}}
+{{- if or .Linux.AMD64 .Linux.ARM64}}
+
+  on_linux do
+{{- with .Linux.ARM64}}
+    on_arm do
+      url "{{.URL}}"
+      sha256 "{{.SHA256}}"
+    end
+{{- end}}
+{{- with .Linux.AMD64}}
+    on_intel do
+      url "{{.URL}}"
+      sha256 "{{.SHA256}}"
+    end
+{{- end}}
+  end
+{{- end}}
+
+  def install
+    bin.install "{{.Binary}}"
+  end
+
+  test do
+    system "#{bin}/{{.Binary}}", "--version"
+  end
+end
+`
+
+// defaultFormulaCommitMessage is the default commit message template.
+const defaultFormulaCommitMessage = "{{.Formula}} {{.Version}}"
+
+// HomebrewConfig configures updating a formula in a Homebrew tap.
+type HomebrewConfig struct {
+	// Enabled turns on the formula update in the post-publish hook.
+	Enabled bool `json:"enabled"`
+	// Tap is the tap repository and how to commit to it.
+	Tap RepoTarget `json:"tap"`
+	// Formula is the formula name; it defaults to the repository name.
+	Formula string `json:"formula,omitempty"`
+	// Path is the formula path in the tap; it defaults to Formula/<formula>.rb.
+	Path string `json:"path,omitempty"`
+	// Template is an inline formula template; TemplateFile reads it from a file.
+	Template     string `json:"template,omitempty"`
+	TemplateFile string `json:"template_file,omitempty"`
+	// Description, Homepage, License and Binary fill the default template.
+	Description string `json:"description,omitempty"`
+	Homepage    string `json:"homepage,omitempty"`
+	License     string `json:"license,omitempty"`
+	Binary      string `json:"binary,omitempty"`
+	// CommitMessage is a template for the commit message and pull request title.
+	CommitMessage string `json:"commit_message,omitempty"`
+}
+
+// packageAsset is a release archive referenced by a package manifest.
+type packageAsset struct {
+	Name   string
+	URL    string
+	SHA256 string
+}
+
+// formulaPlatform holds the archives of one operating system.
+type formulaPlatform struct {
+	AMD64 *packageAsset
+	ARM64 *packageAsset
+}
+
+// formulaData is the data available to formula templates.
+type formulaData struct {
+	templateData
+	Formula     string
+	Class       string
+	Description string
+	Homepage    string
+	License     string
+	Binary      string
+	Darwin      formulaPlatform
+	Linux       formulaPlatform
+}
+
+// parseHomebrewConfig parses the homebrew section of the configuration.
+func parseHomebrewConfig(raw map[string]any) HomebrewConfig {
+	parser := helpers.NewConfigParser(raw)
+	return HomebrewConfig{
+		Enabled:       parser.GetBool("enabled", false),
+		Tap:           parseRepoTarget(helpers.NewConfigParser(parser.GetMap("tap"))),
+		Formula:       parser.GetString("formula", "", ""),
+		Path:          parser.GetString("path", "", ""),
+		Template:      parser.GetString("template", "", ""),
+		TemplateFile:  parser.GetString("template_file", "", ""),
+		Description:   parser.GetString("description", "", ""),
+		Homepage:      parser.GetString("homepage", "", ""),
+		License:       parser.GetString("license", "", ""),
+		Binary:        parser.GetString("binary", "", ""),
+		CommitMessage: parser.GetString("commit_message", "", defaultFormulaCommitMessage),
+	}
+}
+
+// validateHomebrewConfig validates the homebrew section of the configuration.
+func validateHomebrewConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	if raw == nil {
+		return
+	}
+
+	cfg := parseHomebrewConfig(raw)
+	if !cfg.Enabled {
+		return
+	}
+
+	validateRepoTarget(vb, "homebrew.tap", cfg.Tap)
+	if cfg.Template != "" && cfg.TemplateFile != "" {
+		vb.AddError("homebrew.template", "template and template_file are mutually exclusive")
+	}
+
+	sample := sampleFormulaData()
+	validateTemplate(vb, "homebrew.template", cfg.Template, sample)
+	validateTemplate(vb, "homebrew.commit_message", cfg.CommitMessage, sample)
+}
+
+// sampleFormulaData returns formula data with every platform populated, used
+// to validate templates.
+func sampleFormulaData() formulaData {
+	asset := &packageAsset{Name: "app.tar.gz", URL: "https://example.com/app.tar.gz", SHA256: strings.Repeat("0", 64)}
+	return formulaData{
+		Darwin: formulaPlatform{AMD64: asset, ARM64: asset},
+		Linux:  formulaPlatform{AMD64: asset, ARM64: asset},
+	}
+}
+
+// formulaClass returns the Ruby class name Homebrew expects for a formula,
+// e.g. "my-app" becomes "MyApp".
+func formulaClass(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case r == '@':
+			b.WriteString("AT")
+			upper = true
+		case r == '-' || r == '_' || r == '.' || r == '+':
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// rubyString quotes s as a double-quoted Ruby string literal, escaping
+// quotes, backslashes, control characters and #{} interpolation.
+func rubyString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\', '#':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// newFormulaData collects the darwin and linux archives of a release.
+func newFormulaData(hb HomebrewConfig, data templateData, artifacts []plugin.Artifact) (formulaData, error) {
+	formula := hb.Formula
+	if formula == "" {
+		formula = data.Repo
+	}
+	binary := hb.Binary
+	if binary == "" {
+		binary = formula
+	}
+	homepage := hb.Homepage
+	if homepage == "" {
+		homepage = data.ReleaseURL
+	}
+
+	fd := formulaData{
+		templateData: data,
+		Formula:      formula,
+		Class:        formulaClass(formula),
+		Description:  hb.Description,
+		Homepage:     homepage,
+		License:      hb.License,
+		Binary:       binary,
+	}
+
+	found := false
+	for _, a := range artifacts {
+		osName, arch := classifyAsset(a.Name)
+		var platform *formulaPlatform
+		switch osName {
+		case platformDarwin:
+			platform = &fd.Darwin
+		case platformLinux:
+			platform = &fd.Linux
+		default:
+			continue
+		}
+		if a.Checksum == "" {
+			return fd, fmt.Errorf("no SHA-256 known for asset %s", a.Name)
+		}
+
+		asset := &packageAsset{Name: a.Name, URL: a.Path, SHA256: a.Checksum}
+		switch arch {
+		case archARM64:
+			platform.ARM64 = asset
+		case archAMD64:
+			platform.AMD64 = asset
+		default:
+			continue
+		}
+		found = true
+	}
+	if !found {
+		return fd, fmt.Errorf("no darwin or linux archive among the release assets")
+	}
+	return fd, nil
+}
+
+// updateHomebrew renders the formula for a published release and commits it
+// to the tap. In dry-run the formula is only rendered into the outputs.
+func (p *GitHubPlugin) updateHomebrew(ctx context.Context, cfg *Config, client *github.Client, data templateData, artifacts []plugin.Artifact, resp *plugin.ExecuteResponse, dryRun bool) error {
+	hb := cfg.Homebrew
+
+	fd, err := newFormulaData(hb, data, artifacts)
+	if err != nil {
+		return err
+	}
+
+	text := hb.Template
+	if hb.TemplateFile != "" {
+		b, err := os.ReadFile(hb.TemplateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read formula template: %w", err)
+		}
+		text = string(b)
+	}
+	if text == "" {
+		text = defaultFormulaTemplate
+	}
+
+	formula, err := renderTemplate("homebrew.template", text, fd)
+	if err != nil {
+		return fmt.Errorf("failed to render formula: %w", err)
+	}
+	message, err := renderTemplate("homebrew.commit_message", hb.CommitMessage, fd)
+	if err != nil {
+		return fmt.Errorf("failed to render commit message: %w", err)
+	}
+
+	path := hb.Path
+	if path == "" {
+		path = fmt.Sprintf("Formula/%s.rb", fd.Formula)
+	}
+	resp.Outputs["homebrew_formula_path"] = path
+
+	if dryRun {
+		loggerFromContext(ctx).Info("dry run, skipping formula update", "tap", hb.Tap.Repository, "path", path)
+		resp.Outputs["homebrew_formula"] = formula
+		return nil
+	}
+
+	return p.publishFiles(ctx, cfg, client, hb.Tap, "homebrew", resp, repoChange{
+		Files:   []repoFile{{Path: path, Content: formula}},
+		Message: message,
+		Branch:  fmt.Sprintf("relicta/%s-%s", fd.Formula, data.Tag),
+		Body:    fmt.Sprintf("Updates the %s formula to %s.\n\nRelease: %s", fd.Formula, data.Tag, data.ReleaseURL),
+	})
+}
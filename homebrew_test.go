@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+
+	"github.com/relicta-tech/plugin-github/internal/ghfake"
+)
+
+// TestFormulaClass tests Ruby class names for formulae.
+func TestFormulaClass(t *testing.T) {
+	tests := map[string]string{
+		"relicta":      "Relicta",
+		"my-app":       "MyApp",
+		"foo_bar.baz":  "FooBarBaz",
+		"python@3.12":  "PythonAT312",
+		"already-Done": "AlreadyDone",
+	}
+	for name, expected := range tests {
+		if got := formulaClass(name); got != expected {
+			t.Errorf("formulaClass(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+// TestRubyString tests quoting of formula fields.
+func TestRubyString(t *testing.T) {
+	tests := map[string]string{
+		"An app":                   `"An app"`,
+		`The "best" app`:           `"The \"best\" app"`,
+		`C:\tools #{system("id")}`: `"C:\\tools \#{system(\"id\")}"`,
+		"line\nbreak":              `"line\nbreak"`,
+	}
+	for s, expected := range tests {
+		if got := rubyString(s); got != expected {
+			t.Errorf("rubyString(%q) = %s, expected %s", s, got, expected)
+		}
+	}
+}
+
+// homebrewRequest returns a post-publish request that updates a tap formula.
+func homebrewRequest(t *testing.T, cfg map[string]any, tap map[string]any) plugin.ExecuteRequest {
+	t.Helper()
+	dir := writeAssets(t, map[string]string{
+		"app_darwin_arm64.tar.gz": "darwin arm64",
+		"app_darwin_amd64.tar.gz": "darwin amd64",
+		"app_linux_amd64.tar.gz":  "linux amd64",
+		"checksums.txt":           "sums",
+	})
+	cfg["assets"] = []any{dir + "/*"}
+	cfg["homebrew"] = map[string]any{
+		"enabled":     true,
+		"tap":         tap,
+		"formula":     "app",
+		"description": "An app",
+		"license":     "MIT",
+	}
+	return plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  cfg,
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	}
+}
+
+// TestExecuteHomebrewDirect tests committing a formula straight to the tap branch.
+func TestExecuteHomebrewDirect(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	req := homebrewRequest(t, fakeConfig(fake, nil), map[string]any{"repository": "test-owner/homebrew-tap"})
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	formula, ok := fake.File("test-owner", "homebrew-tap", "main", "Formula/app.rb")
+	if !ok {
+		t.Fatal("expected formula to be committed to main")
+	}
+	for _, want := range []string{
+		"class App < Formula",
+		`version "1.2.0"`,
+		`license "MIT"`,
+		"/app_darwin_arm64.tar.gz\"",
+		sha256Hex("darwin arm64"),
+		sha256Hex("linux amd64"),
+		`bin.install "app"`,
+	} {
+		if !strings.Contains(formula, want) {
+			t.Errorf("expected formula to contain %q:\n%s", want, formula)
+		}
+	}
+	if strings.Contains(formula, "checksums.txt") {
+		t.Error("expected non-archive assets to be ignored")
+	}
+	if resp.Outputs["homebrew_commit_url"] == nil {
+		t.Errorf("expected commit URL in outputs, got %v", resp.Outputs)
+	}
+}
+
+// TestExecuteHomebrewPullRequest tests opening a pull request, and that reruns reuse it.
+func TestExecuteHomebrewPullRequest(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.PutFile("test-owner", "homebrew-tap", "main", "Formula/app.rb", "old formula")
+	p := &GitHubPlugin{}
+
+	tap := map[string]any{"repository": "test-owner/homebrew-tap", "pull_request": true}
+	resp, err := p.Execute(context.Background(), homebrewRequest(t, fakeConfig(fake, nil), tap))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	pulls := fake.PullRequests("test-owner", "homebrew-tap")
+	if len(pulls) != 1 || pulls[0].GetHead().GetRef() != "relicta/app-v1.2.0" || pulls[0].GetTitle() != "app 1.2.0" {
+		t.Fatalf("unexpected pull requests: %v", pulls)
+	}
+	if resp.Outputs["homebrew_pull_request_url"] != pulls[0].GetHTMLURL() {
+		t.Errorf("unexpected outputs %v", resp.Outputs)
+	}
+	if content, _ := fake.File("test-owner", "homebrew-tap", "main", "Formula/app.rb"); content != "old formula" {
+		t.Error("expected main to be untouched")
+	}
+
+	// Rendering the same formula again changes nothing
+	formula, _ := fake.File("test-owner", "homebrew-tap", "relicta/app-v1.2.0", "Formula/app.rb")
+	if _, err := commitFiles(context.Background(), mustClient(t, p, fake), RepoTarget{Repository: "test-owner/homebrew-tap", PullRequest: true}, repoChange{
+		Files:   []repoFile{{Path: "Formula/app.rb", Content: formula}},
+		Message: "app 1.2.0",
+		Branch:  "relicta/app-v1.2.0",
+	}); err != nil {
+		t.Fatalf("commitFiles: %v", err)
+	}
+	if n := len(fake.PullRequests("test-owner", "homebrew-tap")); n != 1 {
+		t.Errorf("expected the pull request to be reused, got %d", n)
+	}
+}
+
+// TestExecuteHomebrewPullRequestRetry tests that a rerun opens the pull
+// request of a branch whose commit succeeded but whose pull request failed.
+func TestExecuteHomebrewPullRequestRetry(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.PutFile("test-owner", "homebrew-tap", "main", "Formula/app.rb", "old formula")
+	fake.InjectFault(ghfake.Fault{Method: "POST", Path: "/pulls", Status: http.StatusUnprocessableEntity, Times: 1})
+	p := &GitHubPlugin{}
+
+	tap := map[string]any{"repository": "test-owner/homebrew-tap", "pull_request": true}
+	resp, err := p.Execute(context.Background(), homebrewRequest(t, fakeConfig(fake, nil), tap))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || len(fake.PullRequests("test-owner", "homebrew-tap")) != 0 {
+		t.Fatalf("expected the pull request to fail, got %+v", resp)
+	}
+
+	formula, _ := fake.File("test-owner", "homebrew-tap", "relicta/app-v1.2.0", "Formula/app.rb")
+	result, err := commitFiles(context.Background(), mustClient(t, p, fake), RepoTarget{Repository: "test-owner/homebrew-tap", PullRequest: true}, repoChange{
+		Files:   []repoFile{{Path: "Formula/app.rb", Content: formula}},
+		Message: "app 1.2.0",
+		Branch:  "relicta/app-v1.2.0",
+	})
+	if err != nil {
+		t.Fatalf("commitFiles: %v", err)
+	}
+	pulls := fake.PullRequests("test-owner", "homebrew-tap")
+	if result.Changed || len(pulls) != 1 || result.PullRequestURL != pulls[0].GetHTMLURL() {
+		t.Errorf("expected the rerun to open the pull request, got %v", pulls)
+	}
+}
+
+// TestExecuteHomebrewDryRun tests that dry-run renders the formula from local files.
+func TestExecuteHomebrewDryRun(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	req := homebrewRequest(t, fakeConfig(fake, nil), map[string]any{"repository": "test-owner/homebrew-tap"})
+	req.DryRun = true
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	formula, _ := resp.Outputs["homebrew_formula"].(string)
+	if !strings.Contains(formula, sha256Hex("darwin amd64")) {
+		t.Errorf("expected rendered formula in outputs, got %q", formula)
+	}
+	if n := fake.CountRequests("PUT", "/contents/"); n != 0 {
+		t.Errorf("expected no commits in dry run, got %d", n)
+	}
+}
+
+// TestValidateHomebrew tests validation of the homebrew section.
+func TestValidateHomebrew(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token": "ghp_test",
+		"homebrew": map[string]any{
+			"enabled":  true,
+			"tap":      map[string]any{"repository": "homebrew-tap"},
+			"template": "{{.Nope}}",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 2 {
+		t.Errorf("expected repository and template errors, got %+v", resp.Errors)
+	}
+}
+
+// mustClient returns a GitHub client for the fake API.
+func mustClient(t *testing.T, p *GitHubPlugin, fake *ghfake.Server) *github.Client {
+	t.Helper()
+	client, err := p.getClient(context.Background(), p.parseConfig(map[string]any{"token": "ghp_test_token", "base_url": fake.URL(), "log_level": "off"}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
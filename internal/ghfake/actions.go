@@ -0,0 +1,88 @@
+package ghfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Dispatch is a repository_dispatch event received by the fake.
+type Dispatch struct {
+	EventType     string         `json:"event_type"`
+	ClientPayload map[string]any `json:"client_payload"`
+}
+
+// WorkflowDispatch is a workflow_dispatch event received by the fake.
+type WorkflowDispatch struct {
+	Workflow string         `json:"-"`
+	Ref      string         `json:"ref"`
+	Inputs   map[string]any `json:"inputs"`
+}
+
+func (s *Server) actionsRoutes() {
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/dispatches", s.createDispatch)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/actions/workflows/{workflow}/dispatches", s.createWorkflowDispatch)
+}
+
+// AddWorkflow registers a workflow file, e.g. "release.yml", that accepts
+// workflow_dispatch events.
+func (s *Server) AddWorkflow(owner, repo, workflow string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, repo).workflows[workflow] = true
+}
+
+// Dispatches returns the repository_dispatch events of a repository.
+func (s *Server) Dispatches(owner, repo string) []Dispatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Dispatch(nil), s.repo(owner, repo).dispatches...)
+}
+
+// WorkflowDispatches returns the workflow_dispatch events of a repository.
+func (s *Server) WorkflowDispatches(owner, repo string) []WorkflowDispatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]WorkflowDispatch(nil), s.repo(owner, repo).workflowDispatches...)
+}
+
+func (s *Server) createDispatch(w http.ResponseWriter, req *http.Request) {
+	var body Dispatch
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.EventType == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	r.dispatches = append(r.dispatches, body)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) createWorkflowDispatch(w http.ResponseWriter, req *http.Request) {
+	var body WorkflowDispatch
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Ref == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	workflow := req.PathValue("workflow")
+	if !r.workflows[workflow] {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if _, ok := r.refs["refs/heads/"+body.Ref]; !ok && body.Ref != defaultBranch {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("No ref found for: %s", body.Ref))
+		return
+	}
+
+	body.Workflow = workflow
+	r.workflowDispatches = append(r.workflowDispatches, body)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,200 @@
+package ghfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// appTokenPrefix is the prefix of GitHub App installation tokens. Only App
+// tokens may create check runs.
+const appTokenPrefix = "ghs_"
+
+func (s *Server) checkRoutes() {
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/statuses/{sha}", s.createStatus)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/commits/{ref}/statuses", s.listStatuses)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/check-runs", s.createCheckRun)
+	s.mux.HandleFunc("PATCH /repos/{owner}/{repo}/check-runs/{id}", s.updateCheckRun)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/commits/{ref}/check-runs", s.listCheckRuns)
+}
+
+// CommitStatuses returns the statuses of a commit in creation order.
+func (s *Server) CommitStatuses(owner, repo, sha string) []*github.RepoStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*github.RepoStatus(nil), s.repo(owner, repo).statuses[sha]...)
+}
+
+// CheckRuns returns the check runs of a repository in creation order.
+func (s *Server) CheckRuns(owner, repo string) []*github.CheckRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*github.CheckRun(nil), s.repo(owner, repo).checkRuns...)
+}
+
+// commitStates are the states accepted for commit statuses.
+var commitStates = map[string]bool{"error": true, "failure": true, "pending": true, "success": true}
+
+func (s *Server) createStatus(w http.ResponseWriter, req *http.Request) {
+	var body github.RepoStatus
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || !commitStates[body.GetState()] {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	sha := req.PathValue("sha")
+	if body.Context == nil {
+		body.Context = github.String("default")
+	}
+	now := github.Timestamp{Time: time.Now()}
+	body.ID = github.Int64(s.id())
+	body.CreatedAt = &now
+	body.UpdatedAt = &now
+	r.statuses[sha] = append(r.statuses[sha], &body)
+	writeJSON(w, http.StatusCreated, &body)
+}
+
+func (s *Server) listStatuses(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	statuses := r.statuses[s.resolveCommit(r, req.PathValue("ref"))]
+	out := make([]*github.RepoStatus, len(statuses))
+	for i, st := range statuses {
+		out[len(statuses)-1-i] = st
+	}
+	start, end := paginate(w, req, len(out))
+	writeJSON(w, http.StatusOK, out[start:end])
+}
+
+// resolveCommit returns the SHA a branch or tag name points to, or ref
+// itself. The caller must hold s.mu.
+func (s *Server) resolveCommit(r *repository, ref string) string {
+	for _, prefix := range []string{"refs/tags/", "refs/heads/"} {
+		if sha, ok := r.refs[prefix+ref]; ok {
+			return sha
+		}
+	}
+	return ref
+}
+
+// checkRunRequest is the body of check run create and update requests.
+type checkRunRequest struct {
+	Name        *string                `json:"name"`
+	HeadSHA     *string                `json:"head_sha"`
+	DetailsURL  *string                `json:"details_url"`
+	ExternalID  *string                `json:"external_id"`
+	Status      *string                `json:"status"`
+	Conclusion  *string                `json:"conclusion"`
+	CompletedAt *github.Timestamp      `json:"completed_at"`
+	Output      *github.CheckRunOutput `json:"output"`
+}
+
+// apply copies the set fields of the request to run.
+func (c *checkRunRequest) apply(run *github.CheckRun) {
+	if c.Name != nil {
+		run.Name = c.Name
+	}
+	if c.DetailsURL != nil {
+		run.DetailsURL = c.DetailsURL
+	}
+	if c.ExternalID != nil {
+		run.ExternalID = c.ExternalID
+	}
+	if c.Status != nil {
+		run.Status = c.Status
+	}
+	if c.Conclusion != nil {
+		run.Conclusion = c.Conclusion
+		run.Status = github.String("completed")
+	}
+	if c.Output != nil {
+		run.Output = c.Output
+	}
+	if run.GetStatus() == "completed" && run.CompletedAt == nil {
+		now := github.Timestamp{Time: time.Now()}
+		run.CompletedAt = &now
+	}
+}
+
+func (s *Server) createCheckRun(w http.ResponseWriter, req *http.Request) {
+	if !strings.Contains(req.Header.Get("Authorization"), appTokenPrefix) {
+		writeError(w, http.StatusForbidden, "Resource not accessible by integration")
+		return
+	}
+
+	var body checkRunRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Name == nil || body.HeadSHA == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	now := github.Timestamp{Time: time.Now()}
+	run := &github.CheckRun{
+		ID:        github.Int64(s.id()),
+		HeadSHA:   body.HeadSHA,
+		Status:    github.String("queued"),
+		StartedAt: &now,
+	}
+	body.apply(run)
+	run.HTMLURL = github.String(fmt.Sprintf("%s%s/%s/runs/%d", s.URL(), r.owner, r.name, run.GetID()))
+	r.checkRuns = append(r.checkRuns, run)
+	writeJSON(w, http.StatusCreated, run)
+}
+
+func (s *Server) updateCheckRun(w http.ResponseWriter, req *http.Request) {
+	var body checkRunRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	id, _ := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	for _, run := range r.checkRuns {
+		if run.GetID() == id {
+			body.apply(run)
+			writeJSON(w, http.StatusOK, run)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) listCheckRuns(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	sha := s.resolveCommit(r, req.PathValue("ref"))
+	name := req.URL.Query().Get("check_name")
+
+	runs := []*github.CheckRun{}
+	for i := len(r.checkRuns) - 1; i >= 0; i-- {
+		run := r.checkRuns[i]
+		if run.GetHeadSHA() == sha && (name == "" || run.GetName() == name) {
+			runs = append(runs, run)
+		}
+	}
+	writeJSON(w, http.StatusOK, &github.ListCheckRunsResults{
+		Total:     github.Int(len(runs)),
+		CheckRuns: runs,
+	})
+}
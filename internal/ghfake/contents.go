@@ -0,0 +1,313 @@
+package ghfake
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// defaultBranch is the default branch of every fake repository.
+const defaultBranch = "main"
+
+// AuthenticatedUser is the login of the user every token authenticates as;
+// forks are created in its account.
+const AuthenticatedUser = "relicta-bot"
+
+func (s *Server) contentRoutes() {
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}", s.getRepository)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/contents/{path...}", s.getContents)
+	s.mux.HandleFunc("PUT /repos/{owner}/{repo}/contents/{path...}", s.putContents)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/pulls", s.listPulls)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/pulls", s.createPull)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/forks", s.createFork)
+	s.mux.HandleFunc("GET /user", s.getUser)
+}
+
+// PutFile seeds a file on a branch, creating the branch from the default
+// branch if needed.
+func (s *Server) PutFile(owner, repo, branch, path, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(owner, repo)
+	tree, ok := s.branchTree(r, branch)
+	if !ok {
+		tree, _ = s.branchTree(r, defaultBranch)
+	}
+	tree = maps.Clone(tree)
+	tree[path] = []byte(content)
+	r.refs["refs/heads/"+branch] = s.commit(tree)
+}
+
+// File returns the content of a file on a branch.
+func (s *Server) File(owner, repo, branch, path string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tree, ok := s.branchTree(s.repo(owner, repo), branch)
+	if !ok {
+		return "", false
+	}
+	content, ok := tree[path]
+	return string(content), ok
+}
+
+// PullRequests returns the pull requests of a repository in creation order.
+func (s *Server) PullRequests(owner, repo string) []*github.PullRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*github.PullRequest(nil), s.repo(owner, repo).pulls...)
+}
+
+// commit stores a tree as a new commit and returns its SHA. The caller must
+// hold s.mu.
+func (s *Server) commit(tree map[string][]byte) string {
+	sha := fmt.Sprintf("%040x", s.id())
+	s.commits[sha] = tree
+	return sha
+}
+
+// branchTree returns the files at the head of a branch. The default branch
+// is created empty on first use. The caller must hold s.mu.
+func (s *Server) branchTree(r *repository, branch string) (map[string][]byte, bool) {
+	sha, ok := r.refs["refs/heads/"+branch]
+	if !ok {
+		if branch != defaultBranch {
+			return nil, false
+		}
+		sha = s.commit(map[string][]byte{})
+		r.refs["refs/heads/"+branch] = sha
+	}
+	tree, ok := s.commits[sha]
+	if !ok {
+		// Refs seeded with arbitrary SHAs point at an empty tree
+		tree = map[string][]byte{}
+		s.commits[sha] = tree
+	}
+	return tree, true
+}
+
+// blobSHA returns the SHA reported for file content.
+func blobSHA(content []byte) string {
+	sum := sha1.Sum(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Server) getRepository(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	writeJSON(w, http.StatusOK, &github.Repository{
+		Name:          github.String(r.name),
+		FullName:      github.String(r.owner + "/" + r.name),
+		NodeID:        github.String(r.nodeID),
+		DefaultBranch: github.String(defaultBranch),
+		HTMLURL:       github.String(fmt.Sprintf("%s%s/%s", s.URL(), r.owner, r.name)),
+	})
+}
+
+func (s *Server) getUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, &github.User{Login: github.String(AuthenticatedUser)})
+}
+
+// createFork forks a repository into the account of AuthenticatedUser,
+// copying its branches. Like GitHub, it answers 202 Accepted and returns the
+// existing fork when there is one.
+func (s *Server) createFork(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	s.branchTree(r, defaultBranch)
+	fork := s.repo(AuthenticatedUser, r.name)
+	if len(fork.refs) == 0 {
+		for ref, sha := range r.refs {
+			if strings.HasPrefix(ref, "refs/heads/") {
+				fork.refs[ref] = sha
+			}
+		}
+	}
+	writeJSON(w, http.StatusAccepted, &github.Repository{
+		Name:          github.String(fork.name),
+		FullName:      github.String(fork.owner + "/" + fork.name),
+		Owner:         &github.User{Login: github.String(fork.owner)},
+		DefaultBranch: github.String(defaultBranch),
+		Fork:          github.Bool(true),
+	})
+}
+
+func (s *Server) getContents(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	branch := req.URL.Query().Get("ref")
+	if branch == "" {
+		branch = defaultBranch
+	}
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+
+	path := req.PathValue("path")
+	tree, ok := s.branchTree(s.repo(req.PathValue("owner"), req.PathValue("repo")), branch)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No commit found for the ref %s", branch))
+		return
+	}
+	content, ok := tree[path]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &github.RepositoryContent{
+		Type:     github.String("file"),
+		Name:     github.String(path[strings.LastIndex(path, "/")+1:]),
+		Path:     github.String(path),
+		SHA:      github.String(blobSHA(content)),
+		Encoding: github.String("base64"),
+		Content:  github.String(base64.StdEncoding.EncodeToString(content)),
+	})
+}
+
+func (s *Server) putContents(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Message string `json:"message"`
+		Content string `json:"content"`
+		SHA     string `json:"sha"`
+		Branch  string `json:"branch"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Message == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(body.Content)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "content is not valid Base64")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	branch := body.Branch
+	if branch == "" {
+		branch = defaultBranch
+	}
+	path := req.PathValue("path")
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	tree, ok := s.branchTree(r, branch)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Branch %s not found", branch))
+		return
+	}
+
+	status := http.StatusCreated
+	if existing, ok := tree[path]; ok {
+		if body.SHA != blobSHA(existing) {
+			writeError(w, http.StatusConflict, fmt.Sprintf("%s does not match %s", path, body.SHA))
+			return
+		}
+		status = http.StatusOK
+	} else if body.SHA != "" {
+		writeError(w, http.StatusUnprocessableEntity, "sha does not match any file")
+		return
+	}
+
+	tree = maps.Clone(tree)
+	tree[path] = content
+	sha := s.commit(tree)
+	r.refs["refs/heads/"+branch] = sha
+
+	writeJSON(w, status, &github.RepositoryContentResponse{
+		Content: &github.RepositoryContent{
+			Path: github.String(path),
+			SHA:  github.String(blobSHA(content)),
+		},
+		Commit: github.Commit{
+			SHA:     github.String(sha),
+			Message: github.String(body.Message),
+			HTMLURL: github.String(fmt.Sprintf("%s%s/%s/commit/%s", s.URL(), r.owner, r.name, sha)),
+		},
+	})
+}
+
+func (s *Server) listPulls(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := req.URL.Query()
+	state := q.Get("state")
+	if state == "" {
+		state = "open"
+	}
+
+	var pulls []*github.PullRequest
+	for _, pr := range s.repo(req.PathValue("owner"), req.PathValue("repo")).pulls {
+		if state != "all" && pr.GetState() != state {
+			continue
+		}
+		if head := q.Get("head"); head != "" && pr.GetHead().GetLabel() != head {
+			continue
+		}
+		if base := q.Get("base"); base != "" && pr.GetBase().GetRef() != base {
+			continue
+		}
+		pulls = append(pulls, pr)
+	}
+
+	start, end := paginate(w, req, len(pulls))
+	writeJSON(w, http.StatusOK, pulls[start:end])
+}
+
+func (s *Server) createPull(w http.ResponseWriter, req *http.Request) {
+	var body github.NewPullRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.GetTitle() == "" || body.GetHead() == "" || body.GetBase() == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	// The head is a branch of the repository or "owner:branch" of a fork
+	headRepo, head := r, body.GetHead()
+	if owner, after, ok := strings.Cut(head, ":"); ok {
+		head = after
+		if owner != r.owner {
+			headRepo = s.repo(owner, r.name)
+		}
+	}
+	label := headRepo.owner + ":" + head
+	if _, ok := s.branchTree(headRepo, head); !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: head does not exist")
+		return
+	}
+	for _, pr := range r.pulls {
+		if pr.GetState() == "open" && pr.GetHead().GetLabel() == label && pr.GetBase().GetRef() == body.GetBase() {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("A pull request already exists for %s.", label))
+			return
+		}
+	}
+
+	number := len(r.pulls) + len(r.issues) + 1
+	pr := &github.PullRequest{
+		ID:      github.Int64(s.id()),
+		Number:  github.Int(number),
+		State:   github.String("open"),
+		Title:   github.String(body.GetTitle()),
+		Body:    github.String(body.GetBody()),
+		HTMLURL: github.String(fmt.Sprintf("%s%s/%s/pull/%d", s.URL(), r.owner, r.name, number)),
+		Head:    &github.PullRequestBranch{Ref: github.String(head), Label: github.String(label)},
+		Base:    &github.PullRequestBranch{Ref: github.String(body.GetBase())},
+	}
+	r.pulls = append(r.pulls, pr)
+	writeJSON(w, http.StatusCreated, pr)
+}
@@ -0,0 +1,201 @@
+package ghfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func (s *Server) deploymentRoutes() {
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/deployments", s.listDeployments)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/deployments", s.createDeployment)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/deployments/{id}/statuses", s.listDeploymentStatuses)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/deployments/{id}/statuses", s.createDeploymentStatus)
+}
+
+// Deployments returns the deployments of a repository, newest first.
+func (s *Server) Deployments(owner, repo string) []*github.Deployment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deployments := s.repo(owner, repo).deployments
+	out := make([]*github.Deployment, len(deployments))
+	for i, d := range deployments {
+		out[len(deployments)-1-i] = d
+	}
+	return out
+}
+
+// DeploymentStatuses returns the statuses of a deployment in creation order.
+func (s *Server) DeploymentStatuses(deploymentID int64) []*github.DeploymentStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*github.DeploymentStatus(nil), s.deploymentStatuses[deploymentID]...)
+}
+
+func (s *Server) listDeployments(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := req.URL.Query()
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+
+	var out []*github.Deployment
+	for i := len(r.deployments) - 1; i >= 0; i-- {
+		d := r.deployments[i]
+		if ref := q.Get("ref"); ref != "" && d.GetRef() != ref {
+			continue
+		}
+		if env := q.Get("environment"); env != "" && d.GetEnvironment() != env {
+			continue
+		}
+		if task := q.Get("task"); task != "" && d.GetTask() != task {
+			continue
+		}
+		out = append(out, d)
+	}
+
+	start, end := paginate(w, req, len(out))
+	writeJSON(w, http.StatusOK, out[start:end])
+}
+
+func (s *Server) createDeployment(w http.ResponseWriter, req *http.Request) {
+	var body github.DeploymentRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.GetRef() == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	sha, ok := r.refs["refs/tags/"+body.GetRef()]
+	if !ok {
+		sha, ok = r.refs["refs/heads/"+body.GetRef()]
+	}
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("No ref found for: %s", body.GetRef()))
+		return
+	}
+
+	environment := body.GetEnvironment()
+	if environment == "" {
+		environment = "production"
+	}
+	task := body.GetTask()
+	if task == "" {
+		task = "deploy"
+	}
+
+	id := s.id()
+	now := github.Timestamp{Time: time.Now()}
+	d := &github.Deployment{
+		ID:          github.Int64(id),
+		SHA:         github.String(sha),
+		Ref:         github.String(body.GetRef()),
+		Task:        github.String(task),
+		Environment: github.String(environment),
+		Description: body.Description,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+		URL:         github.String(fmt.Sprintf("%srepos/%s/%s/deployments/%d", s.URL(), r.owner, r.name, id)),
+	}
+	if body.Payload != nil {
+		raw, _ := json.Marshal(body.Payload)
+		d.Payload = raw
+	}
+	r.deployments = append(r.deployments, d)
+	writeJSON(w, http.StatusCreated, d)
+}
+
+// deployment returns a deployment of r by path ID. The caller must hold s.mu.
+func (s *Server) deployment(r *repository, rawID string) *github.Deployment {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil
+	}
+	for _, d := range r.deployments {
+		if d.GetID() == id {
+			return d
+		}
+	}
+	return nil
+}
+
+func (s *Server) listDeploymentStatuses(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.deployment(s.repo(req.PathValue("owner"), req.PathValue("repo")), req.PathValue("id"))
+	if d == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	statuses := s.deploymentStatuses[d.GetID()]
+	out := make([]*github.DeploymentStatus, len(statuses))
+	for i, st := range statuses {
+		out[len(statuses)-1-i] = st
+	}
+	start, end := paginate(w, req, len(out))
+	writeJSON(w, http.StatusOK, out[start:end])
+}
+
+// deploymentStates are the states accepted for deployment statuses.
+var deploymentStates = map[string]bool{
+	"error": true, "failure": true, "inactive": true, "in_progress": true,
+	"queued": true, "pending": true, "success": true,
+}
+
+func (s *Server) createDeploymentStatus(w http.ResponseWriter, req *http.Request) {
+	var body github.DeploymentStatusRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || !deploymentStates[body.GetState()] {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	d := s.deployment(r, req.PathValue("id"))
+	if d == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	now := github.Timestamp{Time: time.Now()}
+	st := &github.DeploymentStatus{
+		ID:             github.Int64(s.id()),
+		State:          body.State,
+		Description:    body.Description,
+		Environment:    d.Environment,
+		EnvironmentURL: body.EnvironmentURL,
+		LogURL:         body.LogURL,
+		CreatedAt:      &now,
+		UpdatedAt:      &now,
+	}
+	s.deploymentStatuses[d.GetID()] = append(s.deploymentStatuses[d.GetID()], st)
+
+	// A successful deployment marks older ones in the environment inactive
+	if body.GetState() == "success" && (body.AutoInactive == nil || body.GetAutoInactive()) {
+		for _, other := range r.deployments {
+			if other.GetID() == d.GetID() || other.GetEnvironment() != d.GetEnvironment() {
+				continue
+			}
+			statuses := s.deploymentStatuses[other.GetID()]
+			if len(statuses) > 0 && statuses[len(statuses)-1].GetState() == "success" {
+				s.deploymentStatuses[other.GetID()] = append(statuses, &github.DeploymentStatus{
+					ID:    github.Int64(s.id()),
+					State: github.String("inactive"),
+				})
+			}
+		}
+	}
+	writeJSON(w, http.StatusCreated, st)
+}
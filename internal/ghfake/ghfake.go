@@ -0,0 +1,300 @@
+// Package ghfake provides a stateful in-memory fake of the GitHub REST and
+// GraphQL APIs for testing the plugin end to end without network access.
+//
+// The fake serves both the github.com URL layout and the GitHub Enterprise
+// layout (/api/v3, /api/uploads, /api/graphql), so the plugin can be pointed at
+// it with its base_url option. Repositories are created on first use with an
+// empty default branch named "main".
+package ghfake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// defaultRateLimit is the request budget reported in rate limit headers.
+const defaultRateLimit = 5000
+
+// Fault describes an injected failure for matching requests.
+type Fault struct {
+	// Method restricts the fault to an HTTP method; empty matches any method.
+	Method string
+	// Path restricts the fault to request paths containing this string;
+	// empty matches any path.
+	Path string
+	// Status is the HTTP status to respond with, e.g. 502.
+	Status int
+	// RateLimited responds with a primary rate limit error.
+	RateLimited bool
+	// Delay is applied before the request is handled (or failed), e.g. to
+	// simulate slow uploads.
+	Delay time.Duration
+	// Times is the number of requests the fault applies to; 0 means always.
+	Times int
+}
+
+// Request is a request received by the fake.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+// Server is an in-memory GitHub API.
+type Server struct {
+	srv *httptest.Server
+	mux *http.ServeMux
+
+	mu            sync.Mutex
+	nextID        int64
+	requests      []Request
+	faults        []*Fault
+	rateRemaining int
+	repos         map[string]*repository
+	assets        map[int64]*storedAsset
+	commits       map[string]map[string][]byte
+
+	deploymentStatuses map[int64][]*github.DeploymentStatus
+}
+
+// repository holds the state of a single repository.
+type repository struct {
+	owner, name string
+	nodeID      string
+	releases    []*github.RepositoryRelease
+	refs        map[string]string
+	issues      []*github.Issue
+	comments    map[int][]*github.IssueComment
+	milestones  []*github.Milestone
+	categories  []*DiscussionCategory
+	discussions []*Discussion
+	pulls       []*github.PullRequest
+	workflows   map[string]bool
+	deployments []*github.Deployment
+	statuses    map[string][]*github.RepoStatus
+	checkRuns   []*github.CheckRun
+	comparisons map[string][]*github.RepositoryCommit
+
+	dispatches         []Dispatch
+	workflowDispatches []WorkflowDispatch
+}
+
+// storedAsset is a release asset with its content.
+type storedAsset struct {
+	repo      string
+	releaseID int64
+	asset     *github.ReleaseAsset
+	content   []byte
+}
+
+// New starts a fake GitHub server. Call Close when done.
+func New() *Server {
+	s := &Server{
+		nextID:        1,
+		rateRemaining: defaultRateLimit,
+		repos:         make(map[string]*repository),
+		assets:        make(map[int64]*storedAsset),
+		commits:       make(map[string]map[string][]byte),
+
+		deploymentStatuses: make(map[int64][]*github.DeploymentStatus),
+	}
+	s.mux = http.NewServeMux()
+	s.routes()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL of the fake, suitable for the plugin's base_url option.
+func (s *Server) URL() string {
+	return s.srv.URL + "/"
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// InjectFault registers a fault. Faults are matched in registration order.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// CountRequests returns how many requests matched method and contained path.
+func (s *Server) CountRequests(method, path string) int {
+	n := 0
+	for _, r := range s.Requests() {
+		if (method == "" || r.Method == method) && strings.Contains(r.Path, path) {
+			n++
+		}
+	}
+	return n
+}
+
+// serveHTTP records the request, applies faults and dispatches to the mux.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	// Accept the GitHub Enterprise URL layout
+	path := r.URL.Path
+	for _, prefix := range []string{"/api/v3", "/api/uploads", "/api"} {
+		if strings.HasPrefix(path, prefix+"/") {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	r.URL.Path = path
+	r.URL.RawPath = ""
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Query: r.URL.RawQuery})
+	fault := s.matchFault(r)
+	if s.rateRemaining > 0 {
+		s.rateRemaining--
+	}
+	remaining := s.rateRemaining
+	requestID := fmt.Sprintf("FAKE:%d", len(s.requests))
+	s.mu.Unlock()
+
+	w.Header().Set("X-GitHub-Request-Id", requestID)
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(defaultRateLimit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+
+	if fault != nil {
+		if fault.Delay > 0 {
+			// Buffer the body first so client disconnects cancel the context
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			select {
+			case <-time.After(fault.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.RateLimited {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			writeError(w, http.StatusForbidden, "API rate limit exceeded")
+			return
+		}
+		if fault.Status != 0 {
+			writeError(w, fault.Status, http.StatusText(fault.Status))
+			return
+		}
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// matchFault returns the first fault matching r and consumes one use of it.
+// The caller must hold s.mu.
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.Path != "" && !strings.Contains(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// repo returns the repository state, creating it on first use. The caller
+// must hold s.mu.
+func (s *Server) repo(owner, name string) *repository {
+	key := owner + "/" + name
+	r, ok := s.repos[key]
+	if !ok {
+		r = &repository{
+			owner:     owner,
+			name:      name,
+			nodeID:    "R_" + key,
+			refs:      make(map[string]string),
+			comments:  make(map[int][]*github.IssueComment),
+			workflows: make(map[string]bool),
+			statuses:  make(map[string][]*github.RepoStatus),
+
+			comparisons: make(map[string][]*github.RepositoryCommit),
+		}
+		s.repos[key] = r
+	}
+	return r
+}
+
+// id allocates a new object ID. The caller must hold s.mu.
+func (s *Server) id() int64 {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"message": message})
+}
+
+// paginate applies per_page and page query parameters to n items, setting a
+// Link header when more pages follow. It returns the slice bounds.
+func paginate(w http.ResponseWriter, r *http.Request, n int) (int, int) {
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage <= 0 {
+		perPage = 30
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+
+	start := min((page-1)*perPage, n)
+	end := min(start+perPage, n)
+
+	if end < n {
+		next := *r.URL
+		q := next.Query()
+		q.Set("page", strconv.Itoa(page+1))
+		q.Set("per_page", strconv.Itoa(perPage))
+		next.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	}
+	return start, end
+}
@@ -0,0 +1,202 @@
+package ghfake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func newClient(t *testing.T, s *Server) *github.Client {
+	t.Helper()
+	client, err := github.NewClient(nil).WithEnterpriseURLs(s.URL(), s.URL())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+// TestReleaseLifecycle tests creating, listing and deleting releases and assets.
+func TestReleaseLifecycle(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s)
+	ctx := context.Background()
+
+	rel, _, err := client.Repositories.CreateRelease(ctx, "o", "r", &github.RepositoryRelease{TagName: github.String("v1.0.0")})
+	if err != nil {
+		t.Fatalf("CreateRelease: %v", err)
+	}
+	if _, _, err := client.Repositories.CreateRelease(ctx, "o", "r", &github.RepositoryRelease{TagName: github.String("v1.0.0")}); err == nil {
+		t.Error("expected duplicate tag to be rejected")
+	}
+
+	got, _, err := client.Repositories.GetReleaseByTag(ctx, "o", "r", "v1.0.0")
+	if err != nil || got.GetID() != rel.GetID() {
+		t.Fatalf("GetReleaseByTag: %v, %v", got, err)
+	}
+	if _, ok := s.Refs("o", "r")["refs/tags/v1.0.0"]; !ok {
+		t.Error("expected tag ref to be created")
+	}
+
+	asset := s.AddAsset("o", "r", rel.GetID(), "a.txt", []byte("hello"))
+	assets, _, err := client.Repositories.ListReleaseAssets(ctx, "o", "r", rel.GetID(), nil)
+	if err != nil || len(assets) != 1 || assets[0].GetSize() != 5 {
+		t.Fatalf("ListReleaseAssets: %v, %v", assets, err)
+	}
+
+	rc, _, err := client.Repositories.DownloadReleaseAsset(ctx, "o", "r", asset.GetID(), http.DefaultClient)
+	if err != nil {
+		t.Fatalf("DownloadReleaseAsset: %v", err)
+	}
+	_ = rc.Close()
+
+	if _, err := client.Repositories.DeleteRelease(ctx, "o", "r", rel.GetID()); err != nil {
+		t.Fatalf("DeleteRelease: %v", err)
+	}
+	if len(s.Releases("o", "r")) != 0 {
+		t.Error("expected release to be deleted")
+	}
+	if _, ok := s.AssetContent(asset.GetID()); ok {
+		t.Error("expected assets of deleted release to be removed")
+	}
+}
+
+// TestPagination tests that list endpoints paginate with Link headers.
+func TestPagination(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s)
+
+	for i := 0; i < 5; i++ {
+		s.CreateRelease("o", "r", &github.RepositoryRelease{TagName: github.String("v1.0." + string(rune('0'+i)))})
+	}
+
+	releases, resp, err := client.Repositories.ListReleases(context.Background(), "o", "r", &github.ListOptions{PerPage: 2})
+	if err != nil {
+		t.Fatalf("ListReleases: %v", err)
+	}
+	if len(releases) != 2 || resp.NextPage != 2 {
+		t.Errorf("expected 2 releases and next page 2, got %d and %d", len(releases), resp.NextPage)
+	}
+}
+
+// TestFaults tests fault injection.
+func TestFaults(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s)
+	ctx := context.Background()
+
+	s.InjectFault(Fault{Method: "GET", Path: "/releases", Status: http.StatusServiceUnavailable, Times: 1})
+	if _, _, err := client.Repositories.ListReleases(ctx, "o", "r", nil); err == nil {
+		t.Error("expected injected 503")
+	}
+	if _, _, err := client.Repositories.ListReleases(ctx, "o", "r", nil); err != nil {
+		t.Errorf("expected fault to be consumed, got %v", err)
+	}
+
+	s.InjectFault(Fault{RateLimited: true})
+	_, _, err := client.Repositories.ListReleases(ctx, "o", "r", nil)
+	var rateErr *github.RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Errorf("expected rate limit error, got %v", err)
+	}
+
+	if n := s.CountRequests("GET", "/repos/o/r/releases"); n != 3 {
+		t.Errorf("expected 3 recorded requests, got %d", n)
+	}
+}
+
+// TestIssues tests issue and comment endpoints.
+func TestIssues(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s)
+	ctx := context.Background()
+
+	issue, _, err := client.Issues.Create(ctx, "o", "r", &github.IssueRequest{
+		Title:  github.String("Release failed"),
+		Labels: &[]string{"release"},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, _, err := client.Issues.CreateComment(ctx, "o", "r", issue.GetNumber(), &github.IssueComment{Body: github.String("again")}); err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+
+	open, _, err := client.Issues.ListByRepo(ctx, "o", "r", &github.IssueListByRepoOptions{Labels: []string{"release"}})
+	if err != nil || len(open) != 1 {
+		t.Fatalf("ListByRepo: %v, %v", open, err)
+	}
+
+	if _, _, err := client.Issues.Edit(ctx, "o", "r", issue.GetNumber(), &github.IssueRequest{State: github.String("closed")}); err != nil {
+		t.Fatalf("Edit: %v", err)
+	}
+	if s.Issues("o", "r")[0].GetState() != "closed" || len(s.Comments("o", "r", 1)) != 1 {
+		t.Error("unexpected issue state")
+	}
+}
+
+// TestContents tests the Contents API, branches and pull requests.
+func TestContents(t *testing.T) {
+	s := New()
+	defer s.Close()
+	client := newClient(t, s)
+	ctx := context.Background()
+
+	main, _, err := client.Git.GetRef(ctx, "o", "r", "heads/main")
+	if err != nil {
+		t.Fatalf("GetRef: %v", err)
+	}
+	if _, _, err := client.Git.CreateRef(ctx, "o", "r", &github.Reference{
+		Ref:    github.String("refs/heads/update"),
+		Object: &github.GitObject{SHA: main.Object.SHA},
+	}); err != nil {
+		t.Fatalf("CreateRef: %v", err)
+	}
+
+	opts := &github.RepositoryContentFileOptions{
+		Message: github.String("add file"),
+		Content: []byte("v1"),
+		Branch:  github.String("update"),
+	}
+	if _, _, err := client.Repositories.CreateFile(ctx, "o", "r", "a.txt", opts); err != nil {
+		t.Fatalf("CreateFile: %v", err)
+	}
+	file, _, _, err := client.Repositories.GetContents(ctx, "o", "r", "a.txt", &github.RepositoryContentGetOptions{Ref: "update"})
+	if err != nil {
+		t.Fatalf("GetContents: %v", err)
+	}
+	if content, _ := file.GetContent(); content != "v1" {
+		t.Errorf("expected v1, got %q", content)
+	}
+
+	opts.Content = []byte("v2")
+	if _, _, err := client.Repositories.UpdateFile(ctx, "o", "r", "a.txt", opts); err == nil {
+		t.Error("expected update without sha to be rejected")
+	}
+	opts.SHA = file.SHA
+	if _, _, err := client.Repositories.UpdateFile(ctx, "o", "r", "a.txt", opts); err != nil {
+		t.Fatalf("UpdateFile: %v", err)
+	}
+	if _, ok := s.File("o", "r", "main", "a.txt"); ok {
+		t.Error("expected main to be untouched")
+	}
+
+	pr, _, err := client.PullRequests.Create(ctx, "o", "r", &github.NewPullRequest{
+		Title: github.String("Update"),
+		Head:  github.String("update"),
+		Base:  github.String("main"),
+	})
+	if err != nil {
+		t.Fatalf("Create pull request: %v", err)
+	}
+	open, _, err := client.PullRequests.List(ctx, "o", "r", &github.PullRequestListOptions{Head: "o:update"})
+	if err != nil || len(open) != 1 || open[0].GetNumber() != pr.GetNumber() {
+		t.Fatalf("List pull requests: %v, %v", open, err)
+	}
+}
@@ -0,0 +1,165 @@
+package ghfake
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func (s *Server) gitRoutes() {
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/git/ref/{ref...}", s.getRef)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/git/refs", s.createRef)
+	s.mux.HandleFunc("PATCH /repos/{owner}/{repo}/git/refs/{ref...}", s.updateRef)
+	s.mux.HandleFunc("DELETE /repos/{owner}/{repo}/git/refs/{ref...}", s.deleteRef)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/tags", s.listTags)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/compare/{basehead...}", s.compareCommits)
+}
+
+// CreateRef seeds a git reference such as "refs/tags/v1.0.0".
+func (s *Server) CreateRef(owner, repo, ref, sha string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, repo).refs[ref] = sha
+}
+
+// Refs returns the git references of a repository mapped to their SHAs.
+func (s *Server) Refs(owner, repo string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refs := make(map[string]string)
+	for k, v := range s.repo(owner, repo).refs {
+		refs[k] = v
+	}
+	return refs
+}
+
+// SetComparison seeds the commits between two refs, returned by the compare
+// endpoint for "base...head".
+func (s *Server) SetComparison(owner, repo, base, head string, commits []*github.RepositoryCommit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, repo).comparisons[base+"..."+head] = commits
+}
+
+func refResponse(ref, sha string) *github.Reference {
+	return &github.Reference{
+		Ref:    github.String(ref),
+		Object: &github.GitObject{SHA: github.String(sha), Type: github.String("commit")},
+	}
+}
+
+func (s *Server) getRef(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ref := "refs/" + req.PathValue("ref")
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	if ref == "refs/heads/"+defaultBranch {
+		s.branchTree(r, defaultBranch)
+	}
+	sha, ok := r.refs[ref]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, refResponse(ref, sha))
+}
+
+func (s *Server) createRef(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || !strings.HasPrefix(body.Ref, "refs/") || body.SHA == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	if _, ok := r.refs[body.Ref]; ok {
+		writeError(w, http.StatusUnprocessableEntity, "Reference already exists")
+		return
+	}
+	r.refs[body.Ref] = body.SHA
+	writeJSON(w, http.StatusCreated, refResponse(body.Ref, body.SHA))
+}
+
+func (s *Server) updateRef(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.SHA == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ref := "refs/" + req.PathValue("ref")
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	if _, ok := r.refs[ref]; !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Reference does not exist")
+		return
+	}
+	r.refs[ref] = body.SHA
+	writeJSON(w, http.StatusOK, refResponse(ref, body.SHA))
+}
+
+func (s *Server) deleteRef(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ref := "refs/" + req.PathValue("ref")
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	if _, ok := r.refs[ref]; !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Reference does not exist")
+		return
+	}
+	delete(r.refs, ref)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listTags(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tags []*github.RepositoryTag
+	for ref, sha := range s.repo(req.PathValue("owner"), req.PathValue("repo")).refs {
+		if name, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+			tags = append(tags, &github.RepositoryTag{
+				Name:   github.String(name),
+				Commit: &github.Commit{SHA: github.String(sha)},
+			})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].GetName() < tags[j].GetName() })
+
+	start, end := paginate(w, req, len(tags))
+	writeJSON(w, http.StatusOK, tags[start:end])
+}
+
+func (s *Server) compareCommits(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commits, ok := s.repo(req.PathValue("owner"), req.PathValue("repo")).comparisons[req.PathValue("basehead")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	start, end := paginate(w, req, len(commits))
+	writeJSON(w, http.StatusOK, &github.CommitsComparison{
+		Status:       github.String("ahead"),
+		AheadBy:      github.Int(len(commits)),
+		TotalCommits: github.Int(len(commits)),
+		Commits:      commits[start:end],
+	})
+}
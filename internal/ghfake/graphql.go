@@ -0,0 +1,201 @@
+package ghfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DiscussionCategory is a repository discussion category.
+type DiscussionCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// Discussion is a discussion created through the GraphQL API.
+type Discussion struct {
+	ID         string `json:"id"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	Body       string `json:"body"`
+	URL        string `json:"url"`
+	CategoryID string `json:"-"`
+	Locked     bool   `json:"locked"`
+	Pinned     bool   `json:"-"`
+}
+
+// AddDiscussionCategory seeds a discussion category and returns it.
+func (s *Server) AddDiscussionCategory(owner, repo, name string) *DiscussionCategory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(owner, repo)
+	c := &DiscussionCategory{
+		ID:   fmt.Sprintf("DIC_%d", s.id()),
+		Name: name,
+		Slug: strings.ToLower(strings.ReplaceAll(name, " ", "-")),
+	}
+	r.categories = append(r.categories, c)
+	return c
+}
+
+// Discussions returns the discussions of a repository.
+func (s *Server) Discussions(owner, repo string) []Discussion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Discussion
+	for _, d := range s.repo(owner, repo).discussions {
+		out = append(out, *d)
+	}
+	return out
+}
+
+// graphqlRequest is a GraphQL request body.
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// graphql serves the subset of the GraphQL API used for discussions. The
+// operation is chosen by the root field named in the query.
+func (s *Server) graphql(w http.ResponseWriter, req *http.Request) {
+	var body graphqlRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		data any
+		err  error
+	)
+	switch {
+	case strings.Contains(body.Query, "createDiscussion"):
+		data, err = s.gqlCreateDiscussion(inputOf(body.Variables))
+	case strings.Contains(body.Query, "pinDiscussion"):
+		data, err = s.gqlPinDiscussion(inputOf(body.Variables))
+	case strings.Contains(body.Query, "lockLockable"):
+		data, err = s.gqlLockLockable(inputOf(body.Variables))
+	case strings.Contains(body.Query, "discussionCategories"):
+		data, err = s.gqlRepository(body.Variables)
+	default:
+		err = fmt.Errorf("unsupported query")
+	}
+
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"data":   nil,
+			"errors": []map[string]any{{"message": err.Error()}},
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": data})
+}
+
+// inputOf returns the conventional "input" variable of a mutation.
+func inputOf(vars map[string]any) map[string]any {
+	input, _ := vars["input"].(map[string]any)
+	return input
+}
+
+func stringVar(vars map[string]any, key string) string {
+	s, _ := vars[key].(string)
+	return s
+}
+
+// gqlRepository resolves repository(owner, name) { id discussionCategories }.
+func (s *Server) gqlRepository(vars map[string]any) (any, error) {
+	key := stringVar(vars, "owner") + "/" + stringVar(vars, "name")
+	r, ok := s.repos[key]
+	if !ok {
+		return nil, fmt.Errorf("Could not resolve to a Repository with the name '%s'.", key)
+	}
+
+	categories := make([]*DiscussionCategory, len(r.categories))
+	copy(categories, r.categories)
+	return map[string]any{
+		"repository": map[string]any{
+			"id": r.nodeID,
+			"discussionCategories": map[string]any{
+				"nodes": categories,
+			},
+		},
+	}, nil
+}
+
+// gqlCreateDiscussion resolves createDiscussion(input).
+func (s *Server) gqlCreateDiscussion(input map[string]any) (any, error) {
+	repoID := stringVar(input, "repositoryId")
+	categoryID := stringVar(input, "categoryId")
+
+	for _, r := range s.repos {
+		if r.nodeID != repoID {
+			continue
+		}
+
+		found := false
+		for _, c := range r.categories {
+			if c.ID == categoryID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Could not resolve to a node with the global id of '%s'", categoryID)
+		}
+
+		number := len(r.discussions) + 1
+		d := &Discussion{
+			ID:         fmt.Sprintf("D_%d", s.id()),
+			Number:     number,
+			Title:      stringVar(input, "title"),
+			Body:       stringVar(input, "body"),
+			URL:        fmt.Sprintf("%s%s/%s/discussions/%d", s.URL(), r.owner, r.name, number),
+			CategoryID: categoryID,
+		}
+		r.discussions = append(r.discussions, d)
+		return map[string]any{"createDiscussion": map[string]any{"discussion": d}}, nil
+	}
+
+	return nil, fmt.Errorf("Could not resolve to a node with the global id of '%s'", repoID)
+}
+
+// gqlLockLockable resolves lockLockable(input) for discussions.
+func (s *Server) gqlLockLockable(input map[string]any) (any, error) {
+	id := stringVar(input, "lockableId")
+	for _, r := range s.repos {
+		for _, d := range r.discussions {
+			if d.ID == id {
+				d.Locked = true
+				return map[string]any{
+					"lockLockable": map[string]any{
+						"lockedRecord": map[string]any{"locked": true},
+					},
+				}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Could not resolve to a node with the global id of '%s'", id)
+}
+
+// gqlPinDiscussion resolves pinDiscussion(input).
+func (s *Server) gqlPinDiscussion(input map[string]any) (any, error) {
+	id := stringVar(input, "discussionId")
+	for _, r := range s.repos {
+		for _, d := range r.discussions {
+			if d.ID == id {
+				d.Pinned = true
+				return map[string]any{
+					"pinDiscussion": map[string]any{"discussion": map[string]any{"id": d.ID}},
+				}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Could not resolve to a node with the global id of '%s'", id)
+}
@@ -0,0 +1,344 @@
+package ghfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func (s *Server) issueRoutes() {
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/issues", s.listIssues)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/issues", s.createIssue)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}", s.getIssue)
+	s.mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/{number}", s.editIssue)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/comments", s.listComments)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/comments", s.createComment)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/milestones", s.listMilestones)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/milestones", s.createMilestone)
+	s.mux.HandleFunc("PATCH /repos/{owner}/{repo}/milestones/{number}", s.editMilestone)
+}
+
+// CreateIssue seeds an issue and returns it with its number assigned.
+func (s *Server) CreateIssue(owner, repo string, req *github.IssueRequest) *github.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addIssue(s.repo(owner, repo), req)
+}
+
+// Issues returns the issues of a repository in creation order.
+func (s *Server) Issues(owner, repo string) []*github.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*github.Issue(nil), s.repo(owner, repo).issues...)
+}
+
+// Comments returns the comments on an issue.
+func (s *Server) Comments(owner, repo string, number int) []*github.IssueComment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*github.IssueComment(nil), s.repo(owner, repo).comments[number]...)
+}
+
+// CreateMilestone seeds a milestone and returns it with its number assigned.
+func (s *Server) CreateMilestone(owner, repo, title string) *github.Milestone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addMilestone(s.repo(owner, repo), &github.Milestone{Title: github.String(title)})
+}
+
+// Milestones returns the milestones of a repository.
+func (s *Server) Milestones(owner, repo string) []*github.Milestone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*github.Milestone(nil), s.repo(owner, repo).milestones...)
+}
+
+// nextNumber returns the next issue or pull request number. The caller must hold s.mu.
+func (r *repository) nextNumber() int {
+	return len(r.issues) + 1
+}
+
+// addIssue stores an issue. The caller must hold s.mu.
+func (s *Server) addIssue(r *repository, req *github.IssueRequest) *github.Issue {
+	number := r.nextNumber()
+	issue := &github.Issue{
+		ID:        github.Int64(s.id()),
+		Number:    github.Int(number),
+		Title:     req.Title,
+		Body:      req.Body,
+		State:     github.String("open"),
+		HTMLURL:   github.String(fmt.Sprintf("%s%s/%s/issues/%d", s.URL(), r.owner, r.name, number)),
+		CreatedAt: &github.Timestamp{Time: time.Now()},
+	}
+	applyIssueRequest(r, issue, req)
+	r.issues = append(r.issues, issue)
+	return issue
+}
+
+// applyIssueRequest applies the set fields of req to issue.
+func applyIssueRequest(r *repository, issue *github.Issue, req *github.IssueRequest) {
+	if req.Title != nil {
+		issue.Title = req.Title
+	}
+	if req.Body != nil {
+		issue.Body = req.Body
+	}
+	if req.State != nil {
+		issue.State = req.State
+		if req.GetState() == "closed" {
+			issue.ClosedAt = &github.Timestamp{Time: time.Now()}
+			issue.StateReason = req.StateReason
+		} else {
+			issue.ClosedAt = nil
+		}
+	}
+	if req.Labels != nil {
+		issue.Labels = nil
+		for _, name := range *req.Labels {
+			issue.Labels = append(issue.Labels, &github.Label{Name: github.String(name)})
+		}
+	}
+	if req.Assignees != nil {
+		issue.Assignees = nil
+		for _, login := range *req.Assignees {
+			issue.Assignees = append(issue.Assignees, &github.User{Login: github.String(login)})
+		}
+	}
+	if req.Milestone != nil {
+		for _, m := range r.milestones {
+			if m.GetNumber() == req.GetMilestone() {
+				issue.Milestone = m
+			}
+		}
+	}
+}
+
+// findIssue returns the issue with the given number. The caller must hold s.mu.
+func (r *repository) findIssue(number int) *github.Issue {
+	for _, issue := range r.issues {
+		if issue.GetNumber() == number {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := req.URL.Query()
+	state := q.Get("state")
+	if state == "" {
+		state = "open"
+	}
+	var labels []string
+	if l := q.Get("labels"); l != "" {
+		labels = strings.Split(l, ",")
+	}
+
+	var issues []*github.Issue
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	for i := len(r.issues) - 1; i >= 0; i-- {
+		issue := r.issues[i]
+		if state != "all" && issue.GetState() != state {
+			continue
+		}
+		if !hasLabels(issue, labels) {
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	start, end := paginate(w, req, len(issues))
+	writeJSON(w, http.StatusOK, issues[start:end])
+}
+
+// hasLabels reports whether issue carries every label in names.
+func hasLabels(issue *github.Issue, names []string) bool {
+	for _, name := range names {
+		found := false
+		for _, l := range issue.Labels {
+			if l.GetName() == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, req *http.Request) {
+	var body github.IssueRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.GetTitle() == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, s.addIssue(s.repo(req.PathValue("owner"), req.PathValue("repo")), &body))
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	number, _ := strconv.Atoi(req.PathValue("number"))
+	if issue := s.repo(req.PathValue("owner"), req.PathValue("repo")).findIssue(number); issue != nil {
+		writeJSON(w, http.StatusOK, issue)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) editIssue(w http.ResponseWriter, req *http.Request) {
+	var body github.IssueRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	number, _ := strconv.Atoi(req.PathValue("number"))
+	issue := r.findIssue(number)
+	if issue == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	applyIssueRequest(r, issue, &body)
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Server) listComments(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	number, _ := strconv.Atoi(req.PathValue("number"))
+	comments := s.repo(req.PathValue("owner"), req.PathValue("repo")).comments[number]
+	start, end := paginate(w, req, len(comments))
+	writeJSON(w, http.StatusOK, comments[start:end])
+}
+
+func (s *Server) createComment(w http.ResponseWriter, req *http.Request) {
+	var body github.IssueComment
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.GetBody() == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	number, _ := strconv.Atoi(req.PathValue("number"))
+	if r.findIssue(number) == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	id := s.id()
+	comment := &github.IssueComment{
+		ID:        github.Int64(id),
+		Body:      body.Body,
+		HTMLURL:   github.String(fmt.Sprintf("%s%s/%s/issues/%d#issuecomment-%d", s.URL(), r.owner, r.name, number, id)),
+		CreatedAt: &github.Timestamp{Time: time.Now()},
+	}
+	r.comments[number] = append(r.comments[number], comment)
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+// addMilestone stores a milestone. The caller must hold s.mu.
+func (s *Server) addMilestone(r *repository, m *github.Milestone) *github.Milestone {
+	number := len(r.milestones) + 1
+	stored := *m
+	stored.ID = github.Int64(s.id())
+	stored.Number = github.Int(number)
+	if stored.State == nil {
+		stored.State = github.String("open")
+	}
+	stored.HTMLURL = github.String(fmt.Sprintf("%s%s/%s/milestone/%d", s.URL(), r.owner, r.name, number))
+	r.milestones = append(r.milestones, &stored)
+	return &stored
+}
+
+func (s *Server) listMilestones(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := req.URL.Query().Get("state")
+	if state == "" {
+		state = "open"
+	}
+
+	var milestones []*github.Milestone
+	for _, m := range s.repo(req.PathValue("owner"), req.PathValue("repo")).milestones {
+		if state == "all" || m.GetState() == state {
+			milestones = append(milestones, m)
+		}
+	}
+
+	start, end := paginate(w, req, len(milestones))
+	writeJSON(w, http.StatusOK, milestones[start:end])
+}
+
+func (s *Server) createMilestone(w http.ResponseWriter, req *http.Request) {
+	var body github.Milestone
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.GetTitle() == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	for _, m := range r.milestones {
+		if m.GetTitle() == body.GetTitle() {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed: already_exists")
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.addMilestone(r, &body))
+}
+
+func (s *Server) editMilestone(w http.ResponseWriter, req *http.Request) {
+	var body github.Milestone
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	number, _ := strconv.Atoi(req.PathValue("number"))
+	for _, m := range s.repo(req.PathValue("owner"), req.PathValue("repo")).milestones {
+		if m.GetNumber() != number {
+			continue
+		}
+		if body.Title != nil {
+			m.Title = body.Title
+		}
+		if body.Description != nil {
+			m.Description = body.Description
+		}
+		if body.State != nil {
+			m.State = body.State
+		}
+		writeJSON(w, http.StatusOK, m)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
@@ -0,0 +1,386 @@
+package ghfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// routes registers the REST endpoints served by the fake.
+func (s *Server) routes() {
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/releases", s.createRelease)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/releases", s.listReleases)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/releases/{id}", s.getRelease)
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}/releases/{id}/{sub}", s.getReleaseSub)
+	s.mux.HandleFunc("PATCH /repos/{owner}/{repo}/releases/{id}", s.editRelease)
+	s.mux.HandleFunc("DELETE /repos/{owner}/{repo}/releases/{id}", s.deleteRelease)
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/releases/{id}/assets", s.uploadAsset)
+	s.mux.HandleFunc("DELETE /repos/{owner}/{repo}/releases/assets/{id}", s.deleteAsset)
+	s.mux.HandleFunc("GET /download/{id}/{name}", s.downloadAsset)
+
+	s.gitRoutes()
+	s.issueRoutes()
+	s.contentRoutes()
+	s.actionsRoutes()
+	s.deploymentRoutes()
+	s.checkRoutes()
+	s.mux.HandleFunc("POST /graphql", s.graphql)
+}
+
+// CreateRelease seeds a release and returns it with its ID assigned.
+func (s *Server) CreateRelease(owner, repo string, release *github.RepositoryRelease) *github.RepositoryRelease {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addRelease(s.repo(owner, repo), release)
+}
+
+// AddAsset seeds an uploaded asset on a release and returns it.
+func (s *Server) AddAsset(owner, repo string, releaseID int64, name string, content []byte) *github.ReleaseAsset {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addAsset(owner+"/"+repo, releaseID, name, "application/octet-stream", content)
+}
+
+// Releases returns the releases of a repository, newest first.
+func (s *Server) Releases(owner, repo string) []*github.RepositoryRelease {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*github.RepositoryRelease(nil), s.repo(owner, repo).releases...)
+}
+
+// Assets returns the assets of a release in upload order.
+func (s *Server) Assets(releaseID int64) []*github.ReleaseAsset {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.releaseAssets(releaseID)
+}
+
+// AssetContent returns the content of an asset.
+func (s *Server) AssetContent(assetID int64) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.assets[assetID]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), a.content...), true
+}
+
+// addRelease stores a release. The caller must hold s.mu.
+func (s *Server) addRelease(r *repository, release *github.RepositoryRelease) *github.RepositoryRelease {
+	id := s.id()
+	tag := release.GetTagName()
+
+	stored := *release
+	stored.ID = github.Int64(id)
+	stored.NodeID = github.String(fmt.Sprintf("RE_%d", id))
+	stored.HTMLURL = github.String(fmt.Sprintf("%srepos/%s/%s/releases/tag/%s", s.URL(), r.owner, r.name, tag))
+	stored.URL = github.String(fmt.Sprintf("%srepos/%s/%s/releases/%d", s.URL(), r.owner, r.name, id))
+	stored.UploadURL = github.String(fmt.Sprintf("%srepos/%s/%s/releases/%d/assets{?name,label}", s.URL(), r.owner, r.name, id))
+	stored.Draft = github.Bool(release.GetDraft())
+	stored.Prerelease = github.Bool(release.GetPrerelease())
+	stored.GenerateReleaseNotes = nil
+	stored.DiscussionCategoryName = nil
+	if stored.CreatedAt == nil {
+		stored.CreatedAt = &github.Timestamp{Time: time.Now()}
+	}
+
+	// Publishing a release creates its tag
+	if !stored.GetDraft() && tag != "" {
+		if _, ok := r.refs["refs/tags/"+tag]; !ok {
+			r.refs["refs/tags/"+tag] = fakeSHA(id)
+		}
+	}
+
+	r.releases = append([]*github.RepositoryRelease{&stored}, r.releases...)
+	return &stored
+}
+
+// addAsset stores an uploaded asset. The caller must hold s.mu.
+func (s *Server) addAsset(repo string, releaseID int64, name, contentType string, content []byte) *github.ReleaseAsset {
+	id := s.id()
+	asset := &github.ReleaseAsset{
+		ID:                 github.Int64(id),
+		Name:               github.String(name),
+		State:              github.String("uploaded"),
+		ContentType:        github.String(contentType),
+		Size:               github.Int(len(content)),
+		URL:                github.String(fmt.Sprintf("%srepos/%s/releases/assets/%d", s.URL(), repo, id)),
+		BrowserDownloadURL: github.String(fmt.Sprintf("%sdownload/%d/%s", s.URL(), id, name)),
+		CreatedAt:          &github.Timestamp{Time: time.Now()},
+	}
+	s.assets[id] = &storedAsset{repo: repo, releaseID: releaseID, asset: asset, content: content}
+	return asset
+}
+
+// releaseAssets returns the assets of a release. The caller must hold s.mu.
+func (s *Server) releaseAssets(releaseID int64) []*github.ReleaseAsset {
+	var assets []*github.ReleaseAsset
+	for id := int64(1); id < s.nextID; id++ {
+		if a, ok := s.assets[id]; ok && a.releaseID == releaseID {
+			assets = append(assets, a.asset)
+		}
+	}
+	return assets
+}
+
+// findRelease returns the release with the given ID. The caller must hold s.mu.
+func (r *repository) findRelease(id int64) (int, *github.RepositoryRelease) {
+	for i, rel := range r.releases {
+		if rel.GetID() == id {
+			return i, rel
+		}
+	}
+	return -1, nil
+}
+
+func (s *Server) createRelease(w http.ResponseWriter, req *http.Request) {
+	var release github.RepositoryRelease
+	if err := json.NewDecoder(req.Body).Decode(&release); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if release.GetTagName() == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: tag_name is missing")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	for _, existing := range r.releases {
+		if existing.GetTagName() == release.GetTagName() && !existing.GetDraft() {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed: tag_name already_exists")
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, s.addRelease(r, &release))
+}
+
+func (s *Server) listReleases(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	releases := s.repo(req.PathValue("owner"), req.PathValue("repo")).releases
+	start, end := paginate(w, req, len(releases))
+	writeJSON(w, http.StatusOK, releases[start:end])
+}
+
+func (s *Server) getRelease(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	if req.PathValue("id") == "latest" {
+		for _, rel := range r.releases {
+			if !rel.GetDraft() && !rel.GetPrerelease() {
+				writeJSON(w, http.StatusOK, rel)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	id, _ := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if _, rel := r.findRelease(id); rel != nil {
+		writeJSON(w, http.StatusOK, rel)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// getReleaseSub serves releases/tags/{tag}, releases/assets/{id} and
+// releases/{id}/assets, which share a path shape.
+func (s *Server) getReleaseSub(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	id, sub := req.PathValue("id"), req.PathValue("sub")
+
+	switch {
+	case id == "tags":
+		for _, rel := range r.releases {
+			if rel.GetTagName() == sub && !rel.GetDraft() {
+				writeJSON(w, http.StatusOK, rel)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Not Found")
+
+	case id == "assets":
+		assetID, _ := strconv.ParseInt(sub, 10, 64)
+		a, ok := s.assets[assetID]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		if strings.Contains(req.Header.Get("Accept"), "application/octet-stream") {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write(a.content)
+			return
+		}
+		writeJSON(w, http.StatusOK, a.asset)
+
+	case sub == "assets":
+		releaseID, _ := strconv.ParseInt(id, 10, 64)
+		if _, rel := r.findRelease(releaseID); rel == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		assets := s.releaseAssets(releaseID)
+		start, end := paginate(w, req, len(assets))
+		writeJSON(w, http.StatusOK, assets[start:end])
+
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) editRelease(w http.ResponseWriter, req *http.Request) {
+	var edit github.RepositoryRelease
+	if err := json.NewDecoder(req.Body).Decode(&edit); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	id, _ := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	_, rel := r.findRelease(id)
+	if rel == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	if edit.TagName != nil {
+		rel.TagName = edit.TagName
+		rel.HTMLURL = github.String(fmt.Sprintf("%srepos/%s/%s/releases/tag/%s", s.URL(), r.owner, r.name, edit.GetTagName()))
+	}
+	if edit.Name != nil {
+		rel.Name = edit.Name
+	}
+	if edit.Body != nil {
+		rel.Body = edit.Body
+	}
+	if edit.Draft != nil {
+		rel.Draft = edit.Draft
+	}
+	if edit.Prerelease != nil {
+		rel.Prerelease = edit.Prerelease
+	}
+	if edit.MakeLatest != nil {
+		rel.MakeLatest = edit.MakeLatest
+	}
+	if !rel.GetDraft() && rel.GetTagName() != "" {
+		if _, ok := r.refs["refs/tags/"+rel.GetTagName()]; !ok {
+			r.refs["refs/tags/"+rel.GetTagName()] = fakeSHA(id)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, rel)
+}
+
+func (s *Server) deleteRelease(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.repo(req.PathValue("owner"), req.PathValue("repo"))
+	id, _ := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	i, rel := r.findRelease(id)
+	if rel == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	r.releases = append(r.releases[:i], r.releases[i+1:]...)
+	for assetID, a := range s.assets {
+		if a.releaseID == id {
+			delete(s.assets, assetID)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) uploadAsset(w http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: name is missing")
+		return
+	}
+
+	content, err := io.ReadAll(req.Body)
+	if err != nil {
+		return
+	}
+	if req.ContentLength >= 0 && int64(len(content)) != req.ContentLength {
+		writeError(w, http.StatusBadRequest, "Content-Length mismatch")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner, repo := req.PathValue("owner"), req.PathValue("repo")
+	id, _ := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if _, rel := s.repo(owner, repo).findRelease(id); rel == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	for _, a := range s.releaseAssets(id) {
+		if a.GetName() == name {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed: already_exists")
+			return
+		}
+	}
+
+	asset := s.addAsset(owner+"/"+repo, id, name, req.Header.Get("Content-Type"), content)
+	if label := req.URL.Query().Get("label"); label != "" {
+		asset.Label = github.String(label)
+	}
+	writeJSON(w, http.StatusCreated, asset)
+}
+
+func (s *Server) deleteAsset(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, _ := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if _, ok := s.assets[id]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	delete(s.assets, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// downloadAsset serves browser_download_url links.
+func (s *Server) downloadAsset(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, _ := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	a, ok := s.assets[id]
+	if !ok || a.asset.GetName() != req.PathValue("name") {
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Content-Type", a.asset.GetContentType())
+	_, _ = w.Write(a.content)
+}
+
+// fakeSHA returns a deterministic 40-character commit SHA.
+func fakeSHA(n int64) string {
+	return fmt.Sprintf("%040x", n)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// LogLevelOff disables logging entirely.
+const LogLevelOff = "off"
+
+// lowRateLimitThreshold is the remaining request count below which API calls
+// are logged as warnings.
+const lowRateLimitThreshold = 100
+
+// redactedPlaceholder replaces secrets in log output.
+const redactedPlaceholder = "[REDACTED]"
+
+var (
+	// tokenPattern matches GitHub personal access, OAuth, app and refresh tokens.
+	tokenPattern = regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`)
+	// authorizationPattern matches Authorization header values.
+	authorizationPattern = regexp.MustCompile(`(?i)(authorization["']?\s*[:=]\s*["']?)(?:(?:bearer|token|basic)\s+)?[^"',\s]+`)
+)
+
+type loggerKey struct{}
+
+// withLogger returns a context carrying logger.
+func withLogger(ctx context.Context, logger hclog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// loggerFromContext returns the logger carried by ctx, or a logger that
+// discards everything.
+func loggerFromContext(ctx context.Context) hclog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(hclog.Logger); ok {
+		return logger
+	}
+	return hclog.NewNullLogger()
+}
+
+// newLogger creates the structured logger for a plugin run. Output is JSON so
+// the host can re-emit it through its own logger, and every configured token
+// is always redacted.
+func (p *GitHubPlugin) newLogger(cfg *Config) hclog.Logger {
+	if cfg.LogLevel == LogLevelOff {
+		return hclog.NewNullLogger()
+	}
+
+	out := p.logOutput
+	if out == nil {
+		out = os.Stderr
+	}
+
+	return hclog.New(&hclog.LoggerOptions{
+		Name:       "github",
+		Level:      hclog.LevelFromString(cfg.LogLevel),
+		Output:     &redactingWriter{w: out, secrets: configuredTokens(cfg)},
+		JSONFormat: true,
+	})
+}
+
+// configuredTokens returns the plugin token and the tokens of mirrors,
+// package manager repositories and dispatch targets, which may have formats
+// tokenPattern does not match.
+func configuredTokens(cfg *Config) []string {
+	tokens := []string{cfg.Token, cfg.Homebrew.Tap.Token, cfg.Scoop.Bucket.Token, cfg.Winget.Target.Token}
+	for _, m := range cfg.Mirrors {
+		tokens = append(tokens, m.Token)
+	}
+	for _, d := range cfg.Dispatch.RepositoryDispatch {
+		tokens = append(tokens, d.Token)
+	}
+	for _, d := range cfg.Dispatch.WorkflowDispatch {
+		tokens = append(tokens, d.Token)
+	}
+	return tokens
+}
+
+// redactingWriter removes tokens and Authorization header values from log lines.
+type redactingWriter struct {
+	w       io.Writer
+	secrets []string
+}
+
+func (rw *redactingWriter) Write(b []byte) (int, error) {
+	if _, err := rw.w.Write(redact(b, rw.secrets)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// redact replaces known secrets, token-shaped strings and Authorization header
+// values in b.
+func redact(b []byte, secrets []string) []byte {
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		b = bytes.ReplaceAll(b, []byte(secret), []byte(redactedPlaceholder))
+	}
+	b = tokenPattern.ReplaceAllLiteral(b, []byte(redactedPlaceholder))
+	return authorizationPattern.ReplaceAll(b, []byte("${1}"+redactedPlaceholder))
+}
+
+// loggingTransport logs every GitHub API round trip with its request ID and
+// remaining rate limit. Headers are never logged.
+type loggingTransport struct {
+	base   http.RoundTripper
+	logger hclog.Logger
+	now    func() time.Time
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	start := t.now()
+	resp, err := base.RoundTrip(req)
+	duration := t.now().Sub(start)
+
+	if err != nil {
+		t.logger.Debug("github request failed",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"duration_ms", duration.Milliseconds(),
+			"error", err)
+		return resp, err
+	}
+
+	args := []any{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"status", resp.StatusCode,
+		"request_id", resp.Header.Get("X-GitHub-Request-Id"),
+		"duration_ms", duration.Milliseconds(),
+	}
+
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	if remaining != "" {
+		args = append(args, "rate_limit_remaining", remaining)
+	}
+
+	if n, err := strconv.Atoi(remaining); err == nil && n < lowRateLimitThreshold {
+		t.logger.Warn("github rate limit running low", args...)
+	} else {
+		t.logger.Debug("github request", args...)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Mirror statuses reported in the mirrors output.
+const (
+	mirrorStatusCreated = "created"
+	mirrorStatusFailed  = "failed"
+	mirrorStatusPlanned = "dry-run"
+)
+
+// Mirror is a repository that receives a copy of the release.
+type Mirror struct {
+	// Repository is the mirror repository as "owner/name".
+	Repository string `json:"repository"`
+	// Token overrides the plugin token for the mirror.
+	Token string `json:"token,omitempty"`
+	// BaseURL and UploadURL point the mirror at another GitHub instance;
+	// they default to the plugin URLs.
+	BaseURL   string `json:"base_url,omitempty"`
+	UploadURL string `json:"upload_url,omitempty"`
+}
+
+// mirrorResult is the outcome of one mirror, reported in the outputs.
+type mirrorResult struct {
+	Repository  string   `json:"repository"`
+	Status      string   `json:"status"`
+	ReleaseID   int64    `json:"release_id,omitempty"`
+	ReleaseURL  string   `json:"release_url,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	AssetErrors []string `json:"asset_errors,omitempty"`
+}
+
+// releaseFile is a file uploaded to the primary release: a local asset, or
+// generated content such as the provenance or the full notes.
+type releaseFile struct {
+	Name        string
+	ContentType string
+	Path        string
+	Content     []byte
+	Size        int64
+	Checksum    string
+}
+
+// open returns the content of the file.
+func (f releaseFile) open() (io.ReadCloser, error) {
+	if f.Path == "" {
+		return io.NopCloser(bytes.NewReader(f.Content)), nil
+	}
+	return os.Open(f.Path)
+}
+
+// releaseFiles records the files uploaded to the primary release so mirrors
+// upload them from disk instead of downloading the primary's assets. A nil
+// *releaseFiles records nothing.
+type releaseFiles struct {
+	files []releaseFile
+	dirs  []string
+}
+
+// addPath records the local file at path, uploaded as artifact.
+func (r *releaseFiles) addPath(path, contentType string, artifact plugin.Artifact) {
+	if r == nil {
+		return
+	}
+	r.files = append(r.files, releaseFile{Name: artifact.Name, ContentType: contentType, Path: path, Size: artifact.Size, Checksum: artifact.Checksum})
+}
+
+// addContent records generated content uploaded as name.
+func (r *releaseFiles) addContent(name, contentType string, content []byte) {
+	if r == nil {
+		return
+	}
+	sum := sha256.Sum256(content)
+	r.files = append(r.files, releaseFile{Name: name, ContentType: contentType, Content: content, Size: int64(len(content)), Checksum: hex.EncodeToString(sum[:])})
+}
+
+// keep takes over removing the temporary directory dir, whose files must
+// outlive the upload to the primary release. It reports whether it did.
+func (r *releaseFiles) keep(dir string) bool {
+	if r == nil {
+		return false
+	}
+	r.dirs = append(r.dirs, dir)
+	return true
+}
+
+// remove removes the kept temporary directories.
+func (r *releaseFiles) remove() {
+	if r == nil {
+		return
+	}
+	for _, dir := range r.dirs {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+type releaseFilesKey struct{}
+
+// withReleaseFiles returns a context that records uploads in files.
+func withReleaseFiles(ctx context.Context, files *releaseFiles) context.Context {
+	return context.WithValue(ctx, releaseFilesKey{}, files)
+}
+
+// releaseFilesFromContext returns the upload record carried by ctx, or nil.
+func releaseFilesFromContext(ctx context.Context) *releaseFiles {
+	files, _ := ctx.Value(releaseFilesKey{}).(*releaseFiles)
+	return files
+}
+
+// parseMirrors parses the mirrors list.
+func parseMirrors(raw any) []Mirror {
+	var mirrors []Mirror
+	for _, m := range mapSlice(raw) {
+		parser := helpers.NewConfigParser(m)
+		mirrors = append(mirrors, Mirror{
+			Repository: parser.GetString("repository", "", ""),
+			Token:      parser.GetString("token", "", ""),
+			BaseURL:    parser.GetString("base_url", "", ""),
+			UploadURL:  parser.GetString("upload_url", "", ""),
+		})
+	}
+	return mirrors
+}
+
+// validateMirrors validates the mirrors list.
+func validateMirrors(vb *helpers.ValidationBuilder, raw any) {
+	for i, m := range parseMirrors(raw) {
+		field := fmt.Sprintf("mirrors[%d]", i)
+		if _, _, ok := splitRepository(m.Repository); !ok {
+			vb.AddError(field+".repository", "repository must be owner/name")
+		}
+		for _, u := range []struct{ key, value string }{{"base_url", m.BaseURL}, {"upload_url", m.UploadURL}} {
+			if _, err := url.ParseRequestURI(u.value); u.value != "" && err != nil {
+				vb.AddError(field+"."+u.key, u.key+" must be a valid URL")
+			}
+		}
+	}
+}
+
+// mirrorClient returns the client for a mirror, reusing client when the
+// mirror has no token or URLs of its own.
+func (p *GitHubPlugin) mirrorClient(ctx context.Context, cfg *Config, client *github.Client, m Mirror) (*github.Client, error) {
+	if m.Token == "" && m.BaseURL == "" {
+		return client, nil
+	}
+
+	token := m.Token
+	if token == "" {
+		token = cfg.Token
+	}
+	baseURL, uploadURL := cfg.BaseURL, cfg.UploadURL
+	if m.BaseURL != "" {
+		baseURL, uploadURL = m.BaseURL, m.UploadURL
+	}
+	return p.clientFactory(ctx, cfg).newClient(token, baseURL, uploadURL)
+}
+
+// mirrorRelease creates the release in every mirror and uploads the files of
+// the primary release, including generated SBOM, provenance and notes files,
+// into it. Promoted assets have no local files and are copied from the
+// primary release instead. Every mirror is attempted; the outcome of each is
+// reported in the mirrors output.
+func (p *GitHubPlugin) mirrorRelease(ctx context.Context, cfg *Config, client *github.Client, data templateData, _ []plugin.Artifact, resp *plugin.ExecuteResponse, dryRun bool) error {
+	primary := repoClient{client: client, owner: data.Owner, repo: data.Repo}
+	var promoted []*github.ReleaseAsset
+	if releaseID, ok := resp.Outputs["release_id"].(int64); ok && cfg.PromoteFrom != "" && !dryRun {
+		list, err := listReleaseAssets(ctx, client, primary.owner, primary.repo, releaseID)
+		if err != nil {
+			return fmt.Errorf("failed to list assets of release %s: %w", data.Tag, err)
+		}
+		promoted = list
+	}
+	var files []releaseFile
+	if r := releaseFilesFromContext(ctx); r != nil {
+		files = r.files
+	}
+
+	var results []mirrorResult
+	var errs []error
+	for _, m := range cfg.Mirrors {
+		result := p.mirrorTo(ctx, cfg, primary, files, promoted, m, data, dryRun)
+		if result.Status == mirrorStatusFailed {
+			errs = append(errs, fmt.Errorf("%s: %s", m.Repository, result.Error))
+		}
+		results = append(results, result)
+	}
+
+	resp.Outputs["mirrors"] = results
+	return errors.Join(errs...)
+}
+
+// mirrorTo creates the release in one mirror, uploads files and copies the
+// promoted assets into it.
+func (p *GitHubPlugin) mirrorTo(ctx context.Context, cfg *Config, primary repoClient, files []releaseFile, promoted []*github.ReleaseAsset, m Mirror, data templateData, dryRun bool) mirrorResult {
+	logger := loggerFromContext(ctx).With("mirror", m.Repository)
+	ctx = withLogger(ctx, logger)
+	result := mirrorResult{Repository: m.Repository, Status: mirrorStatusFailed}
+
+	owner, repo, ok := splitRepository(m.Repository)
+	if !ok {
+		result.Error = fmt.Sprintf("invalid repository %q", m.Repository)
+		return result
+	}
+	client, err := p.mirrorClient(ctx, cfg, primary.client, m)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	// Mirrors get the same release without the primary's discussion. They
+	// generate no SBOM or provenance and keep no resume state: those files
+	// come from the primary. Links point at the mirror's instance, and
+	// truncated notes link to the mirror's copy of the full notes.
+	mirrorCfg := *cfg
+	mirrorCfg.DiscussionCategory = ""
+	mirrorCfg.SBOM.Enabled = false
+	mirrorCfg.Provenance.Enabled = false
+	mirrorCfg.Resume.Enabled = false
+	if m.BaseURL != "" {
+		mirrorCfg.BaseURL, mirrorCfg.UploadURL = m.BaseURL, m.UploadURL
+	}
+	release := newRelease(&mirrorCfg, data)
+	previous := previousTag(plugin.ReleaseContext{TagName: data.Tag, Version: data.Version, PreviousVersion: data.PreviousVersion})
+	limitReleaseBody(&mirrorCfg, owner, repo, previous, release)
+
+	if dryRun {
+		logger.Info("dry run, skipping mirror release", "tag", data.Tag)
+		result.Status = mirrorStatusPlanned
+		return result
+	}
+
+	created, _, err := client.Repositories.CreateRelease(ctx, owner, repo, release)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create release: %v", err)
+		logger.Error("mirror failed", "error", result.Error)
+		return result
+	}
+	result.ReleaseID = created.GetID()
+	result.ReleaseURL = created.GetHTMLURL()
+
+	var artifacts []plugin.Artifact
+	for _, file := range files {
+		artifact, err := uploadReleaseFile(ctx, client, owner, repo, created.GetID(), file)
+		if err != nil {
+			logger.Error("asset upload failed", "name", file.Name, "error", err)
+			result.AssetErrors = append(result.AssetErrors, err.Error())
+			continue
+		}
+		artifacts = append(artifacts, *artifact)
+	}
+	mirror := repoClient{client: client, owner: owner, repo: repo}
+	for _, asset := range promoted {
+		if _, err := copyReleaseAsset(ctx, primary, mirror, asset, created.GetID()); err != nil {
+			logger.Error("asset copy failed", "name", asset.GetName(), "error", err)
+			result.AssetErrors = append(result.AssetErrors, err.Error())
+		}
+	}
+	if cfg.VerifyAssets {
+		for _, err := range p.verifyAssets(ctx, client, owner, repo, created.GetID(), artifacts, cfg.VerifyChecksums) {
+			result.AssetErrors = append(result.AssetErrors, err.Error())
+		}
+	}
+
+	if len(result.AssetErrors) > 0 && cfg.AssetFailurePolicy == AssetFailureFail {
+		result.Error = fmt.Sprintf("release %s published but %d asset(s) failed: %s", result.ReleaseURL, len(result.AssetErrors), strings.Join(result.AssetErrors, "; "))
+		logger.Error("mirror failed", "error", result.Error)
+		return result
+	}
+	result.Status = mirrorStatusCreated
+	logger.Info("mirrored release", "release_url", result.ReleaseURL)
+	return result
+}
+
+// uploadReleaseFile uploads file to the release with releaseID and checks
+// that the uploaded content has the checksum recorded for the primary.
+func uploadReleaseFile(ctx context.Context, client *github.Client, owner, repo string, releaseID int64, file releaseFile) (*plugin.Artifact, error) {
+	rc, err := file.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open asset %s: %w", file.Name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	h := sha256.New()
+	asset, err := uploadReleaseAssetFromReader(ctx, client, owner, repo, releaseID, file.Name, file.ContentType, io.TeeReader(rc, h), file.Size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload asset %s: %w", file.Name, err)
+	}
+	if digest := hex.EncodeToString(h.Sum(nil)); digest != file.Checksum {
+		return nil, fmt.Errorf("asset %s changed since the primary upload: primary %s, mirror %s", file.Name, file.Checksum, digest)
+	}
+
+	return &plugin.Artifact{
+		Name:     file.Name,
+		Path:     asset.GetBrowserDownloadURL(),
+		Type:     "url",
+		Size:     file.Size,
+		Checksum: file.Checksum,
+	}, nil
+}
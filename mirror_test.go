@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+
+	"github.com/relicta-tech/plugin-github/internal/ghfake"
+)
+
+// releaseByTag returns the release of a fake repository with the given tag.
+func releaseByTag(t *testing.T, fake *ghfake.Server, owner, repo, tag string) *github.RepositoryRelease {
+	t.Helper()
+	for _, r := range fake.Releases(owner, repo) {
+		if r.GetTagName() == tag {
+			return r
+		}
+	}
+	t.Fatalf("no release %s in %s/%s", tag, owner, repo)
+	return nil
+}
+
+// TestExecuteMirrors tests mirroring the release and its assets to repositories on two instances.
+func TestExecuteMirrors(t *testing.T) {
+	fake := newFakeGitHub(t)
+	internal := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"app.tar.gz": "binary"})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets": []any{dir + "/app.tar.gz"},
+			"mirrors": []any{
+				map[string]any{"repository": "public/app"},
+				map[string]any{"repository": "corp/app-fork", "token": "ghp_internal", "base_url": internal.URL()},
+			},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: "notes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	results, _ := resp.Outputs["mirrors"].([]mirrorResult)
+	if len(results) != 2 || results[0].Status != mirrorStatusCreated || results[1].Status != mirrorStatusCreated {
+		t.Fatalf("unexpected mirror results %+v", results)
+	}
+
+	for i, m := range []struct {
+		fake        *ghfake.Server
+		owner, repo string
+	}{{fake, "public", "app"}, {internal, "corp", "app-fork"}} {
+		release := releaseByTag(t, m.fake, m.owner, m.repo, "v1.2.0")
+		if release.GetName() != "Release 1.2.0" || release.GetBody() != "notes" || results[i].ReleaseURL != release.GetHTMLURL() {
+			t.Errorf("unexpected mirrored release %v", release)
+		}
+		assets := m.fake.Assets(release.GetID())
+		if len(assets) != 1 || assets[0].GetName() != "app.tar.gz" {
+			t.Fatalf("unexpected mirrored assets %v", assets)
+		}
+		if content, _ := m.fake.AssetContent(assets[0].GetID()); string(content) != "binary" {
+			t.Errorf("unexpected mirrored content %q", content)
+		}
+	}
+	if len(resp.Artifacts) != 1 {
+		t.Errorf("expected only the primary artifacts, got %d", len(resp.Artifacts))
+	}
+}
+
+// TestExecuteMirrorsCopyPrimaryAssets tests that mirrors get the primary's
+// local files, generated SBOM and full notes without downloading them.
+func TestExecuteMirrorsCopyPrimaryAssets(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"app.tar.gz": "binary"})
+	notes := strings.Repeat("- fix: something went wrong\n", 200)
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets":  []any{dir + "/app.tar.gz"},
+			"sbom":    map[string]any{"enabled": true, "go_mod": writeGoModule(t)},
+			"notes":   map[string]any{"max_length": 1000, "upload_full_notes": true},
+			"mirrors": []any{map[string]any{"repository": "public/app"}},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0", ReleaseNotes: notes},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	primary := fake.Assets(releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0").GetID())
+	release := releaseByTag(t, fake, "public", "app", "v1.2.0")
+	mirrored := fake.Assets(release.GetID())
+	if len(primary) != 3 || len(mirrored) != len(primary) {
+		t.Fatalf("expected the 3 primary assets in the mirror, got %v and %v", primary, mirrored)
+	}
+	for i, asset := range primary {
+		want, _ := fake.AssetContent(asset.GetID())
+		got, _ := fake.AssetContent(mirrored[i].GetID())
+		if mirrored[i].GetName() != asset.GetName() || string(got) != string(want) {
+			t.Errorf("mirrored asset %s differs from the primary", asset.GetName())
+		}
+	}
+	if !strings.Contains(release.GetBody(), "/public/app/releases/download/v1.2.0/RELEASE_NOTES.md)") {
+		t.Errorf("expected the mirror body to link to its full notes, got %q", release.GetBody())
+	}
+	if n := fake.CountRequests("POST", "/repos/public/app/releases/"); n != 3 {
+		t.Errorf("expected 3 asset uploads to the mirror, got %d", n)
+	}
+	if n := fake.CountRequests("GET", "/releases/assets/"); n != 0 {
+		t.Errorf("expected no asset downloads, got %d", n)
+	}
+}
+
+// TestExecuteMirrorsPromotedAssets tests that mirrors of a promoted release
+// get the promoted assets.
+func TestExecuteMirrorsPromotedAssets(t *testing.T) {
+	fake := newFakeGitHub(t)
+	source := fake.CreateRelease("test-owner", "test-repo", &github.RepositoryRelease{TagName: github.String("v1.2.0-rc.1"), Prerelease: github.Bool(true)})
+	fake.AddAsset("test-owner", "test-repo", source.GetID(), "app.tar.gz", []byte("rc artifact"))
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"promote_from": "v1.2.0-rc.1",
+			"mirrors":      []any{map[string]any{"repository": "public/app"}},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	assets := fake.Assets(releaseByTag(t, fake, "public", "app", "v1.2.0").GetID())
+	if len(assets) != 1 {
+		t.Fatalf("unexpected mirrored assets %v", assets)
+	}
+	if content, _ := fake.AssetContent(assets[0].GetID()); string(content) != "rc artifact" {
+		t.Errorf("expected the promoted asset in the mirror, got %q", content)
+	}
+}
+
+// TestExecuteMirrorFailure tests that a failed mirror keeps the primary release and the other mirrors.
+func TestExecuteMirrorFailure(t *testing.T) {
+	fake := newFakeGitHub(t)
+	fake.CreateRelease("public", "app", &github.RepositoryRelease{TagName: github.String("v1.2.0")})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"mirrors": []any{
+				map[string]any{"repository": "public/app"},
+				map[string]any{"repository": "corp/app"},
+			},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "published but mirror failed: public/app:") {
+		t.Errorf("expected mirror failure, got %+v", resp)
+	}
+
+	results, _ := resp.Outputs["mirrors"].([]mirrorResult)
+	if len(results) != 2 || results[0].Status != mirrorStatusFailed || results[1].Status != mirrorStatusCreated {
+		t.Errorf("unexpected mirror results %+v", results)
+	}
+	if resp.Outputs["release_id"] == nil {
+		t.Error("expected release outputs to be kept")
+	}
+}
+
+// TestValidateMirrors tests validation of the mirrors list.
+func TestValidateMirrors(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":   "ghp_test",
+		"mirrors": []any{map[string]any{"repository": "no-owner", "base_url": "not a url"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 2 {
+		t.Errorf("expected repository and base_url errors, got %+v", resp.Errors)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// NotesConfig configures post-processing of the release notes.
+type NotesConfig struct {
+	// Linkify links issue references, commit SHAs and mentions.
+	Linkify bool `json:"linkify"`
+	// FullChangelog appends a compare link to the previous release.
+	FullChangelog bool `json:"full_changelog"`
+	// Contributors appends the avatars of the commit authors since the
+	// previous release.
+	Contributors bool `json:"contributors"`
+	// MaxLength is the longest release body in characters; longer bodies
+	// are truncated. It defaults to and is capped at GitHub's limit.
+	MaxLength int `json:"max_length"`
+	// UploadFullNotes uploads truncated notes in full as RELEASE_NOTES.md.
+	UploadFullNotes bool `json:"upload_full_notes"`
+}
+
+// parseNotesConfig parses the notes section of the configuration.
+func parseNotesConfig(raw map[string]any) NotesConfig {
+	parser := helpers.NewConfigParser(raw)
+	return NotesConfig{
+		Linkify:         parser.GetBool("linkify", false),
+		FullChangelog:   parser.GetBool("full_changelog", false),
+		Contributors:    parser.GetBool("contributors", false),
+		MaxLength:       parser.GetInt("max_length", maxReleaseBodyLength),
+		UploadFullNotes: parser.GetBool("upload_full_notes", false),
+	}
+}
+
+// validateNotesConfig validates the notes section of the configuration.
+func validateNotesConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	cfg := parseNotesConfig(raw)
+	if cfg.MaxLength < minReleaseBodyLength || cfg.MaxLength > maxReleaseBodyLength {
+		vb.AddError("notes.max_length", fmt.Sprintf("max_length must be between %d and %d", minReleaseBodyLength, maxReleaseBodyLength))
+	}
+}
+
+// notesProtected matches Markdown the linkifier leaves alone: inline code,
+// links, autolinks and bare URLs.
+var notesProtected = regexp.MustCompile("`[^`]*`|!?\\[[^\\]]*\\]\\([^)]*\\)|<[^>\\s]+>|https?://[^\\s)>]+")
+
+// notesReference matches a linkable reference with the character before it.
+// Submatches are: 1 leading character, 2-3 cross-repo issue, 4-5 cross-repo
+// commit, 6 GH- issue, 7 issue, 8 mention and 9 commit SHA.
+var notesReference = regexp.MustCompile(`(^|[^\w@/#.-])(?:([\w.-]+/[\w.-]+)#(\d+)|([\w.-]+/[\w.-]+)@([0-9a-f]{7,40})|GH-(\d+)|#(\d+)|@([A-Za-z0-9][A-Za-z0-9-]{0,38})|([0-9a-f]{7,40}))\b`)
+
+// linkifyNotes turns references in Markdown notes into links to web, the
+// GitHub web URL, for the repository owner/repo. Fenced code blocks, inline
+// code and existing links are left unchanged.
+func linkifyNotes(notes, web, owner, repo string) string {
+	lines := strings.Split(notes, "\n")
+	fenced := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+
+		var b strings.Builder
+		last := 0
+		for _, loc := range notesProtected.FindAllStringIndex(line, -1) {
+			b.WriteString(linkifyText(line[last:loc[0]], web, owner, repo))
+			b.WriteString(line[loc[0]:loc[1]])
+			last = loc[1]
+		}
+		b.WriteString(linkifyText(line[last:], web, owner, repo))
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkifyText links the references in text that contains no Markdown links.
+func linkifyText(text, web, owner, repo string) string {
+	return notesReference.ReplaceAllStringFunc(text, func(match string) string {
+		m := notesReference.FindStringSubmatch(match)
+		lead, ref := m[1], match[len(m[1]):]
+
+		var target string
+		switch {
+		case m[2] != "":
+			target = fmt.Sprintf("%s/%s/issues/%s", web, m[2], m[3])
+		case m[4] != "":
+			target = fmt.Sprintf("%s/%s/commit/%s", web, m[4], m[5])
+		case m[6] != "":
+			target = fmt.Sprintf("%s/%s/%s/issues/%s", web, owner, repo, m[6])
+		case m[7] != "":
+			target = fmt.Sprintf("%s/%s/%s/issues/%s", web, owner, repo, m[7])
+		case m[8] != "":
+			target = fmt.Sprintf("%s/%s", web, m[8])
+		case isCommitSHA(m[9]):
+			target = fmt.Sprintf("%s/%s/%s/commit/%s", web, owner, repo, m[9])
+			ref = "`" + m[9][:min(len(m[9]), 7)] + "`"
+		default:
+			return match
+		}
+		return fmt.Sprintf("%s[%s](%s)", lead, ref, target)
+	})
+}
+
+// isCommitSHA reports whether s looks like an abbreviated or full commit
+// SHA rather than a number or a word made of hex letters.
+func isCommitSHA(s string) bool {
+	return strings.ContainsAny(s, "0123456789") && strings.ContainsAny(s, "abcdef")
+}
+
+// previousTag returns the tag of the previous release, assuming it uses the
+// same prefix as the current tag, or "" when unknown.
+func previousTag(releaseCtx plugin.ReleaseContext) string {
+	if releaseCtx.PreviousVersion == "" || !strings.HasSuffix(releaseCtx.TagName, releaseCtx.Version) {
+		return ""
+	}
+	return strings.TrimSuffix(releaseCtx.TagName, releaseCtx.Version) + releaseCtx.PreviousVersion
+}
+
+// contributor is a commit author shown in the release notes.
+type contributor struct {
+	Login     string
+	AvatarURL string
+}
+
+// listContributors returns the GitHub users who authored commits between
+// base and head in first-commit order, skipping bots.
+func listContributors(ctx context.Context, client *github.Client, owner, repo, base, head string) ([]contributor, error) {
+	var contributors []contributor
+	seen := make(map[string]bool)
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		comparison, resp, err := client.Repositories.CompareCommits(ctx, owner, repo, base, head, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range comparison.Commits {
+			author := c.GetAuthor()
+			login := author.GetLogin()
+			if login == "" || seen[login] || author.GetType() == "Bot" || strings.HasSuffix(login, "[bot]") {
+				continue
+			}
+			seen[login] = true
+			contributors = append(contributors, contributor{Login: login, AvatarURL: author.GetAvatarURL()})
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return contributors, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// renderContributors renders a contributors section with linked avatars.
+func renderContributors(web string, contributors []contributor) string {
+	var b strings.Builder
+	b.WriteString("## Contributors\n\n")
+	for _, c := range contributors {
+		fmt.Fprintf(&b, `<a href="%s/%s"><img src="%s" width="40" height="40" alt="@%s"></a>`+"\n", web, c.Login, c.AvatarURL, c.Login)
+	}
+	return b.String()
+}
+
+// processNotes returns the release notes (or changelog) with the configured
+// post-processing applied. Contributors are best effort: a failed lookup is
+// logged and the section left out.
+func (p *GitHubPlugin) processNotes(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext) string {
+	logger := loggerFromContext(ctx)
+	notes := releaseCtx.ReleaseNotes
+	if notes == "" {
+		notes = releaseCtx.Changelog
+	}
+
+	owner, repo := resolveRepository(cfg, releaseCtx)
+	web := webURL(cfg)
+	if cfg.Notes.Linkify {
+		notes = linkifyNotes(notes, web, owner, repo)
+	}
+
+	var sections []string
+	prev := previousTag(releaseCtx)
+	if cfg.Notes.Contributors {
+		// The tag may not exist before the release, so compare up to the commit
+		head := releaseCtx.CommitSHA
+		if head == "" {
+			head = releaseCtx.TagName
+		}
+		contributors, err := p.notesContributors(ctx, cfg, owner, repo, prev, head)
+		switch {
+		case err != nil:
+			logger.Warn("failed to list contributors", "error", err)
+		case len(contributors) > 0:
+			sections = append(sections, renderContributors(web, contributors))
+		}
+	}
+	// Generated notes already end with the compare link
+	if cfg.Notes.FullChangelog && prev != "" && !cfg.GenerateReleaseNotes {
+		sections = append(sections, fmt.Sprintf("**Full Changelog**: %s/%s/%s/compare/%s...%s", web, owner, repo, prev, releaseCtx.TagName))
+	}
+
+	for _, s := range sections {
+		notes = strings.TrimRight(notes, "\n") + "\n\n" + strings.TrimRight(s, "\n")
+	}
+	return strings.TrimLeft(notes, "\n")
+}
+
+// notesContributors lists the contributors since the previous tag.
+func (p *GitHubPlugin) notesContributors(ctx context.Context, cfg *Config, owner, repo, base, head string) ([]contributor, error) {
+	if base == "" {
+		return nil, fmt.Errorf("previous version is unknown")
+	}
+	client, err := p.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return listContributors(ctx, client, owner, repo, base, head)
+}
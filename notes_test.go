@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// TestLinkifyNotes tests linking references while leaving code and links alone.
+func TestLinkifyNotes(t *testing.T) {
+	const web = "https://github.com"
+	tests := []struct {
+		name  string
+		notes string
+		want  string
+	}{
+		{"issue", "Fix crash (#12)", "Fix crash ([#12](https://github.com/o/r/issues/12))"},
+		{"GH issue", "GH-7 fixed", "[GH-7](https://github.com/o/r/issues/7) fixed"},
+		{"cross-repo issue", "See acme/lib#3.", "See [acme/lib#3](https://github.com/acme/lib/issues/3)."},
+		{"cross-repo commit", "acme/lib@abc1234", "[acme/lib@abc1234](https://github.com/acme/lib/commit/abc1234)"},
+		{"mention", "Thanks @octo-cat!", "Thanks [@octo-cat](https://github.com/octo-cat)!"},
+		{"commit", "- feat: add (1a2b3c4d5e6f)", "- feat: add ([`1a2b3c4`](https://github.com/o/r/commit/1a2b3c4d5e6f))"},
+		{"hex word", "added a facade and 1234567", "added a facade and 1234567"},
+		{"email", "mail dev@example.com", "mail dev@example.com"},
+		{"inline code", "run `make #1` now", "run `make #1` now"},
+		{"existing link", "[#5](https://example.com/5) and https://example.com/#6", "[#5](https://example.com/5) and https://example.com/#6"},
+		{"fenced code", "```\n#1 @me\n```\n#2", "```\n#1 @me\n```\n[#2](https://github.com/o/r/issues/2)"},
+		{"heading", "## Fixes", "## Fixes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linkifyNotes(tt.notes, web, "o", "r"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExecuteProcessNotes tests that the release body gets links, contributors and the compare link.
+func TestExecuteProcessNotes(t *testing.T) {
+	fake := newFakeGitHub(t)
+	user := func(login, typ string) *github.User {
+		return &github.User{Login: github.String(login), AvatarURL: github.String("https://avatars.example.com/" + login), Type: github.String(typ)}
+	}
+	fake.SetComparison("test-owner", "test-repo", "v1.1.0", "abc1234", []*github.RepositoryCommit{
+		{SHA: github.String("c1"), Author: user("alice", "User")},
+		{SHA: github.String("c2"), Author: user("dependabot[bot]", "Bot")},
+		{SHA: github.String("c3"), Author: user("bob", "User")},
+		{SHA: github.String("c4"), Author: user("alice", "User")},
+		{SHA: github.String("c5")},
+	})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"notes": map[string]any{"linkify": true, "full_changelog": true, "contributors": true},
+		}),
+		Context: plugin.ReleaseContext{
+			Version:         "1.2.0",
+			PreviousVersion: "1.1.0",
+			TagName:         "v1.2.0",
+			CommitSHA:       "abc1234",
+			ReleaseNotes:    "- fix: crash (#12)\n",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	web := strings.TrimSuffix(fake.URL(), "/")
+	body := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0").GetBody()
+	want := "- fix: crash ([#12](" + web + "/test-owner/test-repo/issues/12))\n\n" +
+		"## Contributors\n\n" +
+		`<a href="` + web + `/alice"><img src="https://avatars.example.com/alice" width="40" height="40" alt="@alice"></a>` + "\n" +
+		`<a href="` + web + `/bob"><img src="https://avatars.example.com/bob" width="40" height="40" alt="@bob"></a>` + "\n\n" +
+		"**Full Changelog**: " + web + "/test-owner/test-repo/compare/v1.1.0...v1.2.0"
+	if body != want {
+		t.Errorf("unexpected body:\n%s\nwant:\n%s", body, want)
+	}
+}
+
+// TestExecuteProcessNotesContributorsFailure tests that a failed contributor lookup keeps the release.
+func TestExecuteProcessNotesContributorsFailure(t *testing.T) {
+	fake := newFakeGitHub(t)
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"generate_release_notes": true,
+			"notes":                  map[string]any{"full_changelog": true, "contributors": true},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", PreviousVersion: "1.1.0", TagName: "v1.2.0", ReleaseNotes: "notes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if body := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0").GetBody(); body != "notes" {
+		t.Errorf("expected unchanged notes, got %q", body)
+	}
+}
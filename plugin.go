@@ -3,19 +3,39 @@ package main
 
 import (
 	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/go-github/v60/github"
-	"golang.org/x/oauth2"
 
 	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
 	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
 )
 
 // GitHubPlugin implements the GitHub release plugin.
-type GitHubPlugin struct{}
+type GitHubPlugin struct {
+	// logOutput receives log lines; defaults to stderr, which the host collects.
+	logOutput io.Writer
+	// transport is the base HTTP transport for API calls; defaults to
+	// http.DefaultTransport.
+	transport http.RoundTripper
+	// clock provides the current time and retry sleeps; defaults to the
+	// system clock.
+	clock clock
+}
+
+// pluginVersion is the plugin version, also sent in the User-Agent header.
+const pluginVersion = "2.0.0"
 
 // Config represents the GitHub plugin configuration.
 type Config struct {
@@ -25,6 +45,10 @@ type Config struct {
 	Repo string `json:"repo,omitempty"`
 	// Token is the GitHub token.
 	Token string `json:"token,omitempty"`
+	// BaseURL is the API base URL for GitHub Enterprise Server.
+	BaseURL string `json:"base_url,omitempty"`
+	// UploadURL is the upload URL for GitHub Enterprise Server (defaults to BaseURL).
+	UploadURL string `json:"upload_url,omitempty"`
 	// Draft creates the release as a draft.
 	Draft bool `json:"draft"`
 	// Prerelease marks the release as a prerelease.
@@ -33,15 +57,92 @@ type Config struct {
 	GenerateReleaseNotes bool `json:"generate_release_notes"`
 	// Assets is a list of files to upload as release assets.
 	Assets []string `json:"assets,omitempty"`
+	// ContentTypes maps asset names or glob patterns to the content type
+	// they are uploaded with, overriding detection.
+	ContentTypes map[string]string `json:"content_types,omitempty"`
 	// DiscussionCategory creates a discussion for the release.
 	DiscussionCategory string `json:"discussion_category,omitempty"`
+	// AssetFailurePolicy controls how asset upload and verification failures
+	// are handled: "continue" (default) reports them, "fail" fails the release.
+	AssetFailurePolicy string `json:"asset_failure_policy,omitempty"`
+	// VerifyAssets re-lists the release after uploading and checks every asset.
+	VerifyAssets bool `json:"verify_assets"`
+	// VerifyChecksums downloads each asset back and compares its SHA-256.
+	VerifyChecksums bool `json:"verify_checksums"`
+	// PromoteFrom is the tag of an existing release whose assets are promoted
+	// to this release instead of uploading local files.
+	PromoteFrom string `json:"promote_from,omitempty"`
+	// PromoteMode is "copy" (default) to create a new release with copied
+	// assets, or "retag" to move the source release to the new tag.
+	PromoteMode string `json:"promote_mode,omitempty"`
+	// SBOM configures an SBOM uploaded with the assets.
+	SBOM SBOMConfig `json:"sbom"`
+	// Provenance configures an in-toto provenance statement of the assets.
+	Provenance ProvenanceConfig `json:"provenance"`
+	// Releases lists component releases created instead of the single
+	// release, e.g. for monorepos.
+	Releases []ComponentRelease `json:"releases,omitempty"`
+	// Mirrors are repositories that receive a copy of the release.
+	Mirrors []Mirror `json:"mirrors,omitempty"`
+	// BodyFile is a path template of a file with the release notes.
+	BodyFile string `json:"body_file,omitempty"`
+	// ChangelogFile is a path template of a Keep-a-Changelog file whose
+	// section for the version becomes the release notes.
+	ChangelogFile string `json:"changelog_file,omitempty"`
+	// BodyPrecedence orders the sources of the release body; the first
+	// that yields text is used.
+	BodyPrecedence []string `json:"body_precedence,omitempty"`
+	// Notes configures post-processing of the release notes.
+	Notes NotesConfig `json:"notes"`
+	// AuditLog configures the audit log of mutating API calls.
+	AuditLog AuditLogConfig `json:"audit_log"`
+	// Resume configures resuming interrupted releases.
+	Resume ResumeConfig `json:"resume"`
+	// Announcement configures a release announcement in GitHub Discussions.
+	Announcement AnnouncementConfig `json:"announcement"`
+	// Homebrew configures updating a formula in a Homebrew tap.
+	Homebrew HomebrewConfig `json:"homebrew"`
+	// Scoop configures publishing a Scoop manifest to a bucket.
+	Scoop ScoopConfig `json:"scoop"`
+	// Winget configures publishing winget manifests.
+	Winget WingetConfig `json:"winget"`
+	// Dispatch configures downstream workflows triggered after the release.
+	Dispatch DispatchConfig `json:"dispatch"`
+	// Deployment configures a GitHub deployment of the release tag.
+	Deployment DeploymentConfig `json:"deployment"`
+	// CommitStatus configures reporting the release on the tagged commit.
+	CommitStatus CommitStatusConfig `json:"commit_status"`
+	// FailureIssue configures an issue that tracks failed releases.
+	FailureIssue FailureIssueConfig `json:"failure_issue"`
+	// Cleanup configures removal of old releases after a successful release.
+	Cleanup CleanupConfig `json:"cleanup"`
+	// ActionsOutputs writes step outputs and a step summary when running in GitHub Actions.
+	ActionsOutputs bool `json:"actions_outputs"`
+	// LogLevel is the plugin log level (trace, debug, info, warn, error or off).
+	LogLevel string `json:"log_level,omitempty"`
+	// APIVersion is sent as the X-GitHub-Api-Version header.
+	APIVersion string `json:"api_version,omitempty"`
+	// RequestTimeout bounds each API request attempt (0 disables).
+	RequestTimeout time.Duration `json:"request_timeout"`
+	// UploadTimeout bounds each asset upload (0 disables).
+	UploadTimeout time.Duration `json:"upload_timeout"`
+	// ProgressInterval is how often upload progress is logged (0 disables).
+	ProgressInterval time.Duration `json:"progress_interval"`
+	// MaxRetries is how often transient API failures are retried.
+	MaxRetries int `json:"max_retries"`
 }
 
+// Asset failure policies.
+const (
+	AssetFailureContinue = "continue"
+	AssetFailureFail     = "fail"
+)
+
 // GetInfo returns plugin metadata.
 func (p *GitHubPlugin) GetInfo() plugin.Info {
 	return plugin.Info{
 		Name:        "github",
-		Version:     "2.0.0",
+		Version:     pluginVersion,
 		Description: "Create GitHub releases and upload assets",
 		Author:      "Relicta Team",
 		Hooks: []plugin.Hook{
@@ -55,11 +156,293 @@ func (p *GitHubPlugin) GetInfo() plugin.Info {
 				"owner": {"type": "string", "description": "Repository owner"},
 				"repo": {"type": "string", "description": "Repository name"},
 				"token": {"type": "string", "description": "GitHub token (or use GITHUB_TOKEN env)"},
+				"base_url": {"type": "string", "description": "API base URL for GitHub Enterprise Server"},
+				"upload_url": {"type": "string", "description": "Upload URL for GitHub Enterprise Server (defaults to base_url)"},
 				"draft": {"type": "boolean", "description": "Create as draft", "default": false},
 				"prerelease": {"type": "boolean", "description": "Mark as prerelease", "default": false},
 				"generate_release_notes": {"type": "boolean", "description": "Use GitHub's auto-generated notes", "default": false},
 				"assets": {"type": "array", "items": {"type": "string"}, "description": "Files to upload"},
-				"discussion_category": {"type": "string", "description": "Discussion category name"}
+				"content_types": {"type": "object", "additionalProperties": {"type": "string"}, "description": "Content types by asset name or glob pattern, overriding detection"},
+				"discussion_category": {"type": "string", "description": "Discussion category name"},
+				"asset_failure_policy": {"type": "string", "enum": ["continue", "fail"], "description": "How to handle asset upload and verification failures", "default": "continue"},
+				"verify_assets": {"type": "boolean", "description": "Verify uploaded assets by listing the release", "default": false},
+				"verify_checksums": {"type": "boolean", "description": "Download assets back and compare SHA-256 (requires verify_assets)", "default": false},
+				"promote_from": {"type": "string", "description": "Tag of an existing release whose assets are promoted to this release"},
+				"promote_mode": {"type": "string", "enum": ["copy", "retag"], "description": "Copy assets to a new release or retag the source release", "default": "copy"},
+				"actions_outputs": {"type": "boolean", "description": "Write GITHUB_OUTPUT and step summary when running in GitHub Actions", "default": true},
+				"log_level": {"type": "string", "enum": ["trace", "debug", "info", "warn", "error", "off"], "description": "Plugin log level", "default": "info"},
+				"api_version": {"type": "string", "description": "GitHub REST API version header", "default": "2022-11-28"},
+				"request_timeout": {"type": "string", "description": "Timeout per API request, e.g. 30s (0 disables)", "default": "60s"},
+				"upload_timeout": {"type": "string", "description": "Timeout per asset upload, e.g. 30m (0 disables)", "default": "1h"},
+				"progress_interval": {"type": "string", "description": "How often upload progress is logged, e.g. 30s (0 disables)", "default": "10s"},
+				"max_retries": {"type": "integer", "minimum": 0, "description": "Retries for transient API failures", "default": 3},
+				"sbom": {
+					"type": "object",
+					"description": "Generate an SBOM of the Go modules and upload it as an asset",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"format": {"type": "string", "enum": ["cyclonedx", "spdx"], "default": "cyclonedx"},
+						"source": {"type": "string", "enum": ["gomod", "binaries"], "description": "Read go.mod/go.sum or the build information of Go binaries among the assets", "default": "gomod"},
+						"go_mod": {"type": "string", "description": "Path of go.mod; go.sum is read next to it", "default": "go.mod"},
+						"name": {"type": "string", "description": "Asset name (defaults to sbom.cdx.json or sbom.spdx.json)"},
+						"checksum_files": {"type": "array", "items": {"type": "string"}, "description": "Patterns of checksum manifest assets that get a line for the SBOM", "default": ["*checksums.txt", "SHA256SUMS", "*.sha256sums"]}
+					}
+				},
+				"provenance": {
+					"type": "object",
+					"description": "Upload an in-toto SLSA v1 provenance statement of the uploaded assets",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"name": {"type": "string", "description": "Asset name ending in .intoto.jsonl", "default": "provenance.intoto.jsonl"},
+						"builder_id": {"type": "string", "description": "Builder ID (defaults to the GitHub Actions workflow)"},
+						"signing_key": {"type": "string", "description": "PEM private key or its path to sign the DSSE envelope"},
+						"key_id": {"type": "string", "description": "Key ID of the signature (defaults to the SHA-256 of the public key)"}
+					}
+				},
+				"releases": {
+					"type": "array",
+					"description": "Component releases created instead of the single release, e.g. for monorepos",
+					"items": {
+						"type": "object",
+						"properties": {
+							"component": {"type": "string", "description": "Component name; outputs are keyed by it"},
+							"version": {"type": "string", "description": "Component version (defaults to the release version)"},
+							"tag_prefix": {"type": "string", "description": "Prepended to the version to form the tag (defaults to <component>/v)"},
+							"tag": {"type": "string", "description": "Tag template, overrides tag_prefix"},
+							"name": {"type": "string", "description": "Release name template", "default": "{{.Component}} {{.Version}}"},
+							"body_source": {"type": "string", "enum": ["release_notes", "changelog", "generated", "none"], "default": "release_notes"},
+							"body": {"type": "string", "description": "Body template, overrides body_source"},
+							"assets": {"type": "array", "items": {"type": "string"}},
+							"draft": {"type": "boolean"},
+							"prerelease": {"type": "boolean"},
+							"generate_release_notes": {"type": "boolean"}
+						},
+						"required": ["component"]
+					}
+				},
+				"mirrors": {
+					"type": "array",
+					"description": "Repositories that receive a copy of the release with the same assets",
+					"items": {
+						"type": "object",
+						"properties": {
+							"repository": {"type": "string", "description": "Repository as owner/name"},
+							"token": {"type": "string", "description": "Token for the mirror (defaults to the plugin token)"},
+							"base_url": {"type": "string", "description": "API URL of the mirror's GitHub instance (defaults to base_url)"},
+							"upload_url": {"type": "string", "description": "Upload URL of the mirror's GitHub instance"}
+						},
+						"required": ["repository"]
+					}
+				},
+				"body_file": {
+					"type": "string",
+					"description": "Path template of a file with the release notes, e.g. docs/releases/{{.Tag}}.md"
+				},
+				"changelog_file": {
+					"type": "string",
+					"description": "Path template of a Keep-a-Changelog file; the section of the version becomes the release notes"
+				},
+				"body_precedence": {
+					"type": "array",
+					"description": "Sources of the release body in order; the first that yields text is used",
+					"items": {"type": "string", "enum": ["body_file", "changelog_file", "release_notes", "changelog", "generated"]},
+					"default": ["body_file", "changelog_file", "release_notes", "changelog"]
+				},
+				"notes": {
+					"type": "object",
+					"description": "Post-process the release notes before the release is created",
+					"properties": {
+						"linkify": {"type": "boolean", "description": "Link issue references, commit SHAs and @mentions", "default": false},
+						"full_changelog": {"type": "boolean", "description": "Append a compare link to the previous release", "default": false},
+						"contributors": {"type": "boolean", "description": "Append the avatars of contributors since the previous release", "default": false},
+						"max_length": {"type": "integer", "description": "Longest release body in characters; longer bodies are truncated", "minimum": 1000, "maximum": 125000, "default": 125000},
+						"upload_full_notes": {"type": "boolean", "description": "Upload truncated notes in full as RELEASE_NOTES.md", "default": false}
+					}
+				},
+				"resume": {
+					"type": "object",
+					"description": "Persist progress so an interrupted release can be resumed",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"state_file": {"type": "string", "description": "File the progress is persisted to", "default": ".relicta-github-state.json"}
+					}
+				},
+				"audit_log": {
+					"type": "object",
+					"description": "Record every mutating API call in a JSON Lines file",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"path": {"type": "string", "description": "File the entries are appended to", "default": "relicta-github-audit.jsonl"},
+						"upload": {"type": "boolean", "description": "Upload the audit log as a release asset", "default": false},
+						"name": {"type": "string", "description": "Asset name (defaults to the file name of path)"}
+					}
+				},
+				"announcement": {
+					"type": "object",
+					"description": "Post a release announcement to GitHub Discussions",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"owner": {"type": "string", "description": "Repository owner to post in (defaults to the release repository)"},
+						"repo": {"type": "string", "description": "Repository to post in (defaults to the release repository)"},
+						"category": {"type": "string", "description": "Discussion category name or slug"},
+						"title": {"type": "string", "description": "Title template", "default": "{{.Name}}"},
+						"body": {"type": "string", "description": "Body template", "default": "{{.ReleaseNotes}}\n\n{{.ReleaseURL}}"},
+						"pin": {"type": "boolean", "description": "Pin the discussion", "default": false},
+						"lock": {"type": "boolean", "description": "Lock the discussion", "default": false}
+					}
+				},
+				"homebrew": {
+					"type": "object",
+					"description": "Update a Homebrew tap formula from the darwin and linux assets",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"tap": {
+							"type": "object",
+							"properties": {
+								"repository": {"type": "string", "description": "Tap repository as owner/name"},
+								"branch": {"type": "string", "description": "Branch to commit to or open the pull request against (defaults to the default branch)"},
+								"pull_request": {"type": "boolean", "description": "Open a pull request instead of committing directly", "default": false},
+								"fork": {"type": "boolean", "description": "Push the pull request branch to a fork owned by the token's user", "default": false},
+								"token": {"type": "string", "description": "Token for the tap repository (defaults to the plugin token)"}
+							}
+						},
+						"formula": {"type": "string", "description": "Formula name (defaults to the repository name)"},
+						"path": {"type": "string", "description": "Formula path (defaults to Formula/<formula>.rb)"},
+						"template": {"type": "string", "description": "Formula template"},
+						"template_file": {"type": "string", "description": "File containing the formula template"},
+						"description": {"type": "string"},
+						"homepage": {"type": "string"},
+						"license": {"type": "string"},
+						"binary": {"type": "string", "description": "Binary to install (defaults to the formula name)"},
+						"commit_message": {"type": "string", "description": "Commit message template", "default": "{{.Formula}} {{.Version}}"}
+					}
+				},
+				"scoop": {
+					"type": "object",
+					"description": "Publish a Scoop manifest for the Windows zip assets",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"bucket": {
+							"type": "object",
+							"properties": {
+								"repository": {"type": "string", "description": "Repository as owner/name"},
+								"branch": {"type": "string", "description": "Branch to commit to or open the pull request against (defaults to the default branch)"},
+								"pull_request": {"type": "boolean", "description": "Open a pull request instead of committing directly", "default": false},
+								"fork": {"type": "boolean", "description": "Push the pull request branch to a fork owned by the token's user", "default": false},
+								"token": {"type": "string", "description": "Token for the repository (defaults to the plugin token)"}
+							}
+						},
+						"name": {"type": "string", "description": "App name (defaults to the repository name)"},
+						"path": {"type": "string", "description": "Manifest path (defaults to bucket/<name>.json)"},
+						"description": {"type": "string"},
+						"homepage": {"type": "string"},
+						"license": {"type": "string"},
+						"binary": {"type": "string", "description": "Executable in the zip (defaults to <name>.exe)"},
+						"commit_message": {"type": "string", "description": "Commit message template", "default": "{{.Name}}: Update to version {{.Version}}"}
+					}
+				},
+				"winget": {
+					"type": "object",
+					"description": "Publish winget manifests for the Windows zip assets",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"target": {
+							"type": "object",
+							"properties": {
+								"repository": {"type": "string", "description": "Repository as owner/name"},
+								"branch": {"type": "string", "description": "Branch to commit to or open the pull request against (defaults to the default branch)"},
+								"pull_request": {"type": "boolean", "description": "Open a pull request instead of committing directly", "default": false},
+								"fork": {"type": "boolean", "description": "Push the pull request branch to a fork owned by the token's user", "default": false},
+								"token": {"type": "string", "description": "Token for the repository (defaults to the plugin token)"}
+							}
+						},
+						"package_identifier": {"type": "string", "description": "Package identifier, e.g. MyOrg.MyApp"},
+						"publisher": {"type": "string", "description": "Publisher (defaults to the repository owner)"},
+						"package_name": {"type": "string", "description": "Package name (defaults to the repository name)"},
+						"license": {"type": "string"},
+						"short_description": {"type": "string"},
+						"homepage": {"type": "string"},
+						"binary": {"type": "string", "description": "Executable in the zip (defaults to <repo>.exe)"},
+						"path": {"type": "string", "description": "Manifest directory (defaults to the winget-pkgs layout)"},
+						"commit_message": {"type": "string", "description": "Commit message template", "default": "New version: {{.PackageIdentifier}} version {{.Version}}"}
+					}
+				},
+				"dispatch": {
+					"type": "object",
+					"description": "Trigger downstream workflows after the release",
+					"properties": {
+						"repository_dispatch": {
+							"type": "array",
+							"items": {
+								"type": "object",
+								"properties": {
+									"repository": {"type": "string", "description": "Repository as owner/name"},
+									"event_type": {"type": "string", "default": "release"},
+									"client_payload": {"type": "object", "description": "Templated fields added to version, tag, release_url and assets"},
+									"token": {"type": "string"}
+								},
+								"required": ["repository"]
+							}
+						},
+						"workflow_dispatch": {
+							"type": "array",
+							"items": {
+								"type": "object",
+								"properties": {
+									"repository": {"type": "string", "description": "Repository as owner/name (defaults to the release repository)"},
+									"workflow": {"type": "string", "description": "Workflow file name or ID"},
+									"ref": {"type": "string", "description": "Ref to run on (defaults to the default branch)"},
+									"inputs": {"type": "object", "description": "Templated workflow inputs"},
+									"token": {"type": "string"}
+								},
+								"required": ["workflow"]
+							}
+						}
+					}
+				},
+				"deployment": {
+					"type": "object",
+					"description": "Create a GitHub deployment of the release tag",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"environment": {"type": "string", "description": "Deployment environment, e.g. production"},
+						"description": {"type": "string", "description": "Description template", "default": "Release {{.Tag}}"},
+						"transient_environment": {"type": "boolean", "default": false},
+						"production_environment": {"type": "boolean", "default": false}
+					}
+				},
+				"commit_status": {
+					"type": "object",
+					"description": "Report the release on the tagged commit as a check run or commit status",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"mode": {"type": "string", "enum": ["auto", "check_run", "status"], "description": "auto uses a check run with a GitHub App token", "default": "auto"},
+						"name": {"type": "string", "description": "Check run name or status context", "default": "relicta/release"}
+					}
+				},
+				"failure_issue": {
+					"type": "object",
+					"description": "Open an issue when a release fails and close it on the next successful release of the line",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"title": {"type": "string", "description": "Title template", "default": "Release {{.Tag}} failed"},
+						"body": {"type": "string", "description": "Body and comment template; adds .Line, .Hook and .Error"},
+						"labels": {"type": "array", "items": {"type": "string"}, "default": ["release-failure"]},
+						"assignees": {"type": "array", "items": {"type": "string"}}
+					}
+				},
+				"cleanup": {
+					"type": "object",
+					"description": "Remove old releases after a successful release",
+					"properties": {
+						"enabled": {"type": "boolean", "default": false},
+						"keep_prereleases": {"type": "integer", "minimum": 0, "description": "Prereleases to keep per major.minor line (0 keeps all)"},
+						"draft_max_age_days": {"type": "integer", "minimum": 0, "description": "Delete drafts older than this many days (0 keeps all)"},
+						"delete_superseded": {"type": "boolean", "description": "Delete prereleases superseded by this stable release", "default": false},
+						"delete_tags": {"type": "boolean", "description": "Also delete the tags of removed releases", "default": false},
+						"dry_run": {"type": "boolean", "description": "Only list what would be removed", "default": false}
+					}
+				}
 			}
 		}`,
 	}
@@ -68,20 +451,127 @@ func (p *GitHubPlugin) GetInfo() plugin.Info {
 // Execute runs the plugin for a given hook.
 func (p *GitHubPlugin) Execute(ctx context.Context, req plugin.ExecuteRequest) (*plugin.ExecuteResponse, error) {
 	cfg := p.parseConfig(req.Config)
+	ctx = withLogger(ctx, p.newLogger(cfg).With("hook", string(req.Hook), "dry_run", req.DryRun))
 
+	// Dry runs and hooks that change nothing leave no entries to record
+	if !cfg.AuditLog.Enabled || req.DryRun || !mutatingHook(req.Hook) {
+		return p.executeHook(ctx, cfg, req)
+	}
+	audit, err := openAuditLog(cfg.AuditLog.Path, string(req.Hook), p.now)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+	defer func() { _ = audit.Close() }()
+
+	resp, err := p.executeHook(withAuditLog(ctx, audit), cfg, req)
+	if resp != nil {
+		reportAuditLog(resp, audit)
+	}
+	return resp, err
+}
+
+// executeHook runs the plugin for a given hook.
+func (p *GitHubPlugin) executeHook(ctx context.Context, cfg *Config, req plugin.ExecuteRequest) (*plugin.ExecuteResponse, error) {
 	switch req.Hook {
 	case plugin.HookPostPublish:
-		return p.createRelease(ctx, cfg, req.Context, req.DryRun)
+		// Resolve the discussion category first so a bad category fails
+		// before the release is created
+		var target *discussionTarget
+		if cfg.Announcement.Enabled {
+			owner, repo := announcementRepository(cfg, req.Context)
+			t, err := p.resolveDiscussionTarget(ctx, cfg, owner, repo)
+			if err != nil {
+				return &plugin.ExecuteResponse{
+					Success: false,
+					Error:   err.Error(),
+				}, nil
+			}
+			target = t
+		}
+
+		body, generated, err := resolveBody(ctx, cfg, req.Context)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, nil
+		}
+		req.Context.ReleaseNotes = body
+		switch {
+		case generated:
+			// Leave the body to GitHub rather than falling back to the changelog
+			req.Context.Changelog = ""
+			cfg.GenerateReleaseNotes = true
+		case body == "" && len(cfg.Releases) == 0:
+			// The changelog is a body source only when body_precedence lists
+			// it; components choose their own body_source
+			req.Context.Changelog = ""
+		}
+
+		if cfg.Notes.Linkify || cfg.Notes.FullChangelog || cfg.Notes.Contributors {
+			req.Context.ReleaseNotes = p.processNotes(ctx, cfg, req.Context)
+		}
+
+		// Component releases share the body; Validate rejects the steps
+		// that act on a single release
+		if len(cfg.Releases) > 0 {
+			resp := p.publishComponents(ctx, cfg, req.Context, req.DryRun)
+			if !req.DryRun && cfg.ActionsOutputs && runningInActions() {
+				if err := writeActionsOutputs(ctx, resp); err != nil {
+					loggerFromContext(ctx).Warn("failed to write GitHub Actions outputs", "error", err)
+				}
+			}
+			return resp, nil
+		}
+
+		// Mirrors upload the files of the primary release from disk
+		if len(cfg.Mirrors) > 0 {
+			files := &releaseFiles{}
+			defer files.remove()
+			ctx = withReleaseFiles(ctx, files)
+		}
+
+		resp, err := p.createRelease(ctx, cfg, req.Context, req.DryRun)
+		if err == nil && resp.Success {
+			p.afterRelease(ctx, cfg, target, req, resp)
+		}
+		if log := auditLogFromContext(ctx); err == nil && resp.Success && log != nil && cfg.AuditLog.Upload && !req.DryRun {
+			owner, repo := resolveRepository(cfg, req.Context)
+			if uploadErr := p.uploadAuditLog(ctx, cfg, log, owner, repo, resp); uploadErr != nil {
+				failAfterRelease(ctx, resp, "audit log upload", uploadErr)
+			}
+		}
+		// A release that exists is reported even when a later step failed
+		if _, published := resp.Outputs["release_url"]; err == nil && published && !req.DryRun && cfg.ActionsOutputs && runningInActions() {
+			if err := writeActionsOutputs(ctx, resp); err != nil {
+				loggerFromContext(ctx).Warn("failed to write GitHub Actions outputs", "error", err)
+			}
+		}
+		return resp, err
 	case plugin.HookOnSuccess:
-		return &plugin.ExecuteResponse{
+		resp := &plugin.ExecuteResponse{
 			Success: true,
 			Message: "Release successful",
-		}, nil
+		}
+		if cfg.Cleanup.Enabled {
+			r, err := p.cleanupReleases(ctx, cfg, req.Context, req.DryRun)
+			if err != nil {
+				return r, err
+			}
+			resp = r
+		}
+		p.finishRelease(ctx, cfg, req, resp, true)
+		return resp, nil
 	case plugin.HookOnError:
-		return &plugin.ExecuteResponse{
+		resp := &plugin.ExecuteResponse{
 			Success: true,
 			Message: "Release failed notification acknowledged",
-		}, nil
+		}
+		p.finishRelease(ctx, cfg, req, resp, false)
+		return resp, nil
 	default:
 		return &plugin.ExecuteResponse{
 			Success: true,
@@ -90,6 +580,125 @@ func (p *GitHubPlugin) Execute(ctx context.Context, req plugin.ExecuteRequest) (
 	}
 }
 
+// afterRelease runs the steps that follow a published release, such as the
+// announcement, mirrors, package manager updates, downstream dispatches, the
+// deployment and the commit status. Steps run independently; each
+// failure is recorded in resp without undoing the release.
+func (p *GitHubPlugin) afterRelease(ctx context.Context, cfg *Config, target *discussionTarget, req plugin.ExecuteRequest, resp *plugin.ExecuteResponse) {
+	updates := []struct {
+		step    string
+		enabled bool
+		update  func(context.Context, *Config, *github.Client, templateData, []plugin.Artifact, *plugin.ExecuteResponse, bool) error
+	}{
+		{step: "mirror", enabled: len(cfg.Mirrors) > 0, update: p.mirrorRelease},
+		{step: "homebrew update", enabled: cfg.Homebrew.Enabled, update: p.updateHomebrew},
+		{step: "scoop update", enabled: cfg.Scoop.Enabled, update: p.updateScoop},
+		{step: "winget update", enabled: cfg.Winget.Enabled, update: p.updateWinget},
+		{step: "dispatch", enabled: len(cfg.Dispatch.RepositoryDispatch)+len(cfg.Dispatch.WorkflowDispatch) > 0, update: p.dispatchDownstream},
+		{step: "deployment", enabled: cfg.Deployment.Enabled, update: p.createDeployment},
+		{step: "commit status", enabled: cfg.CommitStatus.Enabled, update: p.publishCommitStatus},
+	}
+
+	enabled := false
+	for _, u := range updates {
+		enabled = enabled || u.enabled
+	}
+	if target == nil && !enabled {
+		return
+	}
+
+	owner, repo := resolveRepository(cfg, req.Context)
+	releaseURL, _ := resp.Outputs["release_url"].(string)
+	data := newTemplateData(owner, repo, req.Context, releaseURL)
+
+	if target != nil {
+		if err := p.announceRelease(ctx, cfg, target, data, resp, req.DryRun); err != nil {
+			failAfterRelease(ctx, resp, "announcement", err)
+		}
+	}
+	if !enabled {
+		return
+	}
+
+	// Dry runs upload nothing, so manifests use the local files instead
+	artifacts := resp.Artifacts
+	if req.DryRun {
+		artifacts = plannedArtifacts(ctx, cfg, owner, repo, req.Context.TagName)
+	}
+
+	client, err := p.getClient(ctx, cfg)
+	for _, u := range updates {
+		if !u.enabled {
+			continue
+		}
+		stepErr := err
+		if stepErr == nil {
+			stepErr = u.update(ctx, cfg, client, data, artifacts, resp, req.DryRun)
+		}
+		if stepErr != nil {
+			failAfterRelease(ctx, resp, u.step, stepErr)
+		}
+	}
+}
+
+// finishRelease runs the steps of the on-success and on-error hooks that
+// record the outcome of the release, such as the deployment, the commit
+// status and the failure tracking issue. Failures
+// are recorded in resp like those of afterRelease.
+func (p *GitHubPlugin) finishRelease(ctx context.Context, cfg *Config, req plugin.ExecuteRequest, resp *plugin.ExecuteResponse, succeeded bool) {
+	steps := []struct {
+		step    string
+		enabled bool
+		finish  func(context.Context, *Config, *github.Client, plugin.ReleaseContext, *plugin.ExecuteResponse, bool, bool) error
+	}{
+		{step: "deployment status", enabled: cfg.Deployment.Enabled, finish: p.finishDeployment},
+		{step: "commit status", enabled: cfg.CommitStatus.Enabled, finish: p.finishCommitStatus},
+		{step: "failure issue", enabled: cfg.FailureIssue.Enabled, finish: p.trackFailureIssue},
+	}
+
+	enabled := false
+	for _, s := range steps {
+		enabled = enabled || s.enabled
+	}
+	if !enabled {
+		return
+	}
+	if resp.Outputs == nil {
+		resp.Outputs = make(map[string]any)
+	}
+
+	client, err := p.getClient(ctx, cfg)
+	for _, s := range steps {
+		if !s.enabled {
+			continue
+		}
+		stepErr := err
+		if stepErr == nil {
+			stepErr = s.finish(ctx, cfg, client, req.Context, resp, succeeded, req.DryRun)
+		}
+		if stepErr != nil {
+			failAfterRelease(ctx, resp, s.step, stepErr)
+		}
+	}
+}
+
+// failAfterRelease marks resp as failed because a post-release step failed.
+func failAfterRelease(ctx context.Context, resp *plugin.ExecuteResponse, step string, err error) {
+	loggerFromContext(ctx).Error(step+" failed", "error", err)
+
+	msg := fmt.Sprintf("%s failed: %v", step, err)
+	if resp.Success {
+		releaseURL, _ := resp.Outputs["release_url"].(string)
+		if releaseURL != "" {
+			msg = fmt.Sprintf("release %s published but %s", releaseURL, msg)
+		}
+		resp.Success = false
+		resp.Error = msg
+		return
+	}
+	resp.Error += "; " + msg
+}
+
 // createRelease creates a GitHub release.
 func (p *GitHubPlugin) createRelease(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
 	// Get GitHub client
@@ -102,16 +711,7 @@ func (p *GitHubPlugin) createRelease(ctx context.Context, cfg *Config, releaseCt
 	}
 
 	// Get owner/repo
-	owner := cfg.Owner
-	repo := cfg.Repo
-
-	if owner == "" {
-		owner = releaseCtx.RepositoryOwner
-	}
-	if repo == "" {
-		repo = releaseCtx.RepositoryName
-	}
-
+	owner, repo := resolveRepository(cfg, releaseCtx)
 	if owner == "" || repo == "" {
 		return &plugin.ExecuteResponse{
 			Success: false,
@@ -119,140 +719,383 @@ func (p *GitHubPlugin) createRelease(ctx context.Context, cfg *Config, releaseCt
 		}, nil
 	}
 
-	// Prepare release
-	tagName := releaseCtx.TagName
-	name := fmt.Sprintf("Release %s", releaseCtx.Version)
-	body := releaseCtx.ReleaseNotes
-	if body == "" {
-		body = releaseCtx.Changelog
-	}
+	release := newRelease(cfg, newTemplateData(owner, repo, releaseCtx, ""))
+	return p.publishRelease(ctx, cfg, client, owner, repo, releaseCtx, release, dryRun)
+}
 
+// newRelease returns the release to create for the release in data.
+func newRelease(cfg *Config, data templateData) *github.RepositoryRelease {
 	release := &github.RepositoryRelease{
-		TagName:              &tagName,
-		Name:                 &name,
-		Body:                 &body,
-		Draft:                &cfg.Draft,
-		Prerelease:           &cfg.Prerelease,
-		GenerateReleaseNotes: &cfg.GenerateReleaseNotes,
+		TagName:              github.String(data.Tag),
+		Name:                 github.String(data.Name),
+		Body:                 github.String(data.ReleaseNotes),
+		Draft:                github.Bool(cfg.Draft),
+		Prerelease:           github.Bool(cfg.Prerelease),
+		GenerateReleaseNotes: github.Bool(cfg.GenerateReleaseNotes),
 	}
-
 	if cfg.DiscussionCategory != "" {
-		release.DiscussionCategoryName = &cfg.DiscussionCategory
+		release.DiscussionCategoryName = github.String(cfg.DiscussionCategory)
+	}
+	return release
+}
+
+// publishRelease creates release, or promotes an existing one when
+// promote_from is set, and uploads the configured assets to it. releaseCtx
+// supplies the commit recorded in the provenance.
+func (p *GitHubPlugin) publishRelease(ctx context.Context, cfg *Config, client *github.Client, owner, repo string, releaseCtx plugin.ReleaseContext, release *github.RepositoryRelease, dryRun bool) (*plugin.ExecuteResponse, error) {
+	tagName := release.GetTagName()
+	logger := loggerFromContext(ctx).With("owner", owner, "repo", repo, "tag", tagName)
+
+	// Oversized bodies fail the whole release, so truncate them up front
+	releaseCtx.TagName = tagName
+	fullNotes, truncated := limitReleaseBody(cfg, owner, repo, previousTag(releaseCtx), release)
+	if truncated {
+		logger.Warn("release body truncated", "length", utf8.RuneCountInString(fullNotes), "truncated_length", utf8.RuneCountInString(release.GetBody()))
 	}
 
+	if cfg.PromoteFrom != "" {
+		resp, err := p.promoteRelease(ctx, client, cfg, owner, repo, release, dryRun)
+		if err == nil && truncated && resp.Outputs != nil {
+			reportTruncation(resp.Outputs, utf8.RuneCountInString(fullNotes))
+		}
+		return resp, err
+	}
+
+	// Generate the SBOM before creating the release so a failure leaves
+	// nothing behind
+	assetPaths := expandAssetPatterns(ctx, cfg.Assets)
+	var sbom *sbomResult
+	if cfg.SBOM.Enabled {
+		dir, err := os.MkdirTemp("", "relicta-sbom-")
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to create SBOM directory: %v", err),
+			}, nil
+		}
+		if !releaseFilesFromContext(ctx).keep(dir) {
+			defer func() { _ = os.RemoveAll(dir) }()
+		}
+
+		sbom, err = generateSBOM(ctx, cfg.SBOM, dir, tagName, assetPaths, p.now())
+		if err != nil {
+			logger.Error("failed to generate SBOM", "error", err)
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to generate SBOM: %v", err),
+			}, nil
+		}
+		assetPaths = sbom.Assets
+	}
+
+	// Load the signing key up front for the same reason
+	var signer crypto.Signer
+	if cfg.Provenance.Enabled && cfg.Provenance.SigningKey != "" {
+		key, err := loadSigningKey(cfg.Provenance.SigningKey)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to load provenance signing key: %v", err),
+			}, nil
+		}
+		signer = key
+	}
+	startedOn := p.now()
+
 	if dryRun {
+		logger.Info("dry run, skipping release creation", "assets", len(assetPaths))
+		outputs := map[string]any{
+			"tag_name":   tagName,
+			"owner":      owner,
+			"repo":       repo,
+			"draft":      cfg.Draft,
+			"prerelease": cfg.Prerelease,
+		}
+		if truncated {
+			reportTruncation(outputs, utf8.RuneCountInString(fullNotes))
+		}
+		if sbom != nil {
+			reportSBOM(outputs, cfg.SBOM, sbom)
+		}
+		if len(assetPaths) > 0 {
+			contentTypes := make(map[string]string, len(assetPaths))
+			for _, path := range assetPaths {
+				contentType, source := resolveContentType(path, cfg.ContentTypes)
+				contentTypes[filepath.Base(path)] = contentType
+				logger.Info("would upload asset", "path", path, "content_type", contentType, "source", source)
+			}
+			outputs["asset_content_types"] = contentTypes
+		}
 		return &plugin.ExecuteResponse{
 			Success: true,
 			Message: fmt.Sprintf("Would create GitHub release for %s/%s: %s", owner, repo, tagName),
-			Outputs: map[string]any{
-				"tag_name":   tagName,
-				"owner":      owner,
-				"repo":       repo,
-				"draft":      cfg.Draft,
-				"prerelease": cfg.Prerelease,
-			},
+			Outputs: outputs,
 		}, nil
 	}
 
-	// Create release
-	createdRelease, _, err := client.Repositories.CreateRelease(ctx, owner, repo, release)
+	// Reuse the release of an interrupted run
+	var run *resumeRun
+	if cfg.Resume.Enabled {
+		r, err := openResume(cfg.Resume.StateFile, owner, repo, tagName, p.now)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   err.Error(),
+			}, nil
+		}
+		run = r
+	}
+	createdRelease, err := run.release(ctx, client, owner, repo, tagName)
 	if err != nil {
+		logger.Error("failed to resume release", "error", err)
 		return &plugin.ExecuteResponse{
 			Success: false,
-			Error:   fmt.Sprintf("failed to create release: %v", err),
+			Error:   fmt.Sprintf("failed to resume release: %v", err),
 		}, nil
 	}
+	resumed := createdRelease != nil
+
+	// Create release
+	if !resumed {
+		logger.Info("creating release", "draft", cfg.Draft, "prerelease", cfg.Prerelease)
+		createdRelease, _, err = client.Repositories.CreateRelease(ctx, owner, repo, release)
+		if err != nil {
+			logger.Error("failed to create release", "error", err)
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to create release: %v", err),
+			}, nil
+		}
+		run.started(ctx, createdRelease)
+	}
 
 	releaseID := createdRelease.GetID()
 	htmlURL := createdRelease.GetHTMLURL()
+	logger.Info("release created", "release_id", releaseID, "release_url", htmlURL, "resumed", resumed)
 
 	// Upload assets - expand glob patterns
 	var artifacts []plugin.Artifact
-	for _, assetPattern := range cfg.Assets {
-		// Expand glob patterns
-		matches, err := filepath.Glob(assetPattern)
+	var uploads []assetUpload
+	var assetErrs []error
+	for _, assetPath := range assetPaths {
+		artifact, ok := run.uploaded(ctx, client, owner, repo, assetPath)
+		if !ok {
+			var elapsed time.Duration
+			artifact, elapsed, err = p.uploadAsset(ctx, client, cfg, owner, repo, releaseID, assetPath)
+			if err != nil {
+				logger.Error("asset upload failed", "path", assetPath, "error", err)
+				assetErrs = append(assetErrs, err)
+				continue
+			}
+			uploads = append(uploads, assetUpload{Name: artifact.Name, Size: artifact.Size, Duration: elapsed})
+		}
+		run.record(ctx, *artifact)
+		contentType, _ := resolveContentType(assetPath, cfg.ContentTypes)
+		releaseFilesFromContext(ctx).addPath(assetPath, contentType, *artifact)
+		artifacts = append(artifacts, *artifact)
+	}
+	if truncated && uploadsFullNotes(cfg) {
+		run.discard(ctx, client, owner, repo, fullNotesAssetName)
+		artifact, err := uploadFullNotes(ctx, client, owner, repo, releaseID, fullNotes)
 		if err != nil {
-			// Invalid pattern, skip
-			continue
+			logger.Error("full notes upload failed", "error", err)
+			assetErrs = append(assetErrs, err)
+		} else {
+			artifacts = append(artifacts, *artifact)
 		}
+	}
 
-		// If no matches found and pattern has no wildcards, treat as literal path
-		if len(matches) == 0 {
-			matches = []string{assetPattern}
+	// Verify uploads against what GitHub reports
+	if cfg.VerifyAssets {
+		assetErrs = append(assetErrs, p.verifyAssets(ctx, client, owner, repo, releaseID, artifacts, cfg.VerifyChecksums)...)
+	}
+
+	subjects := len(artifacts)
+	if cfg.Provenance.Enabled && subjects > 0 {
+		provenance := provenanceRun{Owner: owner, Repo: repo, Tag: tagName, WebURL: webURL(cfg), StartedOn: startedOn, FinishedOn: p.now()}
+		if sha, err := resolveTagCommit(ctx, client, owner, repo, releaseCtx); err == nil {
+			provenance.CommitSHA = sha
+		}
+		run.discard(ctx, client, owner, repo, cfg.Provenance.Name)
+		artifact, err := uploadProvenance(ctx, client, cfg.Provenance, signer, provenance, releaseID, artifacts)
+		if err != nil {
+			logger.Error("provenance upload failed", "error", err)
+			assetErrs = append(assetErrs, err)
+			subjects = 0
+		} else {
+			artifacts = append(artifacts, *artifact)
 		}
+	}
 
-		for _, assetPath := range matches {
-			artifact, err := p.uploadAsset(ctx, client, owner, repo, releaseID, assetPath)
-			if err != nil {
-				// Log but don't fail
-				continue
+	outputs := map[string]any{
+		"release_id":  releaseID,
+		"release_url": htmlURL,
+		"tag_name":    tagName,
+		"upload_url":  createdRelease.GetUploadURL(),
+	}
+	if truncated {
+		reportTruncation(outputs, utf8.RuneCountInString(fullNotes))
+	}
+	if sbom != nil {
+		reportSBOM(outputs, cfg.SBOM, sbom)
+	}
+	reportUploads(outputs, uploads)
+	run.report(outputs, resumed)
+	if cfg.Provenance.Enabled && subjects > 0 {
+		reportProvenance(outputs, cfg.Provenance, signer != nil, subjects)
+	}
+
+	return releaseResponse(cfg, fmt.Sprintf("Created GitHub release: %s", htmlURL), outputs, artifacts, assetErrs), nil
+}
+
+// releaseResponse builds the response for a published release, reporting
+// asset failures in outputs and applying the asset failure policy.
+func releaseResponse(cfg *Config, message string, outputs map[string]any, artifacts []plugin.Artifact, assetErrs []error) *plugin.ExecuteResponse {
+	if len(assetErrs) > 0 {
+		messages := make([]string, len(assetErrs))
+		for i, e := range assetErrs {
+			messages[i] = e.Error()
+		}
+		outputs["asset_errors"] = messages
+
+		if cfg.AssetFailurePolicy == AssetFailureFail {
+			return &plugin.ExecuteResponse{
+				Success:   false,
+				Error:     fmt.Sprintf("release %s published but %d asset(s) failed: %s", outputs["release_url"], len(assetErrs), strings.Join(messages, "; ")),
+				Outputs:   outputs,
+				Artifacts: artifacts,
 			}
-			artifacts = append(artifacts, *artifact)
 		}
 	}
 
 	return &plugin.ExecuteResponse{
-		Success: true,
-		Message: fmt.Sprintf("Created GitHub release: %s", htmlURL),
-		Outputs: map[string]any{
-			"release_id":  releaseID,
-			"release_url": htmlURL,
-			"tag_name":    tagName,
-		},
+		Success:   true,
+		Message:   message,
+		Outputs:   outputs,
 		Artifacts: artifacts,
-	}, nil
+	}
 }
 
-// uploadAsset uploads a release asset.
-func (p *GitHubPlugin) uploadAsset(ctx context.Context, client *github.Client, owner, repo string, releaseID int64, assetPath string) (*plugin.Artifact, error) {
+// resolveRepository returns the configured owner and repository, falling back
+// to the repository from the release context.
+func resolveRepository(cfg *Config, releaseCtx plugin.ReleaseContext) (string, string) {
+	owner := cfg.Owner
+	repo := cfg.Repo
+
+	if owner == "" {
+		owner = releaseCtx.RepositoryOwner
+	}
+	if repo == "" {
+		repo = releaseCtx.RepositoryName
+	}
+
+	return owner, repo
+}
+
+// uploadAsset uploads a release asset, logging its progress, and returns it
+// with the duration of the upload.
+func (p *GitHubPlugin) uploadAsset(ctx context.Context, client *github.Client, cfg *Config, owner, repo string, releaseID int64, assetPath string) (*plugin.Artifact, time.Duration, error) {
 	// Validate and sanitize the asset path to prevent path traversal
 	if err := helpers.ValidateAssetPath(assetPath); err != nil {
-		return nil, fmt.Errorf("invalid asset path %s: %w", assetPath, err)
+		return nil, 0, fmt.Errorf("invalid asset path %s: %w", assetPath, err)
 	}
 
 	// Verify file exists and is a regular file (not a directory)
 	info, err := os.Lstat(assetPath)
 	if err != nil {
-		return nil, fmt.Errorf("asset file not accessible %s: %w", assetPath, err)
+		return nil, 0, fmt.Errorf("asset file not accessible %s: %w", assetPath, err)
 	}
 
 	// Reject directories
 	if info.IsDir() {
-		return nil, fmt.Errorf("asset path is a directory, not a file: %s", assetPath)
+		return nil, 0, fmt.Errorf("asset path is a directory, not a file: %s", assetPath)
 	}
 
 	// Reject symlinks as a defense-in-depth measure
 	if info.Mode()&os.ModeSymlink != 0 {
-		return nil, fmt.Errorf("symlinks not allowed for asset paths: %s", assetPath)
+		return nil, 0, fmt.Errorf("symlinks not allowed for asset paths: %s", assetPath)
 	}
 
 	file, err := os.Open(assetPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open asset %s: %w", assetPath, err)
+		return nil, 0, fmt.Errorf("failed to open asset %s: %w", assetPath, err)
 	}
 	defer func() { _ = file.Close() }()
 
 	// Get file info for size
 	fileInfo, err := file.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("failed to stat asset %s: %w", assetPath, err)
+		return nil, 0, fmt.Errorf("failed to stat asset %s: %w", assetPath, err)
 	}
 
-	// Upload
+	// Hash the content so uploads can be verified later
+	checksum, err := hashFile(file)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to hash asset %s: %w", assetPath, err)
+	}
+
+	// Upload, reporting progress so long uploads are not mistaken for hangs
 	name := fileInfo.Name()
-	opts := &github.UploadOptions{Name: name}
+	logger := loggerFromContext(ctx)
+	body := &progressReader{r: file}
+	clk := p.timeSource()
+	progress := &uploadProgress{clock: clk, logger: logger, name: name, total: fileInfo.Size(), body: body, start: clk.Now()}
+	stop := progress.watch(cfg.ProgressInterval)
 
-	asset, _, err := client.Repositories.UploadReleaseAsset(ctx, owner, repo, releaseID, opts, file)
+	contentType, source := resolveContentType(assetPath, cfg.ContentTypes)
+	logger.Debug("resolved content type", "name", name, "content_type", contentType, "source", source)
+	asset, err := uploadReleaseAssetFromReader(ctx, client, owner, repo, releaseID, name, contentType, body, fileInfo.Size())
+	stop()
+	elapsed := clk.Now().Sub(progress.start)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload asset: %w", err)
+		return nil, 0, fmt.Errorf("failed to upload asset: %w", err)
 	}
 
+	logger.Info("uploaded asset",
+		"name", name,
+		"size", fileInfo.Size(),
+		"duration_ms", elapsed.Milliseconds(),
+		"bytes_per_sec", bytesPerSecond(fileInfo.Size(), elapsed))
+
 	return &plugin.Artifact{
-		Name: name,
-		Path: asset.GetBrowserDownloadURL(),
-		Type: "url",
-		Size: fileInfo.Size(),
-	}, nil
+		Name:     name,
+		Path:     asset.GetBrowserDownloadURL(),
+		Type:     "url",
+		Size:     fileInfo.Size(),
+		Checksum: checksum,
+	}, elapsed, nil
+}
+
+// hashFile returns the hex-encoded SHA-256 of file and rewinds it to the start.
+func hashFile(file *os.File) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// uploadReleaseAssetFromReader streams size bytes from r to a new release asset.
+// Unlike UploadReleaseAsset it does not require the content to be a local file.
+func uploadReleaseAssetFromReader(ctx context.Context, client *github.Client, owner, repo string, releaseID int64, name, contentType string, r io.Reader, size int64) (*github.ReleaseAsset, error) {
+	u := fmt.Sprintf("repos/%s/%s/releases/%d/assets?name=%s", owner, repo, releaseID, url.QueryEscape(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	req, err := client.NewUploadRequest(u, r, size, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := new(github.ReleaseAsset)
+	if _, err := client.Do(ctx, req, asset); err != nil {
+		return nil, err
+	}
+	return asset, nil
 }
 
 // getClient creates a GitHub client.
@@ -269,10 +1112,7 @@ func (p *GitHubPlugin) getClient(ctx context.Context, cfg *Config) (*github.Clie
 		return nil, fmt.Errorf("GitHub token is required (set GITHUB_TOKEN or configure token)")
 	}
 
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-
-	return github.NewClient(tc), nil
+	return p.clientFactory(ctx, cfg).newClient(token, cfg.BaseURL, cfg.UploadURL)
 }
 
 // parseConfig parses the plugin configuration using the SDK ConfigParser.
@@ -288,20 +1128,67 @@ func (p *GitHubPlugin) parseConfig(raw map[string]any) *Config {
 		token = os.Getenv("GH_TOKEN")
 	}
 
-	return &Config{
+	cfg := &Config{
 		Owner:                parser.GetString("owner", "", ""),
 		Repo:                 parser.GetString("repo", "", ""),
 		Token:                token,
+		BaseURL:              parser.GetString("base_url", "", ""),
+		UploadURL:            parser.GetString("upload_url", "", ""),
 		Draft:                parser.GetBool("draft", false),
 		Prerelease:           parser.GetBool("prerelease", false),
 		GenerateReleaseNotes: parser.GetBool("generate_release_notes", false),
 		Assets:               parser.GetStringSlice("assets", nil),
+		ContentTypes:         parseContentTypes(parser.GetMap("content_types")),
 		DiscussionCategory:   parser.GetString("discussion_category", "", ""),
+		AssetFailurePolicy:   parser.GetString("asset_failure_policy", "", AssetFailureContinue),
+		VerifyAssets:         parser.GetBool("verify_assets", false),
+		VerifyChecksums:      parser.GetBool("verify_checksums", false),
+		PromoteFrom:          parser.GetString("promote_from", "", ""),
+		PromoteMode:          parser.GetString("promote_mode", "", PromoteModeCopy),
+		SBOM:                 parseSBOMConfig(parser.GetMap("sbom")),
+		Provenance:           parseProvenanceConfig(parser.GetMap("provenance")),
+		BodyFile:             parser.GetString("body_file", "", ""),
+		ChangelogFile:        parser.GetString("changelog_file", "", ""),
+		BodyPrecedence:       parser.GetStringSlice("body_precedence", defaultBodyPrecedence),
+		Notes:                parseNotesConfig(parser.GetMap("notes")),
+		AuditLog:             parseAuditLogConfig(parser.GetMap("audit_log")),
+		Resume:               parseResumeConfig(parser.GetMap("resume")),
+		Announcement:         parseAnnouncementConfig(parser.GetMap("announcement")),
+		Homebrew:             parseHomebrewConfig(parser.GetMap("homebrew")),
+		Scoop:                parseScoopConfig(parser.GetMap("scoop")),
+		Winget:               parseWingetConfig(parser.GetMap("winget")),
+		Dispatch:             parseDispatchConfig(parser.GetMap("dispatch")),
+		Deployment:           parseDeploymentConfig(parser.GetMap("deployment")),
+		CommitStatus:         parseCommitStatusConfig(parser.GetMap("commit_status")),
+		FailureIssue:         parseFailureIssueConfig(parser.GetMap("failure_issue")),
+		Cleanup:              parseCleanupConfig(parser.GetMap("cleanup")),
+		ActionsOutputs:       parser.GetBool("actions_outputs", true),
+		LogLevel:             parser.GetString("log_level", "", "info"),
+		APIVersion:           parser.GetString("api_version", "", defaultAPIVersion),
+		RequestTimeout:       durationOrDefault(parser.GetString("request_timeout", "", ""), defaultRequestTimeout),
+		UploadTimeout:        durationOrDefault(parser.GetString("upload_timeout", "", ""), defaultUploadTimeout),
+		ProgressInterval:     durationOrDefault(parser.GetString("progress_interval", "", ""), defaultProgressInterval),
+		MaxRetries:           parser.GetInt("max_retries", defaultMaxRetries),
+	}
+	cfg.Releases = parseComponentReleases(raw["releases"], cfg)
+	cfg.Mirrors = parseMirrors(raw["mirrors"])
+	return cfg
+}
+
+// mapSlice returns the maps in a configured list, skipping other elements.
+func mapSlice(raw any) []map[string]any {
+	items, _ := raw.([]any)
+	out := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			out = append(out, m)
+		}
 	}
+	return out
 }
 
 // Validate validates the plugin configuration using the SDK ValidationBuilder.
-func (p *GitHubPlugin) Validate(_ context.Context, config map[string]any) (*plugin.ValidateResponse, error) {
+func (p *GitHubPlugin) Validate(ctx context.Context, config map[string]any) (*plugin.ValidateResponse, error) {
 	vb := helpers.NewValidationBuilder()
 
 	// Token is required (either from config or environment)
@@ -319,5 +1206,46 @@ func (p *GitHubPlugin) Validate(_ context.Context, config map[string]any) (*plug
 			"GitHub token is required (set GITHUB_TOKEN env var or configure token)")
 	}
 
+	cfg := p.parseConfig(config)
+	vb.ValidateURL(config, "base_url")
+	vb.ValidateURL(config, "upload_url")
+	vb.ValidateOneOf(config, "asset_failure_policy", []string{AssetFailureContinue, AssetFailureFail})
+
+	if parser.GetBool("verify_checksums", false) && !parser.GetBool("verify_assets", false) {
+		vb.AddError("verify_checksums", "verify_checksums requires verify_assets to be enabled")
+	}
+
+	vb.ValidateOneOf(config, "promote_mode", []string{PromoteModeCopy, PromoteModeRetag})
+	vb.ValidateOneOf(config, "log_level", []string{"trace", "debug", "info", "warn", "error", LogLevelOff})
+
+	for _, key := range []string{"request_timeout", "upload_timeout", "progress_interval"} {
+		if _, err := parseDuration(parser.GetString(key, "", ""), 0); err != nil {
+			vb.AddError(key, fmt.Sprintf("invalid duration: %v", err))
+		}
+	}
+	if parser.GetInt("max_retries", 0) < 0 {
+		vb.AddError("max_retries", "max_retries must not be negative")
+	}
+
+	validateContentTypes(vb, parser.GetMap("content_types"))
+	validateSBOMConfig(vb, parser.GetMap("sbom"))
+	validateProvenanceConfig(vb, parser.GetMap("provenance"))
+	validateComponentReleases(vb, config["releases"], cfg)
+	validateMirrors(vb, config["mirrors"])
+	validateBodyConfig(vb, parser)
+	validateNotesConfig(vb, parser.GetMap("notes"))
+	validateAuditLogConfig(vb, parser.GetMap("audit_log"))
+	validatePromoteConfig(vb, cfg)
+	validateResumeConfig(vb, parser.GetMap("resume"), parser.GetString("promote_from", "", ""))
+	validateCleanupConfig(vb, parser.GetMap("cleanup"))
+	validateHomebrewConfig(vb, parser.GetMap("homebrew"))
+	validateScoopConfig(vb, parser.GetMap("scoop"))
+	validateWingetConfig(vb, parser.GetMap("winget"))
+	validateDispatchConfig(vb, parser.GetMap("dispatch"))
+	validateDeploymentConfig(vb, parser.GetMap("deployment"))
+	validateCommitStatusConfig(vb, parser.GetMap("commit_status"))
+	validateFailureIssueConfig(vb, parser.GetMap("failure_issue"))
+	p.validateAnnouncementConfig(ctx, vb, cfg, parser.GetMap("announcement"))
+
 	return vb.Build(), nil
 }
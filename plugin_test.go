@@ -1556,7 +1556,7 @@ func TestUploadAssetInvalidPath(t *testing.T) {
 	ctx := context.Background()
 
 	// Create a mock client (not used since validation fails first)
-	_, err := p.uploadAsset(ctx, nil, "owner", "repo", 123, "/nonexistent/path/to/file.txt")
+	_, _, err := p.uploadAsset(ctx, nil, &Config{}, "owner", "repo", 123, "/nonexistent/path/to/file.txt")
 
 	if err == nil {
 		t.Error("expected error for nonexistent file")
@@ -1577,7 +1577,7 @@ func TestUploadAssetPathTraversal(t *testing.T) {
 	ctx := context.Background()
 
 	// Try path traversal
-	_, err := p.uploadAsset(ctx, nil, "owner", "repo", 123, "../../../etc/passwd")
+	_, _, err := p.uploadAsset(ctx, nil, &Config{}, "owner", "repo", 123, "../../../etc/passwd")
 
 	if err == nil {
 		t.Error("expected error for path traversal attempt")
@@ -1601,7 +1601,7 @@ func TestUploadAssetDirectory(t *testing.T) {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	// Try to upload a directory
-	_, err = p.uploadAsset(ctx, nil, "owner", "repo", 123, tmpDir)
+	_, _, err = p.uploadAsset(ctx, nil, &Config{}, "owner", "repo", 123, tmpDir)
 
 	if err == nil {
 		t.Error("expected error when uploading a directory")
@@ -1635,7 +1635,7 @@ func TestUploadAssetSymlink(t *testing.T) {
 	}
 
 	// Try to upload a symlink
-	_, err = p.uploadAsset(ctx, nil, "owner", "repo", 123, symlinkPath)
+	_, _, err = p.uploadAsset(ctx, nil, &Config{}, "owner", "repo", 123, symlinkPath)
 
 	if err == nil {
 		t.Error("expected error when uploading a symlink")
@@ -1688,7 +1688,7 @@ func TestUploadAssetWithValidFile(t *testing.T) {
 	p := &GitHubPlugin{}
 	ctx := context.Background()
 
-	artifact, err := p.uploadAsset(ctx, client, "owner", "repo", 123, tmpFile.Name())
+	artifact, _, err := p.uploadAsset(ctx, client, &Config{}, "owner", "repo", 123, tmpFile.Name())
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -1705,6 +1705,10 @@ func TestUploadAssetWithValidFile(t *testing.T) {
 	if artifact.Size != int64(len(content)) {
 		t.Errorf("expected artifact size %d, got %d", len(content), artifact.Size)
 	}
+
+	if artifact.Checksum != sha256Hex(string(content)) {
+		t.Errorf("expected artifact checksum %s, got %q", sha256Hex(string(content)), artifact.Checksum)
+	}
 }
 
 // TestCreateReleaseWithMockServer tests createRelease with a mock GitHub API.
@@ -1780,16 +1784,12 @@ func TestCreateReleaseAPIError(t *testing.T) {
 	p := &GitHubPlugin{}
 	ctx := context.Background()
 
-	// Create GitHub client with mock server
-	client := github.NewClient(nil)
-	serverURL, _ := url.Parse(server.URL + "/")
-	client.BaseURL = serverURL
-	client.UploadURL = serverURL
-
+	// Point the client at the mock server
 	cfg := &Config{
-		Owner: "test-owner",
-		Repo:  "test-repo",
-		Token: "invalid_token",
+		Owner:   "test-owner",
+		Repo:    "test-repo",
+		Token:   "invalid_token",
+		BaseURL: server.URL + "/",
 	}
 
 	releaseCtx := plugin.ReleaseContext{
@@ -1797,9 +1797,6 @@ func TestCreateReleaseAPIError(t *testing.T) {
 		TagName: "v1.0.0",
 	}
 
-	// Call createRelease directly using a helper that injects the client
-	// Since we cannot inject the client, we test through Execute
-	// which will fail because the real API requires valid credentials
 	resp, err := p.createRelease(ctx, cfg, releaseCtx, false)
 
 	// The function should return a response with Success=false when API fails
@@ -1811,8 +1808,8 @@ func TestCreateReleaseAPIError(t *testing.T) {
 		t.Error("expected Success=false for API error")
 	}
 
-	if resp.Error == "" {
-		t.Error("expected non-empty error message")
+	if !strings.Contains(resp.Error, "Bad credentials") {
+		t.Errorf("expected 'Bad credentials' error, got %q", resp.Error)
 	}
 }
 
@@ -1960,7 +1957,7 @@ func TestUploadAssetAPIFailure(t *testing.T) {
 	p := &GitHubPlugin{}
 	ctx := context.Background()
 
-	_, err = p.uploadAsset(ctx, client, "owner", "repo", 123, tmpFile.Name())
+	_, _, err = p.uploadAsset(ctx, client, &Config{}, "owner", "repo", 123, tmpFile.Name())
 
 	if err == nil {
 		t.Error("expected error for API failure")
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// defaultProgressInterval is how often upload progress is logged.
+const defaultProgressInterval = 10 * time.Second
+
+// progressReader counts the bytes read from an upload body.
+type progressReader struct {
+	r    io.Reader
+	read atomic.Int64
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	pr.read.Add(int64(n))
+	return n, err
+}
+
+// uploadProgress logs the progress of one upload.
+type uploadProgress struct {
+	clock  clock
+	logger hclog.Logger
+	name   string
+	total  int64
+	body   *progressReader
+	start  time.Time
+	last   int64
+}
+
+// tick logs the percentage sent and the throughput so far. Ticks without new
+// bytes, such as while GitHub processes a finished upload, are logged as
+// keepalives so CI systems do not kill the job for inactivity.
+func (u *uploadProgress) tick(now time.Time) {
+	sent := u.body.read.Load()
+	elapsed := now.Sub(u.start)
+	if sent == u.last {
+		u.logger.Info("upload in progress",
+			"name", u.name,
+			"bytes", sent,
+			"total", u.total,
+			"elapsed_s", int64(elapsed.Seconds()))
+		return
+	}
+	u.last = sent
+	u.logger.Info("upload progress",
+		"name", u.name,
+		"percent", uploadPercent(sent, u.total),
+		"bytes", sent,
+		"total", u.total,
+		"bytes_per_sec", bytesPerSecond(sent, elapsed))
+}
+
+// watch calls tick every interval until the returned stop function is called.
+// A zero interval disables progress reporting.
+func (u *uploadProgress) watch(interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticks, stop := u.clock.NewTicker(interval)
+		defer stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticks:
+				u.tick(now)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// uploadPercent returns sent as a whole percentage of total.
+func uploadPercent(sent, total int64) int64 {
+	if total <= 0 {
+		return 100
+	}
+	return sent * 100 / total
+}
+
+// bytesPerSecond returns the throughput of n bytes over elapsed.
+func bytesPerSecond(n int64, elapsed time.Duration) int64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(n) / elapsed.Seconds())
+}
+
+// assetUpload is the throughput of one uploaded asset.
+type assetUpload struct {
+	Name     string
+	Size     int64
+	Duration time.Duration
+}
+
+// reportUploads records per-asset and overall upload throughput in outputs.
+func reportUploads(outputs map[string]any, uploads []assetUpload) {
+	if len(uploads) == 0 {
+		return
+	}
+	var size int64
+	var duration time.Duration
+	metrics := make([]map[string]any, len(uploads))
+	for i, u := range uploads {
+		metrics[i] = map[string]any{
+			"name":          u.Name,
+			"size":          u.Size,
+			"duration_ms":   u.Duration.Milliseconds(),
+			"bytes_per_sec": bytesPerSecond(u.Size, u.Duration),
+		}
+		size += u.Size
+		duration += u.Duration
+	}
+	outputs["asset_uploads"] = metrics
+	outputs["upload_duration_ms"] = duration.Milliseconds()
+	outputs["upload_bytes_per_sec"] = bytesPerSecond(size, duration)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// TestUploadProgress tests progress and keepalive lines while an upload runs.
+func TestUploadProgress(t *testing.T) {
+	var out bytes.Buffer
+	logger := hclog.New(&hclog.LoggerOptions{Output: &out, JSONFormat: true})
+	body := &progressReader{r: strings.NewReader(strings.Repeat("x", 1000))}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	progress := &uploadProgress{logger: logger, name: "app.tar.gz", total: 1000, body: body, start: start}
+
+	if _, err := io.CopyN(io.Discard, body, 250); err != nil {
+		t.Fatal(err)
+	}
+	progress.tick(start.Add(time.Second))
+	progress.tick(start.Add(2 * time.Second))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two lines, got %q", out.String())
+	}
+	var first, second map[string]any
+	_ = json.Unmarshal([]byte(lines[0]), &first)
+	_ = json.Unmarshal([]byte(lines[1]), &second)
+	if first["@message"] != "upload progress" || first["percent"] != float64(25) || first["bytes_per_sec"] != float64(250) {
+		t.Errorf("unexpected progress line %v", first)
+	}
+	if second["@message"] != "upload in progress" || second["elapsed_s"] != float64(2) {
+		t.Errorf("unexpected keepalive line %v", second)
+	}
+}
+
+// TestUploadProgressWatch tests that progress is logged on the plugin clock's ticks.
+func TestUploadProgressWatch(t *testing.T) {
+	var out bytes.Buffer
+	logger := hclog.New(&hclog.LoggerOptions{Output: &out, JSONFormat: true})
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := &fakeClock{now: start, ticks: make(chan time.Time)}
+	body := &progressReader{r: strings.NewReader("")}
+	progress := &uploadProgress{clock: clk, logger: logger, name: "app.tar.gz", total: 1000, body: body, start: start}
+
+	stop := progress.watch(time.Minute)
+	clk.ticks <- start.Add(3 * time.Minute)
+	stop()
+
+	var line map[string]any
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatalf("expected one log line, got %q", out.String())
+	}
+	if line["@message"] != "upload in progress" || line["elapsed_s"] != float64(180) {
+		t.Errorf("unexpected keepalive line %v", line)
+	}
+}
+
+// TestExecuteUploadMetrics tests per-asset throughput in the outputs.
+func TestExecuteUploadMetrics(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"app.tar.gz": "binary", "app.zip": "zip"})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  fakeConfig(fake, map[string]any{"assets": []any{dir + "/*"}, "progress_interval": "1ms"}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	uploads, _ := resp.Outputs["asset_uploads"].([]map[string]any)
+	if len(uploads) != 2 {
+		t.Fatalf("unexpected upload metrics %v", resp.Outputs["asset_uploads"])
+	}
+	for i, u := range uploads {
+		if u["name"] != resp.Artifacts[i].Name || u["size"] != resp.Artifacts[i].Size {
+			t.Errorf("metrics %v do not match artifact %+v", u, resp.Artifacts[i])
+		}
+		if _, ok := u["bytes_per_sec"].(int64); !ok {
+			t.Errorf("expected bytes_per_sec in %v", u)
+		}
+	}
+	if _, ok := resp.Outputs["upload_duration_ms"].(int64); !ok {
+		t.Errorf("expected upload_duration_ms in %v", resp.Outputs)
+	}
+}
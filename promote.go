@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Promotion modes.
+const (
+	PromoteModeCopy  = "copy"
+	PromoteModeRetag = "retag"
+)
+
+// validatePromoteConfig rejects options that act on local files, which a
+// promoted release does not upload.
+func validatePromoteConfig(vb *helpers.ValidationBuilder, cfg *Config) {
+	if cfg.PromoteFrom == "" {
+		return
+	}
+	for _, option := range []struct {
+		key string
+		set bool
+	}{
+		{"assets", len(cfg.Assets) > 0},
+		{"sbom", cfg.SBOM.Enabled},
+		{"provenance", cfg.Provenance.Enabled},
+		{"verify_assets", cfg.VerifyAssets},
+	} {
+		if option.set {
+			vb.AddError(option.key, option.key+" cannot be combined with promote_from")
+		}
+	}
+}
+
+// promoteRelease publishes release using the assets of the existing release
+// tagged cfg.PromoteFrom, without uploading any local files.
+func (p *GitHubPlugin) promoteRelease(ctx context.Context, client *github.Client, cfg *Config, owner, repo string, release *github.RepositoryRelease, dryRun bool) (*plugin.ExecuteResponse, error) {
+	source, _, err := client.Repositories.GetReleaseByTag(ctx, owner, repo, cfg.PromoteFrom)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to find release %s to promote: %v", cfg.PromoteFrom, err),
+		}, nil
+	}
+
+	sourceAssets, err := listReleaseAssets(ctx, client, owner, repo, source.GetID())
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to list assets of release %s: %v", cfg.PromoteFrom, err),
+		}, nil
+	}
+
+	tagName := release.GetTagName()
+
+	if dryRun {
+		names := make([]string, len(sourceAssets))
+		for i, a := range sourceAssets {
+			names[i] = a.GetName()
+		}
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("Would promote %s to %s in %s/%s (%s)", cfg.PromoteFrom, tagName, owner, repo, cfg.PromoteMode),
+			Outputs: map[string]any{
+				"tag_name":      tagName,
+				"owner":         owner,
+				"repo":          repo,
+				"promoted_from": cfg.PromoteFrom,
+				"promote_mode":  cfg.PromoteMode,
+				"assets":        names,
+			},
+		}, nil
+	}
+
+	if cfg.PromoteMode == PromoteModeRetag {
+		return p.retagRelease(ctx, client, cfg, owner, repo, source, sourceAssets, release)
+	}
+
+	createdRelease, _, err := client.Repositories.CreateRelease(ctx, owner, repo, release)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create release: %v", err),
+		}, nil
+	}
+
+	logger := loggerFromContext(ctx).With("promoted_from", cfg.PromoteFrom, "tag", tagName)
+	logger.Info("promoting release", "assets", len(sourceAssets), "release_id", createdRelease.GetID())
+
+	var artifacts []plugin.Artifact
+	var assetErrs []error
+	for _, asset := range sourceAssets {
+		repository := repoClient{client: client, owner: owner, repo: repo}
+		artifact, err := copyReleaseAsset(ctx, repository, repository, asset, createdRelease.GetID())
+		if err != nil {
+			logger.Error("asset copy failed", "name", asset.GetName(), "error", err)
+			assetErrs = append(assetErrs, err)
+			continue
+		}
+		artifacts = append(artifacts, *artifact)
+	}
+
+	htmlURL := createdRelease.GetHTMLURL()
+	outputs := map[string]any{
+		"release_id":    createdRelease.GetID(),
+		"release_url":   htmlURL,
+		"tag_name":      tagName,
+		"upload_url":    createdRelease.GetUploadURL(),
+		"promoted_from": cfg.PromoteFrom,
+	}
+
+	return releaseResponse(cfg, fmt.Sprintf("Promoted %s to GitHub release: %s", cfg.PromoteFrom, htmlURL), outputs, artifacts, assetErrs), nil
+}
+
+// retagRelease moves the source release to the new tag, replacing its name and
+// notes and clearing the prerelease flag as configured. Assets stay in place.
+func (p *GitHubPlugin) retagRelease(ctx context.Context, client *github.Client, cfg *Config, owner, repo string, source *github.RepositoryRelease, sourceAssets []*github.ReleaseAsset, release *github.RepositoryRelease) (*plugin.ExecuteResponse, error) {
+	edit := &github.RepositoryRelease{
+		TagName:    release.TagName,
+		Name:       release.Name,
+		Body:       release.Body,
+		Draft:      release.Draft,
+		Prerelease: release.Prerelease,
+	}
+
+	edited, _, err := client.Repositories.EditRelease(ctx, owner, repo, source.GetID(), edit)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to retag release %s: %v", cfg.PromoteFrom, err),
+		}, nil
+	}
+
+	// Package managers pin archives by SHA-256, which only a download of
+	// the assets in place reveals
+	checksums := cfg.Homebrew.Enabled || cfg.Scoop.Enabled || cfg.Winget.Enabled
+	artifacts := make([]plugin.Artifact, len(sourceAssets))
+	var assetErrs []error
+	for i, a := range sourceAssets {
+		artifacts[i] = plugin.Artifact{
+			Name: a.GetName(),
+			Path: a.GetBrowserDownloadURL(),
+			Type: "url",
+			Size: int64(a.GetSize()),
+		}
+		if !checksums {
+			continue
+		}
+		digest, err := downloadAssetDigest(ctx, client, owner, repo, a.GetID())
+		if err != nil {
+			assetErrs = append(assetErrs, fmt.Errorf("failed to download asset %s for its checksum: %w", a.GetName(), err))
+			continue
+		}
+		artifacts[i].Checksum = digest
+	}
+
+	htmlURL := edited.GetHTMLURL()
+	outputs := map[string]any{
+		"release_id":    edited.GetID(),
+		"release_url":   htmlURL,
+		"tag_name":      edited.GetTagName(),
+		"upload_url":    edited.GetUploadURL(),
+		"promoted_from": cfg.PromoteFrom,
+	}
+	return releaseResponse(cfg, fmt.Sprintf("Retagged %s as GitHub release: %s", cfg.PromoteFrom, htmlURL), outputs, artifacts, assetErrs), nil
+}
+
+// repoClient is a client for one repository.
+type repoClient struct {
+	client      *github.Client
+	owner, repo string
+}
+
+// copyReleaseAsset streams a release asset of from into the release with
+// releaseID in to and verifies that the copy has the same SHA-256 as the
+// source.
+func copyReleaseAsset(ctx context.Context, from, to repoClient, asset *github.ReleaseAsset, releaseID int64) (*plugin.Artifact, error) {
+	name := asset.GetName()
+
+	rc, _, err := from.client.Repositories.DownloadReleaseAsset(ctx, from.owner, from.repo, asset.GetID(), redirectClient(from.client))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download asset %s: %w", name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	h := sha256.New()
+	size := int64(asset.GetSize())
+	copied, err := uploadReleaseAssetFromReader(ctx, to.client, to.owner, to.repo, releaseID, name, asset.GetContentType(), io.TeeReader(rc, h), size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload asset %s: %w", name, err)
+	}
+	sourceDigest := hex.EncodeToString(h.Sum(nil))
+
+	copiedDigest, err := downloadAssetDigest(ctx, to.client, to.owner, to.repo, copied.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to download copied asset %s for verification: %w", name, err)
+	}
+	if copiedDigest != sourceDigest {
+		return nil, fmt.Errorf("asset %s checksum mismatch after copy: source %s, copy %s", name, sourceDigest, copiedDigest)
+	}
+
+	return &plugin.Artifact{
+		Name:     name,
+		Path:     copied.GetBrowserDownloadURL(),
+		Type:     "url",
+		Size:     size,
+		Checksum: sourceDigest,
+	}, nil
+}
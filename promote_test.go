@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// promoteServer is a mock GitHub API holding a source prerelease with one asset.
+type promoteServer struct {
+	mu       sync.Mutex
+	uploads  map[string]string
+	edited   map[string]any
+	corrupt  bool
+	releases int
+}
+
+func newPromoteServer(t *testing.T, corrupt bool) (*promoteServer, *github.Client) {
+	t.Helper()
+
+	ps := &promoteServer{uploads: make(map[string]string), corrupt: corrupt}
+	server := httptest.NewServer(http.HandlerFunc(ps.handle))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	serverURL, _ := url.Parse(server.URL + "/")
+	client.BaseURL = serverURL
+	client.UploadURL = serverURL
+	return ps, client
+}
+
+func (ps *promoteServer) handle(w http.ResponseWriter, r *http.Request) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/repos/owner/repo/releases/tags/v1.4.0-rc.3":
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": 1, "tag_name": "v1.4.0-rc.3", "prerelease": true})
+	case r.Method == "GET" && r.URL.Path == "/repos/owner/repo/releases/1/assets":
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"id": 10, "name": "app.tar.gz", "size": len("rc artifact"), "state": "uploaded", "content_type": "application/gzip"},
+		})
+	case r.Method == "GET" && r.URL.Path == "/repos/owner/repo/releases/assets/10":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte("rc artifact"))
+	case r.Method == "POST" && r.URL.Path == "/repos/owner/repo/releases":
+		ps.releases++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": 2, "tag_name": "v1.4.0", "html_url": "https://github.com/owner/repo/releases/tag/v1.4.0"})
+	case r.Method == "POST" && r.URL.Path == "/repos/owner/repo/releases/2/assets":
+		body, _ := io.ReadAll(r.Body)
+		if ps.corrupt {
+			body = append(body, '!')
+		}
+		name := r.URL.Query().Get("name")
+		ps.uploads[name] = string(body)
+		if r.Header.Get("Content-Type") != "application/gzip" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": 20, "name": name, "size": len(body), "browser_download_url": "https://github.com/owner/repo/releases/download/v1.4.0/" + name})
+	case r.Method == "GET" && r.URL.Path == "/repos/owner/repo/releases/assets/20":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte(ps.uploads["app.tar.gz"]))
+	case r.Method == "PATCH" && r.URL.Path == "/repos/owner/repo/releases/1":
+		_ = json.NewDecoder(r.Body).Decode(&ps.edited)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": 1, "tag_name": ps.edited["tag_name"], "html_url": "https://github.com/owner/repo/releases/tag/v1.4.0"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func promotedRelease() *github.RepositoryRelease {
+	return &github.RepositoryRelease{
+		TagName:    github.String("v1.4.0"),
+		Name:       github.String("Release 1.4.0"),
+		Body:       github.String("Stable notes"),
+		Draft:      github.Bool(false),
+		Prerelease: github.Bool(false),
+	}
+}
+
+// TestPromoteReleaseCopy tests promoting by copying assets to a new release.
+func TestPromoteReleaseCopy(t *testing.T) {
+	ps, client := newPromoteServer(t, false)
+	cfg := &Config{PromoteFrom: "v1.4.0-rc.3", PromoteMode: PromoteModeCopy}
+
+	p := &GitHubPlugin{}
+	resp, err := p.promoteRelease(context.Background(), client, cfg, "owner", "repo", promotedRelease(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if ps.uploads["app.tar.gz"] != "rc artifact" {
+		t.Errorf("expected asset content to be copied, got %q", ps.uploads["app.tar.gz"])
+	}
+	if len(resp.Artifacts) != 1 || resp.Artifacts[0].Checksum != sha256Hex("rc artifact") {
+		t.Errorf("unexpected artifacts: %+v", resp.Artifacts)
+	}
+	if resp.Outputs["promoted_from"] != "v1.4.0-rc.3" {
+		t.Errorf("expected promoted_from output, got %v", resp.Outputs["promoted_from"])
+	}
+}
+
+// TestPromoteReleaseChecksumMismatch tests that a corrupted copy is reported.
+func TestPromoteReleaseChecksumMismatch(t *testing.T) {
+	_, client := newPromoteServer(t, true)
+	cfg := &Config{PromoteFrom: "v1.4.0-rc.3", PromoteMode: PromoteModeCopy, AssetFailurePolicy: AssetFailureFail}
+
+	p := &GitHubPlugin{}
+	resp, err := p.promoteRelease(context.Background(), client, cfg, "owner", "repo", promotedRelease(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success {
+		t.Fatal("expected failure for checksum mismatch")
+	}
+	if !strings.Contains(resp.Error, "checksum mismatch") {
+		t.Errorf("expected checksum mismatch error, got %q", resp.Error)
+	}
+}
+
+// TestPromoteReleaseRetag tests promoting by moving the source release to the new tag.
+func TestPromoteReleaseRetag(t *testing.T) {
+	ps, client := newPromoteServer(t, false)
+	cfg := &Config{PromoteFrom: "v1.4.0-rc.3", PromoteMode: PromoteModeRetag}
+
+	p := &GitHubPlugin{}
+	resp, err := p.promoteRelease(context.Background(), client, cfg, "owner", "repo", promotedRelease(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if ps.releases != 0 {
+		t.Errorf("expected no new release, got %d", ps.releases)
+	}
+	if ps.edited["tag_name"] != "v1.4.0" || ps.edited["prerelease"] != false || ps.edited["body"] != "Stable notes" {
+		t.Errorf("unexpected edit request: %v", ps.edited)
+	}
+	if len(resp.Artifacts) != 1 || resp.Artifacts[0].Name != "app.tar.gz" {
+		t.Errorf("unexpected artifacts: %+v", resp.Artifacts)
+	}
+}
+
+// TestPromoteReleaseRetagChecksums tests that retagged assets get checksums
+// when a package manager needs them.
+func TestPromoteReleaseRetagChecksums(t *testing.T) {
+	_, client := newPromoteServer(t, false)
+	cfg := &Config{PromoteFrom: "v1.4.0-rc.3", PromoteMode: PromoteModeRetag, Homebrew: HomebrewConfig{Enabled: true}}
+
+	p := &GitHubPlugin{}
+	resp, err := p.promoteRelease(context.Background(), client, cfg, "owner", "repo", promotedRelease(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success || len(resp.Artifacts) != 1 || resp.Artifacts[0].Checksum != sha256Hex("rc artifact") {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+// TestValidatePromote tests that options acting on local files are rejected
+// with promote_from.
+func TestValidatePromote(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":         "ghp_test",
+		"promote_from":  "v1.4.0-rc.3",
+		"assets":        []any{"dist/*"},
+		"sbom":          map[string]any{"enabled": true},
+		"provenance":    map[string]any{"enabled": true},
+		"verify_assets": true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := make(map[string]bool)
+	for _, e := range resp.Errors {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{"assets", "sbom", "provenance", "verify_assets"} {
+		if !fields[field] {
+			t.Errorf("expected an error for %s, got %+v", field, resp.Errors)
+		}
+	}
+}
+
+// TestPromoteReleaseDryRun tests that dry run lists the assets without changes.
+func TestPromoteReleaseDryRun(t *testing.T) {
+	ps, client := newPromoteServer(t, false)
+	cfg := &Config{PromoteFrom: "v1.4.0-rc.3", PromoteMode: PromoteModeCopy}
+
+	p := &GitHubPlugin{}
+	resp, err := p.promoteRelease(context.Background(), client, cfg, "owner", "repo", promotedRelease(), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success || !strings.Contains(resp.Message, "Would promote") {
+		t.Errorf("unexpected dry run response: %+v", resp)
+	}
+	if ps.releases != 0 || len(ps.uploads) != 0 {
+		t.Error("expected no changes in dry run")
+	}
+	if names, _ := resp.Outputs["assets"].([]string); len(names) != 1 || names[0] != "app.tar.gz" {
+		t.Errorf("unexpected assets output: %v", resp.Outputs["assets"])
+	}
+}
+
+// TestPromoteReleaseMissingSource tests promotion from a tag without a release.
+func TestPromoteReleaseMissingSource(t *testing.T) {
+	_, client := newPromoteServer(t, false)
+	cfg := &Config{PromoteFrom: "v0.0.1-rc.1", PromoteMode: PromoteModeCopy}
+
+	p := &GitHubPlugin{}
+	resp, err := p.promoteRelease(context.Background(), client, cfg, "owner", "repo", promotedRelease(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "failed to find release v0.0.1-rc.1") {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+const (
+	// inTotoStatementType is the type of an in-toto v1 statement.
+	inTotoStatementType = "https://in-toto.io/Statement/v1"
+	// slsaProvenanceType is the predicate type of SLSA v1 provenance.
+	slsaProvenanceType = "https://slsa.dev/provenance/v1"
+	// inTotoPayloadType is the DSSE payload type of an in-toto statement.
+	inTotoPayloadType = "application/vnd.in-toto+json"
+	// provenanceBuildType describes the build: a Relicta release run by this
+	// plugin.
+	provenanceBuildType = "https://github.com/relicta-tech/plugin-github/release/v1"
+	// defaultProvenanceBuilder identifies builds outside GitHub Actions.
+	defaultProvenanceBuilder = "https://github.com/relicta-tech/plugin-github"
+	// provenanceSuffix is the required suffix of provenance asset names.
+	provenanceSuffix = ".intoto.jsonl"
+)
+
+// ProvenanceConfig configures an in-toto provenance statement for the
+// uploaded assets.
+type ProvenanceConfig struct {
+	// Enabled turns on provenance generation.
+	Enabled bool `json:"enabled"`
+	// Name is the asset name, ending in .intoto.jsonl.
+	Name string `json:"name,omitempty"`
+	// BuilderID overrides the builder derived from the CI environment.
+	BuilderID string `json:"builder_id,omitempty"`
+	// SigningKey is a PEM private key (ECDSA, Ed25519 or RSA), or the path
+	// of one, used to sign the DSSE envelope.
+	SigningKey string `json:"signing_key,omitempty"`
+	// KeyID is the key ID in the signature; it defaults to the SHA-256 of
+	// the public key.
+	KeyID string `json:"key_id,omitempty"`
+}
+
+// parseProvenanceConfig parses the provenance section of the configuration.
+func parseProvenanceConfig(raw map[string]any) ProvenanceConfig {
+	parser := helpers.NewConfigParser(raw)
+	return ProvenanceConfig{
+		Enabled:    parser.GetBool("enabled", false),
+		Name:       parser.GetString("name", "", "provenance"+provenanceSuffix),
+		BuilderID:  parser.GetString("builder_id", "", ""),
+		SigningKey: parser.GetString("signing_key", "", ""),
+		KeyID:      parser.GetString("key_id", "", ""),
+	}
+}
+
+// validateProvenanceConfig validates the provenance section of the
+// configuration, including that the signing key loads.
+func validateProvenanceConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	cfg := parseProvenanceConfig(raw)
+	if !strings.HasSuffix(cfg.Name, provenanceSuffix) || strings.ContainsAny(cfg.Name, `/\`) {
+		vb.AddError("provenance.name", "name must be a file name ending in "+provenanceSuffix)
+	}
+	if cfg.Enabled && cfg.SigningKey != "" {
+		if _, err := loadSigningKey(cfg.SigningKey); err != nil {
+			vb.AddError("provenance.signing_key", err.Error())
+		}
+	}
+}
+
+// loadSigningKey loads a PEM private key given inline or as a file path.
+func loadSigningKey(key string) (crypto.Signer, error) {
+	content := []byte(key)
+	if !strings.Contains(key, "-----BEGIN") {
+		var err error
+		if content, err = os.ReadFile(key); err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+	var parsed any
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key %T", parsed)
+	}
+	return signer, nil
+}
+
+// inTotoSubject is an artifact the statement is about.
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// inTotoStatement is an in-toto v1 statement with a SLSA v1 predicate.
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	Subject       []inTotoSubject `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     slsaProvenance  `json:"predicate"`
+}
+
+// slsaResource is a resolved dependency of the build.
+type slsaResource struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// slsaProvenance is a SLSA v1 provenance predicate.
+type slsaProvenance struct {
+	BuildDefinition struct {
+		BuildType            string         `json:"buildType"`
+		ExternalParameters   map[string]any `json:"externalParameters"`
+		InternalParameters   map[string]any `json:"internalParameters,omitempty"`
+		ResolvedDependencies []slsaResource `json:"resolvedDependencies,omitempty"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+		Metadata struct {
+			InvocationID string `json:"invocationId,omitempty"`
+			StartedOn    string `json:"startedOn,omitempty"`
+			FinishedOn   string `json:"finishedOn,omitempty"`
+		} `json:"metadata"`
+	} `json:"runDetails"`
+}
+
+// dsseSignature is a DSSE signature.
+type dsseSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// dsseEnvelope is a DSSE envelope around an in-toto statement.
+type dsseEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+// provenanceRun describes the release run recorded in the provenance.
+type provenanceRun struct {
+	Owner, Repo, Tag, CommitSHA string
+	WebURL                      string
+	StartedOn, FinishedOn       time.Time
+}
+
+// newProvenanceStatement returns the statement for artifacts, with the
+// builder and invocation taken from the GitHub Actions environment when
+// running there.
+func newProvenanceStatement(cfg ProvenanceConfig, run provenanceRun, artifacts []plugin.Artifact) *inTotoStatement {
+	statement := &inTotoStatement{Type: inTotoStatementType, PredicateType: slsaProvenanceType}
+	for _, a := range artifacts {
+		statement.Subject = append(statement.Subject, inTotoSubject{Name: a.Name, Digest: map[string]string{"sha256": a.Checksum}})
+	}
+
+	repoURL := fmt.Sprintf("%s/%s/%s", run.WebURL, run.Owner, run.Repo)
+	p := &statement.Predicate
+	p.BuildDefinition.BuildType = provenanceBuildType
+	p.BuildDefinition.ExternalParameters = map[string]any{
+		"repository": repoURL,
+		"tag":        run.Tag,
+	}
+	source := slsaResource{URI: "git+" + repoURL + "@refs/tags/" + run.Tag}
+	if run.CommitSHA != "" {
+		source.Digest = map[string]string{"gitCommit": run.CommitSHA}
+	}
+	p.BuildDefinition.ResolvedDependencies = []slsaResource{source}
+	p.RunDetails.Builder.ID = defaultProvenanceBuilder
+	p.RunDetails.Metadata.StartedOn = run.StartedOn.UTC().Format(time.RFC3339)
+	p.RunDetails.Metadata.FinishedOn = run.FinishedOn.UTC().Format(time.RFC3339)
+
+	if runningInActions() {
+		server := os.Getenv("GITHUB_SERVER_URL")
+		workflowRef := os.Getenv("GITHUB_WORKFLOW_REF")
+		p.BuildDefinition.ExternalParameters["workflow"] = map[string]string{
+			"ref":        workflowRef,
+			"repository": server + "/" + os.Getenv("GITHUB_REPOSITORY"),
+		}
+		p.BuildDefinition.InternalParameters = map[string]any{
+			"github": map[string]string{
+				"event_name":          os.Getenv("GITHUB_EVENT_NAME"),
+				"repository_id":       os.Getenv("GITHUB_REPOSITORY_ID"),
+				"repository_owner_id": os.Getenv("GITHUB_REPOSITORY_OWNER_ID"),
+				"runner_environment":  os.Getenv("RUNNER_ENVIRONMENT"),
+			},
+		}
+		if workflowRef != "" {
+			p.RunDetails.Builder.ID = server + "/" + workflowRef
+		}
+		p.RunDetails.Metadata.InvocationID = fmt.Sprintf("%s/%s/actions/runs/%s/attempts/%s",
+			server, os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"), os.Getenv("GITHUB_RUN_ATTEMPT"))
+	}
+	if cfg.BuilderID != "" {
+		p.RunDetails.Builder.ID = cfg.BuilderID
+	}
+	return statement
+}
+
+// reportProvenance records the uploaded provenance in outputs.
+func reportProvenance(outputs map[string]any, cfg ProvenanceConfig, signed bool, subjects int) {
+	outputs["provenance_name"] = cfg.Name
+	outputs["provenance_signed"] = signed
+	outputs["provenance_subjects"] = subjects
+}
+
+// dssePAE returns the DSSE pre-authentication encoding of a payload.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return fmt.Appendf(nil, "DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload)
+}
+
+// newDSSEEnvelope wraps statement in a DSSE envelope, signed when signer is
+// not nil.
+func newDSSEEnvelope(statement *inTotoStatement, signer crypto.Signer, keyID string) (*dsseEnvelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode provenance: %w", err)
+	}
+	envelope := &dsseEnvelope{
+		PayloadType: inTotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []dsseSignature{},
+	}
+	if signer == nil {
+		return envelope, nil
+	}
+
+	message := dssePAE(inTotoPayloadType, payload)
+	var opts crypto.SignerOpts = crypto.SHA256
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		// Ed25519 signs the message itself
+		opts = crypto.Hash(0)
+	} else {
+		digest := sha256.Sum256(message)
+		message = digest[:]
+	}
+	sig, err := signer.Sign(rand.Reader, message, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign provenance: %w", err)
+	}
+
+	if keyID == "" {
+		der, err := x509.MarshalPKIXPublicKey(signer.Public())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode public key: %w", err)
+		}
+		sum := sha256.Sum256(der)
+		keyID = hex.EncodeToString(sum[:])
+	}
+	envelope.Signatures = append(envelope.Signatures, dsseSignature{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)})
+	return envelope, nil
+}
+
+// uploadProvenance uploads the provenance of artifacts as one DSSE envelope
+// line.
+func uploadProvenance(ctx context.Context, client *github.Client, cfg ProvenanceConfig, signer crypto.Signer, run provenanceRun, releaseID int64, artifacts []plugin.Artifact) (*plugin.Artifact, error) {
+	envelope, err := newDSSEEnvelope(newProvenanceStatement(cfg, run, artifacts), signer, cfg.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	line, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode provenance envelope: %w", err)
+	}
+	content := append(line, '\n')
+
+	asset, err := uploadReleaseAssetFromReader(ctx, client, run.Owner, run.Repo, releaseID, cfg.Name, "application/jsonl", bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload asset %s: %w", cfg.Name, err)
+	}
+	loggerFromContext(ctx).Info("uploaded provenance", "name", cfg.Name, "subjects", len(artifacts), "signed", signer != nil)
+	releaseFilesFromContext(ctx).addContent(cfg.Name, "application/jsonl", content)
+
+	sum := sha256.Sum256(content)
+	return &plugin.Artifact{
+		Name:     cfg.Name,
+		Path:     asset.GetBrowserDownloadURL(),
+		Type:     "url",
+		Size:     int64(len(content)),
+		Checksum: hex.EncodeToString(sum[:]),
+	}, nil
+}
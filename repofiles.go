@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// RepoTarget is a repository that generated files, such as package manager
+// manifests, are committed to.
+type RepoTarget struct {
+	// Repository is the target repository as "owner/name".
+	Repository string `json:"repository,omitempty"`
+	// Branch is the branch to commit to, or the base of the pull request.
+	// It defaults to the repository's default branch.
+	Branch string `json:"branch,omitempty"`
+	// PullRequest commits to a new branch and opens a pull request instead
+	// of committing to Branch directly.
+	PullRequest bool `json:"pull_request"`
+	// Fork pushes the pull request branch to a fork of Repository in the
+	// account of the token's user, for repositories the token cannot push
+	// to such as microsoft/winget-pkgs. It requires PullRequest.
+	Fork bool `json:"fork"`
+	// Token overrides the plugin token for this repository.
+	Token string `json:"token,omitempty"`
+}
+
+// repoFile is a file to commit.
+type repoFile struct {
+	Path    string
+	Content string
+}
+
+// repoChange is a set of files committed together.
+type repoChange struct {
+	Files []repoFile
+	// Message is the commit message and pull request title.
+	Message string
+	// Branch is the head branch used when opening a pull request.
+	Branch string
+	// Body is the pull request description.
+	Body string
+	// Fork is the fork holding Branch as "owner/name"; empty when Branch
+	// lives in the target repository.
+	Fork string
+}
+
+// repoCommitResult describes a committed change.
+type repoCommitResult struct {
+	Branch         string
+	CommitURL      string
+	PullRequestURL string
+	// Changed is false when every file already had the rendered content.
+	Changed bool
+}
+
+// parseRepoTarget parses the repository target keys of a section.
+func parseRepoTarget(parser *helpers.ConfigParser) RepoTarget {
+	return RepoTarget{
+		Repository:  parser.GetString("repository", "", ""),
+		Branch:      parser.GetString("branch", "", ""),
+		PullRequest: parser.GetBool("pull_request", false),
+		Fork:        parser.GetBool("fork", false),
+		Token:       parser.GetString("token", "", ""),
+	}
+}
+
+// validateRepoTarget validates the repository target of a section.
+func validateRepoTarget(vb *helpers.ValidationBuilder, section string, target RepoTarget) {
+	if target.Repository == "" {
+		vb.AddError(section+".repository", "repository is required")
+		return
+	}
+	if _, _, ok := splitRepository(target.Repository); !ok {
+		vb.AddError(section+".repository", fmt.Sprintf("repository must be owner/name, got %q", target.Repository))
+	}
+	if target.Fork && !target.PullRequest {
+		vb.AddError(section+".fork", "fork requires pull_request")
+	}
+}
+
+// splitRepository splits "owner/name".
+func splitRepository(s string) (string, string, bool) {
+	owner, name, ok := strings.Cut(s, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	return owner, name, true
+}
+
+// isNotFound reports whether err is a 404 from the GitHub API.
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
+
+// targetClient returns the client for a repository target, honouring its
+// token override.
+func (p *GitHubPlugin) targetClient(ctx context.Context, cfg *Config, client *github.Client, target RepoTarget) (*github.Client, error) {
+	if target.Token == "" {
+		return client, nil
+	}
+	return p.clientFactory(ctx, cfg).newClient(target.Token, cfg.BaseURL, cfg.UploadURL)
+}
+
+// publishFiles commits change to target and records the result in resp
+// under outputs named <prefix>_changed, <prefix>_commit_url and
+// <prefix>_pull_request_url.
+func (p *GitHubPlugin) publishFiles(ctx context.Context, cfg *Config, client *github.Client, target RepoTarget, prefix string, resp *plugin.ExecuteResponse, change repoChange) error {
+	client, err := p.targetClient(ctx, cfg, client, target)
+	if err != nil {
+		return err
+	}
+	if target.Fork {
+		if change.Fork, err = p.forkRepository(ctx, client, target); err != nil {
+			return err
+		}
+	}
+	result, err := commitFiles(ctx, client, target, change)
+	if err != nil {
+		return err
+	}
+
+	resp.Outputs[prefix+"_changed"] = result.Changed
+	if result.CommitURL != "" {
+		resp.Outputs[prefix+"_commit_url"] = result.CommitURL
+	}
+	if result.PullRequestURL != "" {
+		resp.Outputs[prefix+"_pull_request_url"] = result.PullRequestURL
+	}
+	return nil
+}
+
+// commitFiles commits change to target through the Contents API, either
+// directly to the target branch or to change.Branch with a pull request,
+// pushing the branch to change.Fork when set.
+// Files whose content is unchanged are skipped, and an existing open pull
+// request for the branch is reused, so reruns are safe.
+func commitFiles(ctx context.Context, client *github.Client, target RepoTarget, change repoChange) (*repoCommitResult, error) {
+	owner, repo, ok := splitRepository(target.Repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository %q", target.Repository)
+	}
+	logger := loggerFromContext(ctx).With("target", target.Repository)
+
+	base := target.Branch
+	if base == "" {
+		r, _, err := client.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repository %s: %w", target.Repository, err)
+		}
+		base = r.GetDefaultBranch()
+	}
+
+	// Files are committed to the head repository: the fork, if any
+	headOwner, headRepo := owner, repo
+	if change.Fork != "" {
+		if headOwner, headRepo, ok = splitRepository(change.Fork); !ok {
+			return nil, fmt.Errorf("invalid fork %q", change.Fork)
+		}
+	}
+
+	branch := base
+	created := false
+	if target.PullRequest {
+		branch = change.Branch
+		var err error
+		if created, err = ensureBranch(ctx, client, headOwner, headRepo, branch, owner, repo, base); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &repoCommitResult{Branch: branch}
+	for _, f := range change.Files {
+		opts := &github.RepositoryContentFileOptions{
+			Message: github.String(change.Message),
+			Content: []byte(f.Content),
+			Branch:  github.String(branch),
+		}
+
+		existing, _, _, err := client.Repositories.GetContents(ctx, headOwner, headRepo, f.Path, &github.RepositoryContentGetOptions{Ref: branch})
+		switch {
+		case err == nil:
+			current, err := existing.GetContent()
+			if err == nil && current == f.Content {
+				logger.Debug("file unchanged", "path", f.Path)
+				continue
+			}
+			opts.SHA = existing.SHA
+		case !isNotFound(err):
+			return nil, fmt.Errorf("failed to read %s in %s: %w", f.Path, target.Repository, err)
+		}
+
+		var committed *github.RepositoryContentResponse
+		if opts.SHA != nil {
+			committed, _, err = client.Repositories.UpdateFile(ctx, headOwner, headRepo, f.Path, opts)
+		} else {
+			committed, _, err = client.Repositories.CreateFile(ctx, headOwner, headRepo, f.Path, opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to commit %s to %s: %w", f.Path, target.Repository, err)
+		}
+		result.Changed = true
+		result.CommitURL = committed.Commit.GetHTMLURL()
+		logger.Info("committed file", "path", f.Path, "branch", branch)
+	}
+
+	if !target.PullRequest {
+		return result, nil
+	}
+
+	// Look for the pull request even when nothing changed, so a rerun after
+	// a failed pull request creation still opens it
+	prs, _, err := client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+		State: "open",
+		Head:  headOwner + ":" + branch,
+		Base:  base,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests in %s: %w", target.Repository, err)
+	}
+	if len(prs) > 0 {
+		result.PullRequestURL = prs[0].GetHTMLURL()
+		return result, nil
+	}
+	if created && !result.Changed {
+		// A new branch without commits has nothing to propose
+		return result, nil
+	}
+
+	head := branch
+	if change.Fork != "" {
+		head = headOwner + ":" + branch
+	}
+	pr, _, err := client.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title: github.String(change.Message),
+		Head:  github.String(head),
+		Base:  github.String(base),
+		Body:  github.String(change.Body),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pull request in %s: %w", target.Repository, err)
+	}
+	result.PullRequestURL = pr.GetHTMLURL()
+	logger.Info("opened pull request", "url", result.PullRequestURL)
+	return result, nil
+}
+
+// ensureBranch creates branch in owner/repo from the head of base in
+// baseOwner/baseRepo unless it exists, and reports whether it was created.
+// The repositories differ when the branch lives in a fork.
+func ensureBranch(ctx context.Context, client *github.Client, owner, repo, branch, baseOwner, baseRepo, base string) (bool, error) {
+	if _, _, err := client.Git.GetRef(ctx, owner, repo, "heads/"+branch); err == nil {
+		return false, nil
+	} else if !isNotFound(err) {
+		return false, fmt.Errorf("failed to get branch %s: %w", branch, err)
+	}
+
+	baseRef, _, err := client.Git.GetRef(ctx, baseOwner, baseRepo, "heads/"+base)
+	if err != nil {
+		return false, fmt.Errorf("failed to get branch %s: %w", base, err)
+	}
+	_, _, err = client.Git.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: baseRef.Object.SHA},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	return true, nil
+}
+
+// Fork readiness polling: GitHub creates forks in the background.
+const (
+	forkReadyAttempts = 10
+	forkReadyDelay    = 3 * time.Second
+)
+
+// forkRepository forks the target repository into the account of the
+// client's user, or finds the existing fork, and waits until the fork's
+// branches can be read. It returns the fork as "owner/name".
+func (p *GitHubPlugin) forkRepository(ctx context.Context, client *github.Client, target RepoTarget) (string, error) {
+	owner, repo, ok := splitRepository(target.Repository)
+	if !ok {
+		return "", fmt.Errorf("invalid repository %q", target.Repository)
+	}
+
+	fork, _, err := client.Repositories.CreateFork(ctx, owner, repo, nil)
+	var accepted *github.AcceptedError
+	if err != nil && !errors.As(err, &accepted) {
+		return "", fmt.Errorf("failed to fork %s: %w", target.Repository, err)
+	}
+	forkOwner, forkRepo := fork.GetOwner().GetLogin(), fork.GetName()
+
+	clk := p.timeSource()
+	for attempt := 1; ; attempt++ {
+		_, _, err := client.Git.GetRef(ctx, forkOwner, forkRepo, "heads/"+fork.GetDefaultBranch())
+		if err == nil {
+			break
+		}
+		if attempt >= forkReadyAttempts {
+			return "", fmt.Errorf("fork %s/%s of %s is not ready: %w", forkOwner, forkRepo, target.Repository, err)
+		}
+		if err := clk.Sleep(ctx, forkReadyDelay); err != nil {
+			return "", err
+		}
+	}
+	loggerFromContext(ctx).Info("using fork", "target", target.Repository, "fork", forkOwner+"/"+forkRepo)
+	return forkOwner + "/" + forkRepo, nil
+}
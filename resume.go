@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// defaultStateFile is the state file written when no path is configured.
+const defaultStateFile = ".relicta-github-state.json"
+
+// stateFileVersion is the format version of the state file.
+const stateFileVersion = 1
+
+// ResumeConfig configures resuming interrupted releases.
+type ResumeConfig struct {
+	// Enabled turns on the state file.
+	Enabled bool `json:"enabled"`
+	// StateFile is the JSON file progress is persisted to.
+	StateFile string `json:"state_file,omitempty"`
+}
+
+// parseResumeConfig parses the resume section of the configuration.
+func parseResumeConfig(raw map[string]any) ResumeConfig {
+	parser := helpers.NewConfigParser(raw)
+	return ResumeConfig{
+		Enabled:   parser.GetBool("enabled", false),
+		StateFile: parser.GetString("state_file", "", defaultStateFile),
+	}
+}
+
+// validateResumeConfig validates the resume section of the configuration.
+func validateResumeConfig(vb *helpers.ValidationBuilder, raw map[string]any, promoteFrom string) {
+	cfg := parseResumeConfig(raw)
+	if !cfg.Enabled {
+		return
+	}
+	if promoteFrom != "" {
+		vb.AddError("resume.enabled", "resume cannot be combined with promote_from")
+	}
+	if info, err := os.Stat(cfg.StateFile); err == nil && info.IsDir() {
+		vb.AddError("resume.state_file", "state_file must be a file, not a directory")
+	}
+}
+
+// releaseState is the content of the state file: the progress of every
+// release, keyed by owner/repo@tag.
+type releaseState struct {
+	Version  int                      `json:"version"`
+	Releases map[string]*releaseEntry `json:"releases"`
+}
+
+// releaseEntry is the progress of a single release.
+type releaseEntry struct {
+	ReleaseID  int64        `json:"release_id"`
+	ReleaseURL string       `json:"release_url"`
+	UploadURL  string       `json:"upload_url"`
+	Assets     []stateAsset `json:"assets"`
+	UpdatedAt  string       `json:"updated_at"`
+}
+
+// stateAsset is an asset known to be fully uploaded.
+type stateAsset struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	URL    string `json:"url"`
+}
+
+// readReleaseState reads the state file at path. A missing file is an empty
+// state.
+func readReleaseState(path string) (*releaseState, error) {
+	state := &releaseState{Version: stateFileVersion, Releases: make(map[string]*releaseEntry)}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	if state.Version != stateFileVersion {
+		return nil, fmt.Errorf("unsupported state file version %d", state.Version)
+	}
+	if state.Releases == nil {
+		state.Releases = make(map[string]*releaseEntry)
+	}
+	return state, nil
+}
+
+// writeReleaseState replaces the state file at path atomically.
+func writeReleaseState(path string, state *releaseState) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(append(content, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// resumeRun tracks the progress of one release in the state file. All
+// methods are no-ops on a nil run, so callers need not check for resume.
+type resumeRun struct {
+	path    string
+	key     string
+	entry   *releaseEntry
+	now     func() time.Time
+	remote  map[string]*github.ReleaseAsset
+	skipped int
+}
+
+// openResume loads the progress of the release of tag from the state file.
+func openResume(path, owner, repo, tag string, now func() time.Time) (*resumeRun, error) {
+	state, err := readReleaseState(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	key := fmt.Sprintf("%s/%s@%s", owner, repo, tag)
+	entry := state.Releases[key]
+	if entry == nil {
+		entry = &releaseEntry{}
+	}
+	return &resumeRun{path: path, key: key, entry: entry, now: now}, nil
+}
+
+// release returns the release recorded by an earlier run, or nil when there
+// is none or it no longer exists, and loads the assets already uploaded to it.
+func (r *resumeRun) release(ctx context.Context, client *github.Client, owner, repo, tag string) (*github.RepositoryRelease, error) {
+	if r == nil || r.entry.ReleaseID == 0 {
+		return nil, nil
+	}
+	logger := loggerFromContext(ctx)
+
+	release, _, err := client.Repositories.GetRelease(ctx, owner, repo, r.entry.ReleaseID)
+	if isNotFound(err) || (err == nil && release.GetTagName() != tag) {
+		logger.Warn("release in state file no longer exists, creating it again", "release_id", r.entry.ReleaseID)
+		r.entry = &releaseEntry{}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %d: %w", r.entry.ReleaseID, err)
+	}
+
+	assets, err := listReleaseAssets(ctx, client, owner, repo, release.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assets of release %d: %w", release.GetID(), err)
+	}
+	r.remote = make(map[string]*github.ReleaseAsset, len(assets))
+	for _, a := range assets {
+		r.remote[a.GetName()] = a
+	}
+	logger.Info("resuming release", "release_id", release.GetID(), "assets", len(assets))
+	return release, nil
+}
+
+// started records the release so later runs reuse it.
+func (r *resumeRun) started(ctx context.Context, release *github.RepositoryRelease) {
+	if r == nil {
+		return
+	}
+	r.entry.ReleaseID = release.GetID()
+	r.entry.ReleaseURL = release.GetHTMLURL()
+	r.entry.UploadURL = release.GetUploadURL()
+	r.save(ctx)
+}
+
+// uploaded returns the artifact of assetPath when an earlier run already
+// uploaded it: an asset of the same name and size exists and, when the state
+// file knows its digest, the digest matches too. A stale asset of the same
+// name is deleted so it can be uploaded again.
+func (r *resumeRun) uploaded(ctx context.Context, client *github.Client, owner, repo, assetPath string) (*plugin.Artifact, bool) {
+	if r == nil {
+		return nil, false
+	}
+	name := filepath.Base(assetPath)
+	remote, ok := r.remote[name]
+	if !ok {
+		return nil, false
+	}
+	delete(r.remote, name)
+
+	if artifact, err := matchUploaded(r.entry, remote, assetPath); err == nil && artifact != nil {
+		r.skipped++
+		loggerFromContext(ctx).Info("asset already uploaded, skipping", "name", name)
+		return artifact, true
+	}
+	if _, err := client.Repositories.DeleteReleaseAsset(ctx, owner, repo, remote.GetID()); err != nil {
+		loggerFromContext(ctx).Warn("failed to delete stale asset", "name", name, "error", err)
+	}
+	r.forget(name)
+	return nil, false
+}
+
+// matchUploaded returns the artifact of assetPath if remote holds its content.
+func matchUploaded(entry *releaseEntry, remote *github.ReleaseAsset, assetPath string) (*plugin.Artifact, error) {
+	if remote.GetState() != "uploaded" {
+		return nil, nil
+	}
+	file, err := os.Open(assetPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	if err != nil || info.Size() != int64(remote.GetSize()) {
+		return nil, err
+	}
+	checksum, err := hashFile(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range entry.Assets {
+		if a.Name == remote.GetName() && a.SHA256 != checksum {
+			return nil, nil
+		}
+	}
+	return &plugin.Artifact{
+		Name:     remote.GetName(),
+		Path:     remote.GetBrowserDownloadURL(),
+		Type:     "url",
+		Size:     info.Size(),
+		Checksum: checksum,
+	}, nil
+}
+
+// discard deletes an asset of an earlier run that is regenerated on every
+// run, such as the provenance statement.
+func (r *resumeRun) discard(ctx context.Context, client *github.Client, owner, repo, name string) {
+	if r == nil {
+		return
+	}
+	remote, ok := r.remote[name]
+	if !ok {
+		return
+	}
+	delete(r.remote, name)
+	if _, err := client.Repositories.DeleteReleaseAsset(ctx, owner, repo, remote.GetID()); err != nil {
+		loggerFromContext(ctx).Warn("failed to delete stale asset", "name", name, "error", err)
+	}
+	r.forget(name)
+}
+
+// record records an uploaded asset and persists the progress.
+func (r *resumeRun) record(ctx context.Context, artifact plugin.Artifact) {
+	if r == nil {
+		return
+	}
+	r.forget(artifact.Name)
+	r.entry.Assets = append(r.entry.Assets, stateAsset{
+		Name:   artifact.Name,
+		Size:   artifact.Size,
+		SHA256: artifact.Checksum,
+		URL:    artifact.Path,
+	})
+	r.save(ctx)
+}
+
+// forget drops name from the recorded assets.
+func (r *resumeRun) forget(name string) {
+	assets := r.entry.Assets[:0]
+	for _, a := range r.entry.Assets {
+		if a.Name != name {
+			assets = append(assets, a)
+		}
+	}
+	r.entry.Assets = assets
+}
+
+// save writes the progress to the state file, keeping the entries of other
+// releases. Failures are logged; they only cost the ability to resume.
+func (r *resumeRun) save(ctx context.Context) {
+	r.entry.UpdatedAt = r.now().UTC().Format(time.RFC3339)
+	state, err := readReleaseState(r.path)
+	if err == nil {
+		state.Releases[r.key] = r.entry
+		err = writeReleaseState(r.path, state)
+	}
+	if err != nil {
+		loggerFromContext(ctx).Warn("failed to write state file", "path", r.path, "error", err)
+	}
+}
+
+// report records the resumed release in outputs.
+func (r *resumeRun) report(outputs map[string]any, resumed bool) {
+	if r == nil {
+		return
+	}
+	outputs["resumed"] = resumed
+	outputs["assets_skipped"] = r.skipped
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+
+	"github.com/relicta-tech/plugin-github/internal/ghfake"
+)
+
+// runResumable runs PostPublish with resume enabled and the assets in dir.
+func runResumable(t *testing.T, fake *ghfake.Server, dir, stateFile string) *plugin.ExecuteResponse {
+	t.Helper()
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets": []any{dir + "/*"},
+			"resume": map[string]any{"enabled": true, "state_file": stateFile},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+// TestExecuteResume tests that a rerun reuses the release and uploads only the missing asset.
+func TestExecuteResume(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"a.tar.gz": "alpha", "b.tar.gz": "bravo"})
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	fake.InjectFault(ghfake.Fault{Method: "POST", Path: "/assets", Status: http.StatusBadRequest, Times: 1})
+	if resp := runResumable(t, fake, dir, stateFile); len(resp.Artifacts) != 1 {
+		t.Fatalf("expected one uploaded asset, got %+v", resp)
+	}
+
+	resp := runResumable(t, fake, dir, stateFile)
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if resp.Outputs["resumed"] != true || resp.Outputs["assets_skipped"] != 1 || len(resp.Artifacts) != 2 {
+		t.Errorf("unexpected outputs %v, artifacts %v", resp.Outputs, resp.Artifacts)
+	}
+	if n := fake.CountRequests("POST", "/repos/test-owner/test-repo/releases"); n != 4 {
+		t.Errorf("expected one release and three uploads, got %d requests", n)
+	}
+	release := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0")
+	if assets := fake.Assets(release.GetID()); len(assets) != 2 {
+		t.Errorf("expected two assets, got %v", assets)
+	}
+
+	state, err := readReleaseState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := state.Releases["test-owner/test-repo@v1.2.0"]
+	if entry == nil || entry.ReleaseID != release.GetID() || len(entry.Assets) != 2 {
+		t.Fatalf("unexpected state %+v", entry)
+	}
+	for _, a := range entry.Assets {
+		if want := map[string]string{"a.tar.gz": "alpha", "b.tar.gz": "bravo"}[a.Name]; a.SHA256 != sha256Hex(want) {
+			t.Errorf("unexpected state of %s: %+v", a.Name, a)
+		}
+	}
+}
+
+// TestExecuteResumeChangedAsset tests that an asset whose content changed is replaced.
+func TestExecuteResumeChangedAsset(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"a.tar.gz": "alpha", "b.tar.gz": "bravo"})
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	if resp := runResumable(t, fake, dir, stateFile); !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.tar.gz"), []byte("BRAVO"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := runResumable(t, fake, dir, stateFile)
+	if !resp.Success || resp.Outputs["assets_skipped"] != 1 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	release := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0")
+	assets := fake.Assets(release.GetID())
+	if len(assets) != 2 || assets[1].GetName() != "b.tar.gz" {
+		t.Fatalf("unexpected assets %v", assets)
+	}
+	if content, _ := fake.AssetContent(assets[1].GetID()); string(content) != "BRAVO" {
+		t.Errorf("expected the changed asset to be uploaded again, got %q", content)
+	}
+}
+
+// TestExecuteResumeDeletedRelease tests that a release deleted since the last run is created again.
+func TestExecuteResumeDeletedRelease(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"a.tar.gz": "alpha"})
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	err := writeReleaseState(stateFile, &releaseState{
+		Version:  stateFileVersion,
+		Releases: map[string]*releaseEntry{"test-owner/test-repo@v1.2.0": {ReleaseID: 999}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := runResumable(t, fake, dir, stateFile)
+	if !resp.Success || resp.Outputs["resumed"] != false || len(fake.Releases("test-owner", "test-repo")) != 1 {
+		t.Errorf("expected a new release, got %+v", resp)
+	}
+}
+
+// TestValidateResume tests validation of the resume section.
+func TestValidateResume(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token":        "ghp_test",
+		"promote_from": "v1.2.0-rc.1",
+		"resume":       map[string]any{"enabled": true, "state_file": t.TempDir()},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 2 {
+		t.Errorf("expected promote_from and state_file errors, got %+v", resp.Errors)
+	}
+}
@@ -0,0 +1,499 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+)
+
+// SBOM formats.
+const (
+	SBOMFormatCycloneDX = "cyclonedx"
+	SBOMFormatSPDX      = "spdx"
+)
+
+// SBOM sources.
+const (
+	// SBOMSourceGoMod reads the requirements of go.mod and go.sum.
+	SBOMSourceGoMod = "gomod"
+	// SBOMSourceBinaries reads the build information embedded in Go
+	// binaries among the assets, as `go version -m` does.
+	SBOMSourceBinaries = "binaries"
+)
+
+// sbomTool names this plugin as the SBOM generator.
+const sbomTool = "relicta-plugin-github"
+
+// defaultChecksumFiles match the checksum manifests produced by common
+// release tooling.
+var defaultChecksumFiles = []string{"*checksums.txt", "SHA256SUMS", "*.sha256sums"}
+
+// SBOMConfig configures generating an SBOM uploaded with the assets.
+type SBOMConfig struct {
+	// Enabled turns on SBOM generation.
+	Enabled bool `json:"enabled"`
+	// Format is "cyclonedx" (default) or "spdx", both JSON.
+	Format string `json:"format,omitempty"`
+	// Source is "gomod" (default) or "binaries".
+	Source string `json:"source,omitempty"`
+	// GoMod is the path of go.mod; go.sum is read next to it.
+	GoMod string `json:"go_mod,omitempty"`
+	// Name is the asset name; it defaults to sbom.cdx.json or sbom.spdx.json.
+	Name string `json:"name,omitempty"`
+	// ChecksumFiles are glob patterns of checksum manifest assets that get
+	// a line for the SBOM.
+	ChecksumFiles []string `json:"checksum_files,omitempty"`
+}
+
+// parseSBOMConfig parses the sbom section of the configuration.
+func parseSBOMConfig(raw map[string]any) SBOMConfig {
+	parser := helpers.NewConfigParser(raw)
+	format := parser.GetString("format", "", SBOMFormatCycloneDX)
+	name := "sbom.cdx.json"
+	if format == SBOMFormatSPDX {
+		name = "sbom.spdx.json"
+	}
+	return SBOMConfig{
+		Enabled:       parser.GetBool("enabled", false),
+		Format:        format,
+		Source:        parser.GetString("source", "", SBOMSourceGoMod),
+		GoMod:         parser.GetString("go_mod", "", "go.mod"),
+		Name:          parser.GetString("name", "", name),
+		ChecksumFiles: parser.GetStringSlice("checksum_files", defaultChecksumFiles),
+	}
+}
+
+// validateSBOMConfig validates the sbom section of the configuration.
+func validateSBOMConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	vb.ValidateOneOf(raw, "format", []string{SBOMFormatCycloneDX, SBOMFormatSPDX})
+	vb.ValidateOneOf(raw, "source", []string{SBOMSourceGoMod, SBOMSourceBinaries})
+
+	cfg := parseSBOMConfig(raw)
+	if strings.ContainsAny(cfg.Name, `/\`) {
+		vb.AddError("sbom.name", "name must be a file name")
+	}
+	for _, pattern := range cfg.ChecksumFiles {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			vb.AddError("sbom.checksum_files", fmt.Sprintf("invalid pattern %q: %v", pattern, err))
+		}
+	}
+}
+
+// goModule is a module in the SBOM.
+type goModule struct {
+	Path     string
+	Version  string
+	Sum      string
+	Indirect bool
+}
+
+// purl returns the package URL of the module.
+func (m goModule) purl() string {
+	if m.Version == "" {
+		return "pkg:golang/" + m.Path
+	}
+	return "pkg:golang/" + m.Path + "@" + m.Version
+}
+
+// sbomResult is a generated SBOM and the asset list that uploads it.
+type sbomResult struct {
+	Name       string
+	Components int
+	// Assets are the asset paths to upload: the original assets with
+	// checksum manifests replaced by copies that list the SBOM, followed by
+	// the SBOM itself.
+	Assets []string
+}
+
+// generateSBOM writes the SBOM for the release tag, created at created, into
+// dir and returns the asset paths to upload in place of assets.
+func generateSBOM(ctx context.Context, cfg SBOMConfig, dir, tag string, assets []string, created time.Time) (*sbomResult, error) {
+	var root goModule
+	var modules []goModule
+	var err error
+	if cfg.Source == SBOMSourceBinaries {
+		root, modules, err = readBinaryModules(assets)
+	} else {
+		root, modules, err = readGoMod(cfg.GoMod)
+	}
+	if err != nil {
+		return nil, err
+	}
+	root.Version = tag
+
+	var doc any
+	if cfg.Format == SBOMFormatSPDX {
+		doc, err = newSPDXDocument(root, modules, created)
+	} else {
+		doc, err = newCycloneDXDocument(root, modules, created)
+	}
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SBOM: %w", err)
+	}
+	path := filepath.Join(dir, cfg.Name)
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write SBOM: %w", err)
+	}
+	sum := sha256.Sum256(append(content, '\n'))
+
+	result := &sbomResult{Name: cfg.Name, Components: len(modules)}
+	for _, asset := range assets {
+		if !matchesAny(filepath.Base(asset), cfg.ChecksumFiles) {
+			result.Assets = append(result.Assets, asset)
+			continue
+		}
+		manifest, err := appendChecksum(asset, dir, cfg.Name, hex.EncodeToString(sum[:]))
+		if err != nil {
+			return nil, err
+		}
+		loggerFromContext(ctx).Debug("added SBOM to checksum manifest", "manifest", asset)
+		result.Assets = append(result.Assets, manifest)
+	}
+	result.Assets = append(result.Assets, path)
+
+	loggerFromContext(ctx).Info("generated SBOM", "name", cfg.Name, "format", cfg.Format, "components", len(modules))
+	return result, nil
+}
+
+// reportSBOM records the generated SBOM in outputs.
+func reportSBOM(outputs map[string]any, cfg SBOMConfig, sbom *sbomResult) {
+	outputs["sbom_name"] = sbom.Name
+	outputs["sbom_format"] = cfg.Format
+	outputs["sbom_components"] = sbom.Components
+}
+
+// matchesAny reports whether name matches one of the glob patterns.
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// appendChecksum copies the checksum manifest at path into dir with a line
+// for name added, in the "<sha256>  <name>" format of sha256sum.
+func appendChecksum(path, dir, name, checksum string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read checksum manifest %s: %w", path, err)
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		content = append(content, '\n')
+	}
+	content = append(content, fmt.Sprintf("%s  %s\n", checksum, name)...)
+
+	manifest := filepath.Join(dir, filepath.Base(path))
+	if err := os.WriteFile(manifest, content, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write checksum manifest %s: %w", manifest, err)
+	}
+	return manifest, nil
+}
+
+// readGoMod returns the main module and the requirements of a go.mod file,
+// with their hashes from the go.sum next to it when present.
+func readGoMod(path string) (goModule, []goModule, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return goModule{}, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var root goModule
+	var modules []goModule
+	inRequire := false
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		line, comment, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case fields[0] == "module" && len(fields) == 2:
+			root.Path = strings.Trim(fields[1], `"`)
+			continue
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		case !inRequire:
+			continue
+		}
+		if len(fields) == 2 {
+			modules = append(modules, goModule{
+				Path:     strings.Trim(fields[0], `"`),
+				Version:  fields[1],
+				Indirect: strings.TrimSpace(comment) == "indirect",
+			})
+		}
+	}
+	if root.Path == "" {
+		return goModule{}, nil, fmt.Errorf("no module directive in %s", path)
+	}
+
+	sums, err := readGoSum(filepath.Join(filepath.Dir(path), "go.sum"))
+	if err != nil {
+		return goModule{}, nil, err
+	}
+	for i, m := range modules {
+		modules[i].Sum = sums[m.Path+"@"+m.Version]
+	}
+	return root, sortModules(modules), nil
+}
+
+// readGoSum returns the module hashes of a go.sum file keyed by
+// "path@version"; a missing file yields no hashes.
+func readGoSum(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	sums := make(map[string]string)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && !strings.HasSuffix(fields[1], "/go.mod") {
+			sums[fields[0]+"@"+fields[1]] = fields[2]
+		}
+	}
+	return sums, nil
+}
+
+// readBinaryModules returns the main module and dependencies recorded in
+// the Go binaries among assets. Assets that are not Go binaries are skipped.
+func readBinaryModules(assets []string) (goModule, []goModule, error) {
+	var root goModule
+	seen := make(map[string]bool)
+	var modules []goModule
+	for _, asset := range assets {
+		info, err := buildinfo.ReadFile(asset)
+		if err != nil {
+			continue
+		}
+		if root.Path == "" {
+			root.Path = info.Main.Path
+		}
+		for _, dep := range info.Deps {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			key := dep.Path + "@" + dep.Version
+			if !seen[key] {
+				seen[key] = true
+				modules = append(modules, goModule{Path: dep.Path, Version: dep.Version, Sum: dep.Sum})
+			}
+		}
+	}
+	if root.Path == "" {
+		return goModule{}, nil, fmt.Errorf("no Go binaries with build information among the assets")
+	}
+	return root, sortModules(modules), nil
+}
+
+// sortModules sorts modules by path and version.
+func sortModules(modules []goModule) []goModule {
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i].Path != modules[j].Path {
+			return modules[i].Path < modules[j].Path
+		}
+		return modules[i].Version < modules[j].Version
+	})
+	return modules
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate UUID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// cdxComponent is a CycloneDX component.
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	Scope      string        `json:"scope,omitempty"`
+	PURL       string        `json:"purl"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+// cdxProperty is a CycloneDX name-value property.
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cdxDependency lists the components a component depends on.
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// cdxDocument is a CycloneDX 1.5 JSON BOM.
+type cdxDocument struct {
+	BOMFormat    string `json:"bomFormat"`
+	SpecVersion  string `json:"specVersion"`
+	SerialNumber string `json:"serialNumber"`
+	Version      int    `json:"version"`
+	Metadata     struct {
+		Timestamp string `json:"timestamp"`
+		Tools     struct {
+			Components []cdxComponent `json:"components"`
+		} `json:"tools"`
+		Component cdxComponent `json:"component"`
+	} `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+// newCycloneDXDocument returns a CycloneDX BOM of root and its modules.
+// Direct requirements are dependencies of root; indirect ones are marked
+// optional as go.mod does not record which module needs them.
+func newCycloneDXDocument(root goModule, modules []goModule, created time.Time) (*cdxDocument, error) {
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &cdxDocument{BOMFormat: "CycloneDX", SpecVersion: "1.5", SerialNumber: "urn:uuid:" + id, Version: 1}
+	doc.Metadata.Timestamp = created.UTC().Format(time.RFC3339)
+	doc.Metadata.Tools.Components = []cdxComponent{{Type: "application", BOMRef: sbomTool, Name: sbomTool, PURL: "pkg:golang/github.com/relicta-tech/plugin-github"}}
+	doc.Metadata.Component = cdxComponent{Type: "application", BOMRef: root.purl(), Name: root.Path, Version: root.Version, PURL: root.purl()}
+
+	rootDeps := cdxDependency{Ref: root.purl()}
+	doc.Components = make([]cdxComponent, 0, len(modules))
+	for _, m := range modules {
+		c := cdxComponent{Type: "library", BOMRef: m.purl(), Name: m.Path, Version: m.Version, Scope: "required", PURL: m.purl()}
+		if m.Indirect {
+			c.Scope = "optional"
+		} else {
+			rootDeps.DependsOn = append(rootDeps.DependsOn, m.purl())
+		}
+		if m.Sum != "" {
+			c.Properties = []cdxProperty{{Name: "golang:sum", Value: m.Sum}}
+		}
+		doc.Components = append(doc.Components, c)
+	}
+	doc.Dependencies = []cdxDependency{rootDeps}
+	return doc, nil
+}
+
+// spdxPackage is an SPDX package.
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Comment          string            `json:"comment,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+}
+
+// spdxExternalRef is an SPDX package reference such as a purl.
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// spdxRelationship relates two SPDX elements.
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxDocument is an SPDX 2.3 JSON document.
+type spdxDocument struct {
+	SPDXVersion       string `json:"spdxVersion"`
+	DataLicense       string `json:"dataLicense"`
+	SPDXID            string `json:"SPDXID"`
+	Name              string `json:"name"`
+	DocumentNamespace string `json:"documentNamespace"`
+	CreationInfo      struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+	} `json:"creationInfo"`
+	Packages      []spdxPackage      `json:"packages"`
+	Relationships []spdxRelationship `json:"relationships"`
+}
+
+// newSPDXPackage returns the SPDX package of a module.
+func newSPDXPackage(id string, m goModule) spdxPackage {
+	p := spdxPackage{
+		Name:             m.Path,
+		SPDXID:           id,
+		VersionInfo:      m.Version,
+		DownloadLocation: "NOASSERTION",
+		LicenseConcluded: "NOASSERTION",
+		LicenseDeclared:  "NOASSERTION",
+		CopyrightText:    "NOASSERTION",
+		ExternalRefs:     []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: m.purl()}},
+	}
+	if m.Sum != "" {
+		p.Comment = "go.sum " + m.Sum
+	}
+	return p
+}
+
+// newSPDXDocument returns an SPDX document describing root, which depends
+// on its modules.
+func newSPDXDocument(root goModule, modules []goModule, created time.Time) (*spdxDocument, error) {
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	name := root.Path + "@" + root.Version
+	doc := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: "https://spdx.org/spdxdocs/" + strings.ReplaceAll(name, "/", "-") + "-" + id,
+	}
+	doc.CreationInfo.Created = created.UTC().Format(time.RFC3339)
+	doc.CreationInfo.Creators = []string{"Tool: " + sbomTool}
+
+	const rootID = "SPDXRef-Package-0"
+	doc.Packages = append(doc.Packages, newSPDXPackage(rootID, root))
+	doc.Relationships = append(doc.Relationships, spdxRelationship{SPDXElementID: doc.SPDXID, RelationshipType: "DESCRIBES", RelatedSPDXElement: rootID})
+	for i, m := range modules {
+		pkgID := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		doc.Packages = append(doc.Packages, newSPDXPackage(pkgID, m))
+		doc.Relationships = append(doc.Relationships, spdxRelationship{SPDXElementID: rootID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: pkgID})
+	}
+	return doc, nil
+}
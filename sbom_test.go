@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+const testGoMod = `module example.com/app
+
+go 1.24
+
+require example.com/direct v1.0.0
+
+require (
+	example.com/lib v0.2.0 // indirect
+	"example.com/quoted" v1.1.0
+)
+
+replace example.com/lib => ../lib
+`
+
+const testGoSum = `example.com/direct v1.0.0 h1:direct=
+example.com/direct v1.0.0/go.mod h1:directmod=
+example.com/lib v0.2.0 h1:lib=
+`
+
+// writeGoModule writes go.mod and go.sum into a temporary directory and
+// returns the go.mod path.
+func writeGoModule(t *testing.T) string {
+	t.Helper()
+	dir := writeAssets(t, map[string]string{"go.mod": testGoMod, "go.sum": testGoSum})
+	return filepath.Join(dir, "go.mod")
+}
+
+// TestReadGoMod tests reading requirements and hashes from go.mod and go.sum.
+func TestReadGoMod(t *testing.T) {
+	root, modules, err := readGoMod(writeGoModule(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []goModule{
+		{Path: "example.com/direct", Version: "v1.0.0", Sum: "h1:direct="},
+		{Path: "example.com/lib", Version: "v0.2.0", Sum: "h1:lib=", Indirect: true},
+		{Path: "example.com/quoted", Version: "v1.1.0"},
+	}
+	if root.Path != "example.com/app" || len(modules) != len(want) {
+		t.Fatalf("unexpected modules %v %v", root, modules)
+	}
+	for i := range want {
+		if modules[i] != want[i] {
+			t.Errorf("module %d: got %+v, want %+v", i, modules[i], want[i])
+		}
+	}
+}
+
+// TestGenerateSBOM tests both formats and the checksum manifest line.
+func TestGenerateSBOM(t *testing.T) {
+	goMod := writeGoModule(t)
+	assets := writeAssets(t, map[string]string{"app.tar.gz": "binary", "checksums.txt": "abc  app.tar.gz"})
+
+	for _, format := range []string{SBOMFormatCycloneDX, SBOMFormatSPDX} {
+		t.Run(format, func(t *testing.T) {
+			cfg := parseSBOMConfig(map[string]any{"enabled": true, "format": format, "go_mod": goMod})
+			result, err := generateSBOM(context.Background(), cfg, t.TempDir(), "v1.2.0", []string{assets + "/app.tar.gz", assets + "/checksums.txt"}, time.Now())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Assets) != 3 || result.Assets[0] != assets+"/app.tar.gz" || filepath.Base(result.Assets[2]) != cfg.Name {
+				t.Fatalf("unexpected assets %v", result.Assets)
+			}
+
+			content, err := os.ReadFile(result.Assets[2])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc map[string]any
+			if err := json.Unmarshal(content, &doc); err != nil {
+				t.Fatalf("invalid SBOM JSON: %v", err)
+			}
+			if format == SBOMFormatSPDX {
+				packages, _ := doc["packages"].([]any)
+				if doc["spdxVersion"] != "SPDX-2.3" || len(packages) != 4 {
+					t.Errorf("unexpected SPDX document %s", content)
+				}
+			} else {
+				components, _ := doc["components"].([]any)
+				root, _ := doc["metadata"].(map[string]any)["component"].(map[string]any)
+				if doc["bomFormat"] != "CycloneDX" || len(components) != 3 || root["purl"] != "pkg:golang/example.com/app@v1.2.0" {
+					t.Errorf("unexpected CycloneDX document %s", content)
+				}
+			}
+
+			manifest, err := os.ReadFile(result.Assets[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			checksum := sha256File(t, result.Assets[2])
+			if want := "abc  app.tar.gz\n" + checksum + "  " + cfg.Name + "\n"; string(manifest) != want {
+				t.Errorf("got manifest %q, want %q", manifest, want)
+			}
+			if original, _ := os.ReadFile(assets + "/checksums.txt"); string(original) != "abc  app.tar.gz" {
+				t.Error("expected the original manifest to be unchanged")
+			}
+		})
+	}
+}
+
+// sha256File returns the hex SHA-256 of the file at path.
+func sha256File(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	checksum, err := hashFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return checksum
+}
+
+// TestGenerateSBOMFromBinaries tests reading modules from the build information of a Go binary.
+func TestGenerateSBOMFromBinaries(t *testing.T) {
+	binary, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notBinary := writeAssets(t, map[string]string{"README.md": "docs"}) + "/README.md"
+
+	cfg := parseSBOMConfig(map[string]any{"enabled": true, "source": SBOMSourceBinaries})
+	result, err := generateSBOM(context.Background(), cfg, t.TempDir(), "v1.2.0", []string{notBinary, binary}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, _ := os.ReadFile(result.Assets[len(result.Assets)-1])
+	if result.Components == 0 || !strings.Contains(string(content), "pkg:golang/github.com/google/go-github/v60@") {
+		t.Errorf("expected go-github among %d components", result.Components)
+	}
+
+	if _, err := generateSBOM(context.Background(), cfg, t.TempDir(), "v1.2.0", []string{notBinary}, time.Now()); err == nil {
+		t.Error("expected an error without Go binaries")
+	}
+}
+
+// TestExecuteSBOM tests uploading the SBOM and the updated checksum manifest.
+func TestExecuteSBOM(t *testing.T) {
+	fake := newFakeGitHub(t)
+	dir := writeAssets(t, map[string]string{"app.tar.gz": "binary", "checksums.txt": "abc  app.tar.gz\n"})
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"assets": []any{dir + "/*"},
+			"sbom":   map[string]any{"enabled": true, "go_mod": writeGoModule(t)},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if resp.Outputs["sbom_name"] != "sbom.cdx.json" || resp.Outputs["sbom_format"] != SBOMFormatCycloneDX || resp.Outputs["sbom_components"] != 3 {
+		t.Errorf("unexpected SBOM outputs %v", resp.Outputs)
+	}
+
+	artifacts := make(map[string]plugin.Artifact)
+	for _, a := range resp.Artifacts {
+		artifacts[a.Name] = a
+	}
+	sbom, ok := artifacts["sbom.cdx.json"]
+	if !ok || len(artifacts) != 3 {
+		t.Fatalf("unexpected artifacts %v", resp.Artifacts)
+	}
+
+	release := releaseByTag(t, fake, "test-owner", "test-repo", "v1.2.0")
+	for _, asset := range fake.Assets(release.GetID()) {
+		if asset.GetName() != "checksums.txt" {
+			continue
+		}
+		content, _ := fake.AssetContent(asset.GetID())
+		if want := "abc  app.tar.gz\n" + sbom.Checksum + "  sbom.cdx.json\n"; string(content) != want {
+			t.Errorf("got manifest %q, want %q", content, want)
+		}
+	}
+}
+
+// TestExecuteSBOMFailure tests that a failed SBOM leaves no release behind.
+func TestExecuteSBOMFailure(t *testing.T) {
+	fake := newFakeGitHub(t)
+
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  fakeConfig(fake, map[string]any{"sbom": map[string]any{"enabled": true, "go_mod": t.TempDir() + "/go.mod"}}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || !strings.HasPrefix(resp.Error, "failed to generate SBOM:") {
+		t.Errorf("expected SBOM failure, got %+v", resp)
+	}
+	if releases := fake.Releases("test-owner", "test-repo"); len(releases) != 0 {
+		t.Errorf("expected no release, got %d", len(releases))
+	}
+}
+
+// TestValidateSBOM tests validation of the sbom section.
+func TestValidateSBOM(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token": "ghp_test",
+		"sbom":  map[string]any{"format": "swid", "source": "npm", "name": "dist/sbom.json", "checksum_files": []any{"["}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 4 {
+		t.Errorf("expected format, source, name and pattern errors, got %+v", resp.Errors)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// templateData is the data available to configurable templates, e.g.
+// "{{.Tag}} is out: {{.ReleaseURL}}".
+type templateData struct {
+	Version         string
+	PreviousVersion string
+	Tag             string
+	Name            string
+	Owner           string
+	Repo            string
+	Branch          string
+	CommitSHA       string
+	ReleaseURL      string
+	ReleaseNotes    string
+	Changelog       string
+}
+
+// newTemplateData returns template data for a release.
+func newTemplateData(owner, repo string, releaseCtx plugin.ReleaseContext, releaseURL string) templateData {
+	notes := releaseCtx.ReleaseNotes
+	if notes == "" {
+		notes = releaseCtx.Changelog
+	}
+	return templateData{
+		Version:         releaseCtx.Version,
+		PreviousVersion: releaseCtx.PreviousVersion,
+		Tag:             releaseCtx.TagName,
+		Name:            fmt.Sprintf("Release %s", releaseCtx.Version),
+		Owner:           owner,
+		Repo:            repo,
+		Branch:          releaseCtx.Branch,
+		CommitSHA:       releaseCtx.CommitSHA,
+		ReleaseURL:      releaseURL,
+		ReleaseNotes:    notes,
+		Changelog:       releaseCtx.Changelog,
+	}
+}
+
+// templateFuncs are the functions available to configurable templates.
+var templateFuncs = template.FuncMap{
+	"rubyString": rubyString,
+}
+
+// parseTemplate parses text as a template that fails on unknown fields.
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// renderTemplate renders text with data, usually a templateData or a struct
+// embedding it.
+func renderTemplate(name, text string, data any) (string, error) {
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// validateTemplate reports a template that does not parse or references
+// fields that do not exist in data.
+func validateTemplate(vb *helpers.ValidationBuilder, field, text string, data any) {
+	if text == "" {
+		return
+	}
+	if _, err := renderTemplate(field, text, data); err != nil {
+		vb.AddError(field, fmt.Sprintf("invalid template: %v", err))
+	}
+}
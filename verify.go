@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/go-github/v60/github"
+	"golang.org/x/oauth2"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// assetStateUploaded is the state GitHub reports once an asset upload has completed.
+const assetStateUploaded = "uploaded"
+
+// verifyAssets checks that every uploaded artifact is present on the release
+// with the expected size and state. When checksums is true, each asset is
+// downloaded back and its SHA-256 compared with the digest of the local file.
+// One error is returned per problem found.
+func (p *GitHubPlugin) verifyAssets(ctx context.Context, client *github.Client, owner, repo string, releaseID int64, artifacts []plugin.Artifact, checksums bool) []error {
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	remote, err := listReleaseAssets(ctx, client, owner, repo, releaseID)
+	if err != nil {
+		return []error{fmt.Errorf("failed to list release assets for verification: %w", err)}
+	}
+
+	byName := make(map[string]*github.ReleaseAsset, len(remote))
+	for _, asset := range remote {
+		byName[asset.GetName()] = asset
+	}
+
+	var errs []error
+	for _, artifact := range artifacts {
+		asset, ok := byName[artifact.Name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("asset %s missing from release", artifact.Name))
+			continue
+		}
+
+		if state := asset.GetState(); state != assetStateUploaded {
+			errs = append(errs, fmt.Errorf("asset %s is in state %q, expected %q", artifact.Name, state, assetStateUploaded))
+			continue
+		}
+
+		if size := int64(asset.GetSize()); size != artifact.Size {
+			errs = append(errs, fmt.Errorf("asset %s size mismatch: local %d bytes, remote %d bytes", artifact.Name, artifact.Size, size))
+			continue
+		}
+
+		if !checksums {
+			continue
+		}
+
+		digest, err := downloadAssetDigest(ctx, client, owner, repo, asset.GetID())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to download asset %s for verification: %w", artifact.Name, err))
+			continue
+		}
+		if digest != artifact.Checksum {
+			errs = append(errs, fmt.Errorf("asset %s checksum mismatch: local %s, remote %s", artifact.Name, artifact.Checksum, digest))
+		}
+	}
+
+	logger := loggerFromContext(ctx)
+	for _, err := range errs {
+		logger.Warn("asset verification failed", "error", err)
+	}
+	logger.Info("verified release assets", "assets", len(artifacts), "failures", len(errs), "checksums", checksums)
+
+	return errs
+}
+
+// listReleaseAssets returns all assets of a release, following pagination.
+func listReleaseAssets(ctx context.Context, client *github.Client, owner, repo string, releaseID int64) ([]*github.ReleaseAsset, error) {
+	var all []*github.ReleaseAsset
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		assets, resp, err := client.Repositories.ListReleaseAssets(ctx, owner, repo, releaseID, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, assets...)
+		if resp == nil || resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// downloadAssetDigest streams a release asset and returns its hex-encoded SHA-256.
+func downloadAssetDigest(ctx context.Context, client *github.Client, owner, repo string, assetID int64) (string, error) {
+	rc, _, err := client.Repositories.DownloadReleaseAsset(ctx, owner, repo, assetID, redirectClient(client))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = rc.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// redirectClient returns the client that follows asset download redirects to
+// the storage backend. It shares the transport of client, with its timeouts,
+// retries and logging, but drops the credentials so the token is never sent
+// outside the GitHub API.
+func redirectClient(client *github.Client) *http.Client {
+	if t, ok := client.Client().Transport.(*oauth2.Transport); ok && t.Base != nil {
+		return &http.Client{Transport: t.Base}
+	}
+	return http.DefaultClient
+}
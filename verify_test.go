@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// newVerifyServer serves a release asset listing and the asset contents.
+func newVerifyServer(t *testing.T, assets []map[string]any, contents map[string]string) *github.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/releases/123/assets") {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(assets)
+			return
+		}
+		if r.Method == "GET" && strings.Contains(r.URL.Path, "/releases/assets/") {
+			id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			body, ok := contents[id]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	serverURL, _ := url.Parse(server.URL + "/")
+	client.BaseURL = serverURL
+	client.UploadURL = serverURL
+	return client
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// TestVerifyAssets tests post-upload verification of release assets.
+func TestVerifyAssets(t *testing.T) {
+	content := "binary content"
+
+	tests := []struct {
+		name      string
+		assets    []map[string]any
+		contents  map[string]string
+		checksums bool
+		expectErr string
+	}{
+		{
+			name: "all assets match",
+			assets: []map[string]any{
+				{"id": 1, "name": "app.tar.gz", "size": len(content), "state": "uploaded"},
+			},
+			contents:  map[string]string{"1": content},
+			checksums: true,
+		},
+		{
+			name:      "missing asset",
+			assets:    []map[string]any{},
+			expectErr: "missing from release",
+		},
+		{
+			name: "incomplete upload",
+			assets: []map[string]any{
+				{"id": 1, "name": "app.tar.gz", "size": len(content), "state": "starter"},
+			},
+			expectErr: `in state "starter"`,
+		},
+		{
+			name: "size mismatch",
+			assets: []map[string]any{
+				{"id": 1, "name": "app.tar.gz", "size": 3, "state": "uploaded"},
+			},
+			expectErr: "size mismatch",
+		},
+		{
+			name: "checksum mismatch",
+			assets: []map[string]any{
+				{"id": 1, "name": "app.tar.gz", "size": len(content), "state": "uploaded"},
+			},
+			contents:  map[string]string{"1": "binary CONTENT"},
+			checksums: true,
+			expectErr: "checksum mismatch",
+		},
+		{
+			name: "checksum not compared when disabled",
+			assets: []map[string]any{
+				{"id": 1, "name": "app.tar.gz", "size": len(content), "state": "uploaded"},
+			},
+			contents: map[string]string{"1": "binary CONTENT"},
+		},
+		{
+			name: "download failure",
+			assets: []map[string]any{
+				{"id": 1, "name": "app.tar.gz", "size": len(content), "state": "uploaded"},
+			},
+			checksums: true,
+			expectErr: "failed to download asset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newVerifyServer(t, tt.assets, tt.contents)
+			artifacts := []plugin.Artifact{
+				{Name: "app.tar.gz", Size: int64(len(content)), Checksum: sha256Hex(content)},
+			}
+
+			p := &GitHubPlugin{}
+			errs := p.verifyAssets(context.Background(), client, "owner", "repo", 123, artifacts, tt.checksums)
+
+			if tt.expectErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %v", errs)
+			}
+			if !strings.Contains(errs[0].Error(), tt.expectErr) {
+				t.Errorf("expected error containing %q, got %v", tt.expectErr, errs[0])
+			}
+		})
+	}
+}
+
+// TestVerifyAssetsListFailure tests that a listing failure is reported.
+func TestVerifyAssetsListFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	serverURL, _ := url.Parse(server.URL + "/")
+	client.BaseURL = serverURL
+
+	p := &GitHubPlugin{}
+	errs := p.verifyAssets(context.Background(), client, "owner", "repo", 123, []plugin.Artifact{{Name: "a"}}, false)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "failed to list release assets") {
+		t.Errorf("expected list failure error, got %v", errs)
+	}
+}
+
+// TestValidateAssetVerification tests validation of the verification options.
+func TestValidateAssetVerification(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expectValid bool
+	}{
+		{
+			name:        "verify with checksums",
+			config:      map[string]any{"token": "ghp_test", "verify_assets": true, "verify_checksums": true},
+			expectValid: true,
+		},
+		{
+			name:        "checksums without verify",
+			config:      map[string]any{"token": "ghp_test", "verify_checksums": true},
+			expectValid: false,
+		},
+		{
+			name:        "fail policy",
+			config:      map[string]any{"token": "ghp_test", "asset_failure_policy": "fail"},
+			expectValid: true,
+		},
+		{
+			name:        "unknown policy",
+			config:      map[string]any{"token": "ghp_test", "asset_failure_policy": "ignore"},
+			expectValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &GitHubPlugin{}
+			resp, err := p.Validate(context.Background(), tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Valid != tt.expectValid {
+				t.Errorf("expected Valid=%v, got %v (errors: %v)", tt.expectValid, resp.Valid, resp.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// semverPattern matches a tag made of an optional prefix (e.g. "v" or
+// "component/v") followed by a semantic version.
+var semverPattern = regexp.MustCompile(`^(.*?)(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// semver is a semantic version parsed from a tag name.
+type semver struct {
+	Prefix     string
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// parseVersion parses a tag name such as "v1.4.0-rc.3" into a semver.
+func parseVersion(tag string) (semver, bool) {
+	m := semverPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return semver{}, false
+	}
+
+	major, err1 := strconv.Atoi(m[2])
+	minor, err2 := strconv.Atoi(m[3])
+	patch, err3 := strconv.Atoi(m[4])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return semver{}, false
+	}
+
+	return semver{
+		Prefix:     m[1],
+		Major:      major,
+		Minor:      minor,
+		Patch:      patch,
+		Prerelease: m[5],
+	}, true
+}
+
+// Line returns the release line of the version, e.g. "v1.4".
+func (v semver) Line() string {
+	return fmt.Sprintf("%s%d.%d", v.Prefix, v.Major, v.Minor)
+}
+
+// Core returns the version without prefix or prerelease, e.g. "1.4.0".
+func (v semver) Core() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// compareVersions orders two versions by semantic version precedence,
+// returning -1, 0 or 1. Prefixes are not compared.
+func compareVersions(a, b semver) int {
+	if c := compareInts(a.Major, b.Major); c != 0 {
+		return c
+	}
+	if c := compareInts(a.Minor, b.Minor); c != 0 {
+		return c
+	}
+	if c := compareInts(a.Patch, b.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(a.Prerelease, b.Prerelease)
+}
+
+// comparePrerelease compares prerelease identifiers; a release without a
+// prerelease has higher precedence than one with.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if c := compareInts(an, bn); c != 0 {
+				return c
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(as), len(bs))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
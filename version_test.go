@@ -0,0 +1,60 @@
+package main
+
+import "testing"
+
+// TestParseVersion tests parsing of version tags.
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag      string
+		ok       bool
+		expected semver
+	}{
+		{tag: "v1.4.0", ok: true, expected: semver{Prefix: "v", Major: 1, Minor: 4}},
+		{tag: "1.4.0-rc.3", ok: true, expected: semver{Major: 1, Minor: 4, Prerelease: "rc.3"}},
+		{tag: "api/v2.10.1+build.5", ok: true, expected: semver{Prefix: "api/v", Major: 2, Minor: 10, Patch: 1}},
+		{tag: "nightly", ok: false},
+		{tag: "v1.4", ok: false},
+	}
+
+	for _, tt := range tests {
+		v, ok := parseVersion(tt.tag)
+		if ok != tt.ok {
+			t.Errorf("%s: expected ok=%v, got %v", tt.tag, tt.ok, ok)
+			continue
+		}
+		if ok && v != tt.expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.tag, tt.expected, v)
+		}
+	}
+}
+
+// TestCompareVersions tests semantic version precedence.
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	ordered := []string{
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v1.0.1",
+		"v1.2.0",
+		"v2.0.0",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := parseVersion(ordered[i])
+		b, _ := parseVersion(ordered[i+1])
+		if c := compareVersions(a, b); c != -1 {
+			t.Errorf("expected %s < %s, got %d", ordered[i], ordered[i+1], c)
+		}
+		if c := compareVersions(b, a); c != 1 {
+			t.Errorf("expected %s > %s, got %d", ordered[i+1], ordered[i], c)
+		}
+	}
+}
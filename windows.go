@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Default commit message templates, following the conventions of Scoop
+// buckets and winget-pkgs.
+const (
+	defaultScoopCommitMessage  = "{{.Name}}: Update to version {{.Version}}"
+	defaultWingetCommitMessage = "New version: {{.PackageIdentifier}} version {{.Version}}"
+)
+
+const (
+	// wingetManifestVersion is the winget manifest schema version generated.
+	wingetManifestVersion = "1.6.0"
+	// wingetLocale is the default locale of generated winget manifests.
+	wingetLocale = "en-US"
+)
+
+// wingetIdentifierPattern matches winget package identifiers such as "Publisher.App".
+var wingetIdentifierPattern = regexp.MustCompile(`^[^.\s\\/:*?"<>|]+(\.[^.\s\\/:*?"<>|]+)+$`)
+
+// ScoopConfig configures publishing a Scoop manifest to a bucket.
+type ScoopConfig struct {
+	// Enabled turns on the manifest update in the post-publish hook.
+	Enabled bool `json:"enabled"`
+	// Bucket is the bucket repository and how to commit to it.
+	Bucket RepoTarget `json:"bucket"`
+	// Name is the app name; it defaults to the repository name.
+	Name string `json:"name,omitempty"`
+	// Path is the manifest path; it defaults to bucket/<name>.json.
+	Path        string `json:"path,omitempty"`
+	Description string `json:"description,omitempty"`
+	Homepage    string `json:"homepage,omitempty"`
+	License     string `json:"license,omitempty"`
+	// Binary is the executable inside the zip; it defaults to <name>.exe.
+	Binary string `json:"binary,omitempty"`
+	// CommitMessage is a template for the commit message and pull request title.
+	CommitMessage string `json:"commit_message,omitempty"`
+}
+
+// WingetConfig configures publishing winget manifests.
+type WingetConfig struct {
+	// Enabled turns on the manifest update in the post-publish hook.
+	Enabled bool `json:"enabled"`
+	// Target is the manifests repository and how to commit to it.
+	Target RepoTarget `json:"target"`
+	// PackageIdentifier is the winget package identifier, e.g. "MyOrg.MyApp".
+	PackageIdentifier string `json:"package_identifier,omitempty"`
+	// Publisher defaults to the repository owner, PackageName to the repository name.
+	Publisher        string `json:"publisher,omitempty"`
+	PackageName      string `json:"package_name,omitempty"`
+	License          string `json:"license,omitempty"`
+	ShortDescription string `json:"short_description,omitempty"`
+	Homepage         string `json:"homepage,omitempty"`
+	// Binary is the executable inside the zip; it defaults to <repo>.exe.
+	Binary string `json:"binary,omitempty"`
+	// Path is the manifest directory; it defaults to the winget-pkgs layout
+	// manifests/<letter>/<Publisher>/<Name>/<version>.
+	Path string `json:"path,omitempty"`
+	// CommitMessage is a template for the commit message and pull request title.
+	CommitMessage string `json:"commit_message,omitempty"`
+}
+
+// scoopData is the data available to Scoop commit message templates.
+type scoopData struct {
+	templateData
+	Name string
+}
+
+// wingetData is the data available to winget commit message templates.
+type wingetData struct {
+	templateData
+	PackageIdentifier string
+}
+
+// parseScoopConfig parses the scoop section of the configuration.
+func parseScoopConfig(raw map[string]any) ScoopConfig {
+	parser := helpers.NewConfigParser(raw)
+	return ScoopConfig{
+		Enabled:       parser.GetBool("enabled", false),
+		Bucket:        parseRepoTarget(helpers.NewConfigParser(parser.GetMap("bucket"))),
+		Name:          parser.GetString("name", "", ""),
+		Path:          parser.GetString("path", "", ""),
+		Description:   parser.GetString("description", "", ""),
+		Homepage:      parser.GetString("homepage", "", ""),
+		License:       parser.GetString("license", "", ""),
+		Binary:        parser.GetString("binary", "", ""),
+		CommitMessage: parser.GetString("commit_message", "", defaultScoopCommitMessage),
+	}
+}
+
+// parseWingetConfig parses the winget section of the configuration.
+func parseWingetConfig(raw map[string]any) WingetConfig {
+	parser := helpers.NewConfigParser(raw)
+	return WingetConfig{
+		Enabled:           parser.GetBool("enabled", false),
+		Target:            parseRepoTarget(helpers.NewConfigParser(parser.GetMap("target"))),
+		PackageIdentifier: parser.GetString("package_identifier", "", ""),
+		Publisher:         parser.GetString("publisher", "", ""),
+		PackageName:       parser.GetString("package_name", "", ""),
+		License:           parser.GetString("license", "", ""),
+		ShortDescription:  parser.GetString("short_description", "", ""),
+		Homepage:          parser.GetString("homepage", "", ""),
+		Binary:            parser.GetString("binary", "", ""),
+		Path:              parser.GetString("path", "", ""),
+		CommitMessage:     parser.GetString("commit_message", "", defaultWingetCommitMessage),
+	}
+}
+
+// validateScoopConfig validates the scoop section of the configuration.
+func validateScoopConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	if raw == nil {
+		return
+	}
+
+	cfg := parseScoopConfig(raw)
+	if !cfg.Enabled {
+		return
+	}
+	validateRepoTarget(vb, "scoop.bucket", cfg.Bucket)
+	validateTemplate(vb, "scoop.commit_message", cfg.CommitMessage, scoopData{})
+}
+
+// validateWingetConfig validates the winget section of the configuration.
+func validateWingetConfig(vb *helpers.ValidationBuilder, raw map[string]any) {
+	if raw == nil {
+		return
+	}
+
+	cfg := parseWingetConfig(raw)
+	if !cfg.Enabled {
+		return
+	}
+	validateRepoTarget(vb, "winget.target", cfg.Target)
+	if !wingetIdentifierPattern.MatchString(cfg.PackageIdentifier) {
+		vb.AddError("winget.package_identifier", "package_identifier must look like Publisher.Package")
+	}
+	if cfg.License == "" {
+		vb.AddError("winget.license", "license is required by winget")
+	}
+	if cfg.ShortDescription == "" {
+		vb.AddError("winget.short_description", "short_description is required by winget")
+	}
+	validateTemplate(vb, "winget.commit_message", cfg.CommitMessage, wingetData{})
+}
+
+// windowsAssets returns the Windows zip archives of a release by architecture.
+func windowsAssets(artifacts []plugin.Artifact) (map[string]*packageAsset, error) {
+	assets := make(map[string]*packageAsset)
+	for _, a := range artifacts {
+		osName, arch := classifyAsset(a.Name)
+		if osName != platformWindows || !strings.HasSuffix(strings.ToLower(a.Name), ".zip") {
+			continue
+		}
+		if a.Checksum == "" {
+			return nil, fmt.Errorf("no SHA-256 known for asset %s", a.Name)
+		}
+		assets[arch] = &packageAsset{Name: a.Name, URL: a.Path, SHA256: a.Checksum}
+	}
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("no Windows zip archive among the release assets")
+	}
+	return assets, nil
+}
+
+// scoopArchitectures maps asset architectures to Scoop architecture keys.
+var scoopArchitectures = map[string]string{
+	archAMD64: "64bit",
+	arch386:   "32bit",
+	archARM64: "arm64",
+}
+
+type scoopManifest struct {
+	Version      string                  `json:"version"`
+	Description  string                  `json:"description,omitempty"`
+	Homepage     string                  `json:"homepage,omitempty"`
+	License      string                  `json:"license,omitempty"`
+	Architecture map[string]scoopInstall `json:"architecture"`
+	Bin          string                  `json:"bin"`
+	Checkver     map[string]string       `json:"checkver,omitempty"`
+	Autoupdate   *scoopAutoupdate        `json:"autoupdate,omitempty"`
+}
+
+type scoopInstall struct {
+	URL  string `json:"url"`
+	Hash string `json:"hash,omitempty"`
+}
+
+type scoopAutoupdate struct {
+	Architecture map[string]scoopInstall `json:"architecture"`
+}
+
+// renderScoopManifest renders the Scoop manifest JSON for a release.
+func renderScoopManifest(cfg *Config, sc ScoopConfig, data templateData, assets map[string]*packageAsset) (string, error) {
+	name := sc.Name
+	if name == "" {
+		name = data.Repo
+	}
+	binary := sc.Binary
+	if binary == "" {
+		binary = name + ".exe"
+	}
+	homepage := sc.Homepage
+	if homepage == "" {
+		homepage = fmt.Sprintf("%s/%s/%s", webURL(cfg), data.Owner, data.Repo)
+	}
+
+	m := scoopManifest{
+		Version:      data.Version,
+		Description:  sc.Description,
+		Homepage:     homepage,
+		License:      sc.License,
+		Architecture: make(map[string]scoopInstall),
+		Bin:          binary,
+	}
+	autoupdate := make(map[string]scoopInstall)
+	for arch, a := range assets {
+		key, ok := scoopArchitectures[arch]
+		if !ok {
+			continue
+		}
+		m.Architecture[key] = scoopInstall{URL: a.URL, Hash: a.SHA256}
+		if data.Version != "" && strings.Contains(a.URL, data.Version) {
+			autoupdate[key] = scoopInstall{URL: strings.ReplaceAll(a.URL, data.Version, "$version")}
+		}
+	}
+
+	// Scoop can only check github.com releases for new versions
+	if cfg.BaseURL == "" {
+		m.Checkver = map[string]string{"github": homepage}
+		if len(autoupdate) == len(m.Architecture) {
+			m.Autoupdate = &scoopAutoupdate{Architecture: autoupdate}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(m); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// wingetArchitectures maps asset architectures to winget architectures.
+var wingetArchitectures = map[string]string{
+	archAMD64: "x64",
+	arch386:   "x86",
+	archARM64: "arm64",
+}
+
+// yamlString quotes s as a YAML double-quoted scalar.
+func yamlString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// wingetManifestDir returns the winget-pkgs directory of a package version,
+// e.g. manifests/m/MyOrg/MyApp/1.2.0.
+func wingetManifestDir(identifier, version string) string {
+	parts := append([]string{"manifests", strings.ToLower(identifier[:1])}, strings.Split(identifier, ".")...)
+	return path.Join(append(parts, version)...)
+}
+
+// renderWingetManifests renders the version, installer and default locale
+// manifests of a release, keyed by path.
+func renderWingetManifests(wg WingetConfig, data templateData, assets map[string]*packageAsset) []repoFile {
+	id := wg.PackageIdentifier
+	publisher := wg.Publisher
+	if publisher == "" {
+		publisher = data.Owner
+	}
+	name := wg.PackageName
+	if name == "" {
+		name = data.Repo
+	}
+	binary := wg.Binary
+	if binary == "" {
+		binary = data.Repo + ".exe"
+	}
+	dir := wg.Path
+	if dir == "" {
+		dir = wingetManifestDir(id, data.Version)
+	}
+
+	header := func(b *strings.Builder) {
+		fmt.Fprintf(b, "# Created by relicta-plugin-github\n")
+		fmt.Fprintf(b, "PackageIdentifier: %s\n", yamlString(id))
+		fmt.Fprintf(b, "PackageVersion: %s\n", yamlString(data.Version))
+	}
+
+	var version strings.Builder
+	header(&version)
+	fmt.Fprintf(&version, "DefaultLocale: %s\n", wingetLocale)
+	fmt.Fprintf(&version, "ManifestType: version\nManifestVersion: %s\n", wingetManifestVersion)
+
+	var installer strings.Builder
+	header(&installer)
+	installer.WriteString("InstallerType: zip\nNestedInstallerType: portable\nNestedInstallerFiles:\n")
+	fmt.Fprintf(&installer, "  - RelativeFilePath: %s\n", yamlString(binary))
+	installer.WriteString("Installers:\n")
+	for _, arch := range []string{archAMD64, arch386, archARM64} {
+		a, ok := assets[arch]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&installer, "  - Architecture: %s\n", wingetArchitectures[arch])
+		fmt.Fprintf(&installer, "    InstallerUrl: %s\n", yamlString(a.URL))
+		fmt.Fprintf(&installer, "    InstallerSha256: %s\n", strings.ToUpper(a.SHA256))
+	}
+	fmt.Fprintf(&installer, "ManifestType: installer\nManifestVersion: %s\n", wingetManifestVersion)
+
+	var locale strings.Builder
+	header(&locale)
+	fmt.Fprintf(&locale, "PackageLocale: %s\n", wingetLocale)
+	fmt.Fprintf(&locale, "Publisher: %s\n", yamlString(publisher))
+	fmt.Fprintf(&locale, "PackageName: %s\n", yamlString(name))
+	if wg.Homepage != "" {
+		fmt.Fprintf(&locale, "PackageUrl: %s\n", yamlString(wg.Homepage))
+	}
+	fmt.Fprintf(&locale, "License: %s\n", yamlString(wg.License))
+	fmt.Fprintf(&locale, "ShortDescription: %s\n", yamlString(wg.ShortDescription))
+	if data.ReleaseURL != "" {
+		fmt.Fprintf(&locale, "ReleaseNotesUrl: %s\n", yamlString(data.ReleaseURL))
+	}
+	fmt.Fprintf(&locale, "ManifestType: defaultLocale\nManifestVersion: %s\n", wingetManifestVersion)
+
+	return []repoFile{
+		{Path: path.Join(dir, id+".yaml"), Content: version.String()},
+		{Path: path.Join(dir, id+".installer.yaml"), Content: installer.String()},
+		{Path: path.Join(dir, id+".locale."+wingetLocale+".yaml"), Content: locale.String()},
+	}
+}
+
+// updateScoop renders the Scoop manifest for a published release and commits
+// it to the bucket. In dry-run the manifest is only rendered into the outputs.
+func (p *GitHubPlugin) updateScoop(ctx context.Context, cfg *Config, client *github.Client, data templateData, artifacts []plugin.Artifact, resp *plugin.ExecuteResponse, dryRun bool) error {
+	sc := cfg.Scoop
+
+	assets, err := windowsAssets(artifacts)
+	if err != nil {
+		return err
+	}
+	manifest, err := renderScoopManifest(cfg, sc, data, assets)
+	if err != nil {
+		return fmt.Errorf("failed to render scoop manifest: %w", err)
+	}
+
+	sd := scoopData{templateData: data, Name: sc.Name}
+	if sd.Name == "" {
+		sd.Name = data.Repo
+	}
+	message, err := renderTemplate("scoop.commit_message", sc.CommitMessage, sd)
+	if err != nil {
+		return fmt.Errorf("failed to render commit message: %w", err)
+	}
+
+	manifestPath := sc.Path
+	if manifestPath == "" {
+		manifestPath = fmt.Sprintf("bucket/%s.json", sd.Name)
+	}
+	resp.Outputs["scoop_manifest_path"] = manifestPath
+
+	if dryRun {
+		loggerFromContext(ctx).Info("dry run, skipping scoop update", "bucket", sc.Bucket.Repository, "path", manifestPath)
+		resp.Outputs["scoop_manifest"] = manifest
+		return nil
+	}
+
+	return p.publishFiles(ctx, cfg, client, sc.Bucket, "scoop", resp, repoChange{
+		Files:   []repoFile{{Path: manifestPath, Content: manifest}},
+		Message: message,
+		Branch:  fmt.Sprintf("relicta/%s-%s", sd.Name, data.Tag),
+		Body:    fmt.Sprintf("Updates %s to %s.\n\nRelease: %s", sd.Name, data.Tag, data.ReleaseURL),
+	})
+}
+
+// updateWinget renders the winget manifests for a published release and
+// commits them to the target repository. In dry-run the manifests are only
+// rendered into the outputs.
+func (p *GitHubPlugin) updateWinget(ctx context.Context, cfg *Config, client *github.Client, data templateData, artifacts []plugin.Artifact, resp *plugin.ExecuteResponse, dryRun bool) error {
+	wg := cfg.Winget
+
+	assets, err := windowsAssets(artifacts)
+	if err != nil {
+		return err
+	}
+	files := renderWingetManifests(wg, data, assets)
+
+	message, err := renderTemplate("winget.commit_message", wg.CommitMessage, wingetData{templateData: data, PackageIdentifier: wg.PackageIdentifier})
+	if err != nil {
+		return fmt.Errorf("failed to render commit message: %w", err)
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	resp.Outputs["winget_manifest_paths"] = paths
+
+	if dryRun {
+		loggerFromContext(ctx).Info("dry run, skipping winget update", "target", wg.Target.Repository, "paths", paths)
+		manifests := make(map[string]string, len(files))
+		for _, f := range files {
+			manifests[f.Path] = f.Content
+		}
+		resp.Outputs["winget_manifests"] = manifests
+		return nil
+	}
+
+	return p.publishFiles(ctx, cfg, client, wg.Target, "winget", resp, repoChange{
+		Files:   files,
+		Message: message,
+		Branch:  fmt.Sprintf("relicta/%s-%s", wg.PackageIdentifier, data.Version),
+		Body:    fmt.Sprintf("Adds %s version %s.\n\nRelease: %s", wg.PackageIdentifier, data.Version, data.ReleaseURL),
+	})
+}
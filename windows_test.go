@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+
+	"github.com/relicta-tech/plugin-github/internal/ghfake"
+)
+
+// windowsRequest returns a post-publish request with Windows and Linux assets.
+func windowsRequest(t *testing.T, cfg map[string]any) plugin.ExecuteRequest {
+	t.Helper()
+	dir := writeAssets(t, map[string]string{
+		"app_1.2.0_windows_amd64.zip":  "windows amd64",
+		"app_1.2.0_windows_arm64.zip":  "windows arm64",
+		"app_1.2.0_linux_amd64.tar.gz": "linux amd64",
+	})
+	cfg["assets"] = []any{dir + "/*"}
+	return plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  cfg,
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	}
+}
+
+// TestRenderScoopManifest tests the Scoop manifest for github.com releases.
+func TestRenderScoopManifest(t *testing.T) {
+	assets := map[string]*packageAsset{
+		archAMD64: {Name: "app_1.2.0_windows_amd64.zip", URL: "https://github.com/o/app/releases/download/v1.2.0/app_1.2.0_windows_amd64.zip", SHA256: "abc"},
+	}
+	data := templateData{Version: "1.2.0", Owner: "o", Repo: "app"}
+
+	out, err := renderScoopManifest(&Config{}, ScoopConfig{License: "MIT"}, data, assets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var m scoopManifest
+	if err := json.Unmarshal([]byte(out), &m); err != nil {
+		t.Fatalf("invalid manifest JSON: %v\n%s", err, out)
+	}
+	if m.Version != "1.2.0" || m.Bin != "app.exe" || m.Architecture["64bit"].Hash != "abc" {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+	if m.Checkver["github"] != "https://github.com/o/app" {
+		t.Errorf("unexpected checkver %v", m.Checkver)
+	}
+	if m.Autoupdate == nil || !strings.Contains(m.Autoupdate.Architecture["64bit"].URL, "v$version/app_$version_windows") {
+		t.Errorf("unexpected autoupdate %+v", m.Autoupdate)
+	}
+}
+
+// TestWingetManifestDir tests the winget-pkgs directory layout.
+func TestWingetManifestDir(t *testing.T) {
+	if got := wingetManifestDir("MyOrg.MyApp", "1.2.0"); got != "manifests/m/MyOrg/MyApp/1.2.0" {
+		t.Errorf("unexpected directory %q", got)
+	}
+}
+
+// TestExecuteScoopAndWinget tests committing both manifests against the fake API.
+func TestExecuteScoopAndWinget(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	req := windowsRequest(t, fakeConfig(fake, map[string]any{
+		"scoop": map[string]any{
+			"enabled": true,
+			"bucket":  map[string]any{"repository": "test-owner/scoop-bucket"},
+			"name":    "app",
+		},
+		"winget": map[string]any{
+			"enabled":            true,
+			"target":             map[string]any{"repository": "test-owner/winget-pkgs", "pull_request": true},
+			"package_identifier": "TestOwner.App",
+			"license":            "MIT",
+			"short_description":  "An app",
+			"binary":             "app.exe",
+		},
+	}))
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	manifest, ok := fake.File("test-owner", "scoop-bucket", "main", "bucket/app.json")
+	if !ok || !strings.Contains(manifest, sha256Hex("windows amd64")) || !strings.Contains(manifest, `"arm64"`) {
+		t.Errorf("unexpected scoop manifest:\n%s", manifest)
+	}
+
+	pulls := fake.PullRequests("test-owner", "winget-pkgs")
+	if len(pulls) != 1 || pulls[0].GetTitle() != "New version: TestOwner.App version 1.2.0" {
+		t.Fatalf("unexpected pull requests: %v", pulls)
+	}
+	branch := pulls[0].GetHead().GetRef()
+	dir := "manifests/t/TestOwner/App/1.2.0/"
+	installer, ok := fake.File("test-owner", "winget-pkgs", branch, dir+"TestOwner.App.installer.yaml")
+	if !ok || !strings.Contains(installer, strings.ToUpper(sha256Hex("windows arm64"))) || !strings.Contains(installer, "Architecture: arm64") {
+		t.Errorf("unexpected installer manifest:\n%s", installer)
+	}
+	for _, name := range []string{"TestOwner.App.yaml", "TestOwner.App.locale.en-US.yaml"} {
+		if _, ok := fake.File("test-owner", "winget-pkgs", branch, dir+name); !ok {
+			t.Errorf("expected %s to be committed", name)
+		}
+	}
+}
+
+// TestExecuteWingetFork tests opening the winget pull request from a fork
+// of a repository the token cannot push to.
+func TestExecuteWingetFork(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	req := windowsRequest(t, fakeConfig(fake, map[string]any{
+		"winget": map[string]any{
+			"enabled":            true,
+			"target":             map[string]any{"repository": "microsoft/winget-pkgs", "pull_request": true, "fork": true},
+			"package_identifier": "TestOwner.App",
+			"license":            "MIT",
+			"short_description":  "An app",
+		},
+	}))
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	pulls := fake.PullRequests("microsoft", "winget-pkgs")
+	if len(pulls) != 1 || pulls[0].GetHead().GetLabel() != ghfake.AuthenticatedUser+":relicta/TestOwner.App-1.2.0" {
+		t.Fatalf("unexpected pull requests: %v", pulls)
+	}
+	path := "manifests/t/TestOwner/App/1.2.0/TestOwner.App.yaml"
+	if _, ok := fake.File(ghfake.AuthenticatedUser, "winget-pkgs", "relicta/TestOwner.App-1.2.0", path); !ok {
+		t.Error("expected the manifests in the fork")
+	}
+	if _, ok := fake.File("microsoft", "winget-pkgs", "relicta/TestOwner.App-1.2.0", path); ok {
+		t.Error("expected no branch in the upstream repository")
+	}
+}
+
+// TestExecuteWindowsDryRun tests that dry-run renders both manifests for review.
+func TestExecuteWindowsDryRun(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	req := windowsRequest(t, fakeConfig(fake, map[string]any{
+		"scoop": map[string]any{
+			"enabled": true,
+			"bucket":  map[string]any{"repository": "test-owner/scoop-bucket"},
+		},
+		"winget": map[string]any{
+			"enabled":            true,
+			"target":             map[string]any{"repository": "test-owner/winget-pkgs"},
+			"package_identifier": "TestOwner.App",
+			"license":            "MIT",
+			"short_description":  "An app",
+		},
+	}))
+	req.DryRun = true
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if manifest, _ := resp.Outputs["scoop_manifest"].(string); !strings.Contains(manifest, "releases/download/v1.2.0/app_1.2.0_windows_amd64.zip") {
+		t.Errorf("unexpected scoop manifest %q", manifest)
+	}
+	if manifests, _ := resp.Outputs["winget_manifests"].(map[string]string); len(manifests) != 3 {
+		t.Errorf("expected 3 winget manifests, got %v", resp.Outputs["winget_manifests"])
+	}
+	if n := fake.CountRequests("PUT", "/contents/"); n != 0 {
+		t.Errorf("expected no commits in dry run, got %d", n)
+	}
+}
+
+// TestExecuteScoopWithoutWindowsAssets tests that a missing Windows zip fails the hook but keeps the release.
+func TestExecuteScoopWithoutWindowsAssets(t *testing.T) {
+	fake := newFakeGitHub(t)
+	p := &GitHubPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: fakeConfig(fake, map[string]any{
+			"scoop": map[string]any{"enabled": true, "bucket": map[string]any{"repository": "test-owner/scoop-bucket"}},
+		}),
+		Context: plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "published but scoop update failed") {
+		t.Errorf("expected scoop failure, got %+v", resp)
+	}
+	if resp.Outputs["release_id"] == nil {
+		t.Error("expected release outputs to be kept")
+	}
+}
+
+// TestValidateWinget tests validation of the winget section.
+func TestValidateWinget(t *testing.T) {
+	p := &GitHubPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"token": "ghp_test",
+		"winget": map[string]any{
+			"enabled":            true,
+			"target":             map[string]any{"repository": "o/winget-pkgs", "fork": true},
+			"package_identifier": "NoDots",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 4 {
+		t.Errorf("expected fork, identifier, license and description errors, got %+v", resp.Errors)
+	}
+}